@@ -2,10 +2,14 @@ package main
 
 import (
 	"log"
+	"net/http"
+	"strings"
+	"time"
 
 	"golang_server/internal/config"
 	"golang_server/internal/database"
 	"golang_server/internal/handlers"
+	"golang_server/internal/jobs"
 	"golang_server/internal/middleware"
 	"golang_server/internal/repository"
 	"golang_server/internal/services"
@@ -30,20 +34,97 @@ func main() {
 	}
 
 	// Создаем репозитории
+	transactor := repository.NewTransactor(db)
 	userRepo := repository.NewUserRepository(db)
 	taskRepo := repository.NewTaskRepository(db)
+	projectRepo := repository.NewProjectRepository(db)
+	workflowRepo := repository.NewWorkflowRepository(db)
+	customFieldRepo := repository.NewCustomFieldRepository(db)
+	timeEntryRepo := repository.NewTimeEntryRepository(db)
+	taskRevisionRepo := repository.NewTaskRevisionRepository(db)
+	idempotencyKeyRepo := repository.NewIdempotencyKeyRepository(db)
+	savedViewRepo := repository.NewSavedViewRepository(db)
+	taskTemplateRepo := repository.NewTaskTemplateRepository(db)
+	checklistRepo := repository.NewChecklistRepository(db)
+	watcherRepo := repository.NewTaskWatcherRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	slaPolicyRepo := repository.NewSLAPolicyRepository(db)
+	statsRepo := repository.NewStatsRepository(db)
+	importJobRepo := repository.NewImportJobRepository(db)
+	calendarFeedRepo := repository.NewCalendarFeedRepository(db)
+	calendarObjectRepo := repository.NewCalendarObjectRepository(db)
+	appPasswordRepo := repository.NewAppPasswordRepository(db)
+	dependencyRepo := repository.NewTaskDependencyRepository(db)
+	workingCalendarRepo := repository.NewWorkingCalendarRepository(db)
 
 	// Создаем сервисы
 	authService := services.NewAuthService(userRepo, cfg.JWTSecret)
-	taskService := services.NewTaskService(taskRepo)
+	workflowService := services.NewWorkflowService(transactor, workflowRepo)
+	projectService := services.NewProjectService(projectRepo, workflowService)
+	customFieldService := services.NewCustomFieldService(customFieldRepo, projectRepo, userRepo)
+	workingCalendarService := services.NewWorkingCalendarService(transactor, workingCalendarRepo, userRepo)
+	taskService := services.NewTaskService(transactor, taskRepo, projectRepo, customFieldRepo, timeEntryRepo, taskRevisionRepo, checklistRepo, watcherRepo, notificationRepo, calendarObjectRepo, dependencyRepo, userRepo, slaPolicyRepo, workflowService, customFieldService, workingCalendarService, cfg.BulkMaxItems, []byte(cfg.CursorSecret))
+	timeEntryService := services.NewTimeEntryService(timeEntryRepo, taskRepo)
+	savedViewService := services.NewSavedViewService(transactor, savedViewRepo, projectRepo)
+	taskTemplateService := services.NewTaskTemplateService(taskTemplateRepo, projectRepo, taskService)
+	checklistService := services.NewChecklistService(transactor, checklistRepo, taskRepo)
+	watcherService := services.NewWatcherService(watcherRepo, taskRepo)
+	notificationService := services.NewNotificationService(notificationRepo)
+	slaPolicyService := services.NewSLAPolicyService(slaPolicyRepo, projectRepo)
+	userService := services.NewUserService(userRepo)
+	statsService := services.NewStatsService(statsRepo, projectRepo, userRepo)
+	importService := services.NewImportService(taskService, importJobRepo, cfg.ImportMaxRows, cfg.ImportAsyncRows)
+	calendarService := services.NewCalendarService(calendarFeedRepo, taskRepo, userRepo, workflowService)
+	caldavService := services.NewCalDAVService(taskService, taskRepo, calendarObjectRepo, userRepo, workflowService)
+	appPasswordService := services.NewAppPasswordService(appPasswordRepo, userRepo)
+	idempotencyService := services.NewIdempotencyService(idempotencyKeyRepo, time.Duration(cfg.IdempotencyTTLHours)*time.Hour)
+
+	// Загрузки, прерванные остановкой сервера, уже не завершатся
+	if _, err := importService.FailInterruptedJobs(); err != nil {
+		log.Printf("Failed to mark interrupted import jobs: %v", err)
+	}
+
+	// Запускаем фоновые задачи
+	jobs.StartTrashCleanup(taskService, cfg.TrashRetentionDays)
+	jobs.StartAutoArchive(taskService, cfg.AutoArchiveDays)
+	jobs.StartSLAMonitor(taskService)
+	jobs.StartIdempotencyCleanup(idempotencyService)
 
 	// Создаем обработчики
 	authHandler := handlers.NewAuthHandler(authService)
-	taskHandler := handlers.NewTaskHandler(taskService)
+	taskHandler := handlers.NewTaskHandler(taskService, savedViewService)
+	projectHandler := handlers.NewProjectHandler(projectService)
+	workflowHandler := handlers.NewWorkflowHandler(workflowService)
+	customFieldHandler := handlers.NewCustomFieldHandler(customFieldService)
+	timeEntryHandler := handlers.NewTimeEntryHandler(timeEntryService)
+	savedViewHandler := handlers.NewSavedViewHandler(savedViewService)
+	taskTemplateHandler := handlers.NewTaskTemplateHandler(taskTemplateService)
+	checklistHandler := handlers.NewChecklistHandler(checklistService)
+	watcherHandler := handlers.NewWatcherHandler(watcherService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	slaPolicyHandler := handlers.NewSLAPolicyHandler(slaPolicyService)
+	userHandler := handlers.NewUserHandler(userService)
+	statsHandler := handlers.NewStatsHandler(statsService)
+	importHandler := handlers.NewImportHandler(importService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	caldavHandler := handlers.NewCalDAVHandler(caldavService)
+	appPasswordHandler := handlers.NewAppPasswordHandler(appPasswordService)
+	workingCalendarHandler := handlers.NewWorkingCalendarHandler(workingCalendarService)
 
 	// Настраиваем Gin
 	r := gin.Default()
 
+	// CalDAV для приложений напоминаний с входом по паролю приложения. Маршруты
+	// регистрируются до CORS: клиенты CalDAV узнают возможности сервера запросом
+	// OPTIONS, который CORS завершает без ответа обработчика.
+	r.Handle(http.MethodGet, "/.well-known/caldav", caldavHandler.WellKnown)
+	r.Handle("PROPFIND", "/.well-known/caldav", caldavHandler.WellKnown)
+	caldav := r.Group(strings.TrimSuffix(handlers.CalDAVRoot, "/"))
+	caldav.Use(middleware.AppPasswordAuth(appPasswordService, "CalDAV"))
+	for _, method := range handlers.CalDAVMethods {
+		caldav.Handle(method, "/*path", caldavHandler.Serve)
+	}
+
 	// Применяем middleware
 	r.Use(middleware.CORS())
 
@@ -54,15 +135,119 @@ func main() {
 		auth.POST("/login", authHandler.Login)
 	}
 
+	// Подписка на календарь доступна по секретному токену в адресе
+	calendar := r.Group("/api/calendar")
+	{
+		calendar.GET("/feed/:token", calendarHandler.GetFeed)
+	}
+
 	// Защищенные маршруты
 	api := r.Group("/api")
 	api.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+	api.Use(middleware.Idempotency(idempotencyService))
 	{
 		api.GET("/tasks", taskHandler.GetTasks)
 		api.POST("/tasks", taskHandler.CreateTask)
+		api.POST("/tasks/bulk", taskHandler.BulkTasks)
+		api.GET("/tasks/export", taskHandler.ExportTasks)
+		api.POST("/tasks/import", importHandler.ImportTasks)
+		api.GET("/tasks/import/jobs/:id", importHandler.GetJob)
+		api.GET("/tasks/board", taskHandler.GetBoard)
+		api.GET("/tasks/trash", taskHandler.GetTrash)
+		api.POST("/tasks/archive-completed", taskHandler.ArchiveCompleted)
+		api.DELETE("/tasks/trash", taskHandler.EmptyTrash)
 		api.GET("/tasks/:id", taskHandler.GetTask)
 		api.PUT("/tasks/:id", taskHandler.UpdateTask)
+		api.PATCH("/tasks/:id", taskHandler.PatchTask)
 		api.DELETE("/tasks/:id", taskHandler.DeleteTask)
+		api.POST("/tasks/:id/move", taskHandler.MoveTask)
+		api.POST("/tasks/:id/restore", taskHandler.RestoreTask)
+		api.POST("/tasks/:id/archive", taskHandler.ArchiveTask)
+		api.POST("/tasks/:id/unarchive", taskHandler.UnarchiveTask)
+		api.DELETE("/tasks/:id/permanent", taskHandler.PurgeTask)
+		api.GET("/tasks/:id/history", taskHandler.GetTaskHistory)
+		api.POST("/tasks/:id/history/:revisionId/revert", taskHandler.RevertTask)
+		api.POST("/tasks/:id/timer/start", timeEntryHandler.StartTimer)
+		api.GET("/tasks/:id/time-entries", timeEntryHandler.GetTaskEntries)
+		api.POST("/tasks/:id/time-entries", timeEntryHandler.CreateEntry)
+		api.GET("/tasks/:id/checklist", checklistHandler.GetItems)
+		api.POST("/tasks/:id/checklist", checklistHandler.CreateItem)
+		api.POST("/tasks/:id/checklist/reorder", checklistHandler.ReorderItems)
+		api.PUT("/tasks/:id/checklist/:itemId", checklistHandler.UpdateItem)
+		api.DELETE("/tasks/:id/checklist/:itemId", checklistHandler.DeleteItem)
+		api.POST("/tasks/:id/checklist/:itemId/toggle", checklistHandler.ToggleItem)
+		api.GET("/tasks/:id/watchers", watcherHandler.GetWatchers)
+		api.POST("/tasks/:id/watch", watcherHandler.WatchTask)
+		api.DELETE("/tasks/:id/watch", watcherHandler.UnwatchTask)
+		api.GET("/tasks/:id/dependencies", taskHandler.GetDependencies)
+		api.POST("/tasks/:id/dependencies", taskHandler.AddDependency)
+		api.DELETE("/tasks/:id/dependencies/:dependsOnId", taskHandler.RemoveDependency)
+		api.POST("/tasks/:id/shift", taskHandler.ShiftTask)
+
+		api.GET("/search", taskHandler.SearchTasks)
+
+		api.GET("/views", savedViewHandler.GetViews)
+		api.POST("/views", savedViewHandler.CreateView)
+		api.GET("/views/:id", savedViewHandler.GetView)
+		api.PUT("/views/:id", savedViewHandler.UpdateView)
+		api.DELETE("/views/:id", savedViewHandler.DeleteView)
+
+		api.GET("/templates", taskTemplateHandler.GetTemplates)
+		api.POST("/templates", taskTemplateHandler.CreateTemplate)
+		api.GET("/templates/:id", taskTemplateHandler.GetTemplate)
+		api.PUT("/templates/:id", taskTemplateHandler.UpdateTemplate)
+		api.DELETE("/templates/:id", taskTemplateHandler.DeleteTemplate)
+		api.POST("/templates/:id/instantiate", taskTemplateHandler.InstantiateTemplate)
+
+		api.GET("/notifications", notificationHandler.GetNotifications)
+		api.POST("/notifications/read-all", notificationHandler.MarkAllRead)
+		api.GET("/notifications/preferences", notificationHandler.GetPreferences)
+		api.PUT("/notifications/preferences", notificationHandler.UpdatePreferences)
+		api.POST("/notifications/:id/read", notificationHandler.MarkRead)
+
+		api.GET("/sla-policies", slaPolicyHandler.GetPolicies)
+		api.POST("/sla-policies", slaPolicyHandler.CreatePolicy)
+		api.PUT("/sla-policies/:id", slaPolicyHandler.UpdatePolicy)
+		api.DELETE("/sla-policies/:id", slaPolicyHandler.DeletePolicy)
+
+		api.GET("/profile", userHandler.GetProfile)
+		api.PUT("/profile", userHandler.UpdateProfile)
+		api.GET("/app-passwords", appPasswordHandler.GetPasswords)
+		api.POST("/app-passwords", appPasswordHandler.CreatePassword)
+		api.DELETE("/app-passwords/:id", appPasswordHandler.DeletePassword)
+		api.GET("/working-calendar", workingCalendarHandler.GetCalendar)
+		api.PUT("/working-calendar", workingCalendarHandler.UpdateCalendar)
+		api.POST("/working-calendar/holidays", workingCalendarHandler.AddHoliday)
+		api.POST("/working-calendar/holidays/import", workingCalendarHandler.ImportHolidays)
+		api.DELETE("/working-calendar/holidays/:date", workingCalendarHandler.DeleteHoliday)
+
+		api.GET("/timer", timeEntryHandler.GetTimer)
+		api.POST("/timer/stop", timeEntryHandler.StopTimer)
+		api.PUT("/time-entries/:id", timeEntryHandler.UpdateEntry)
+		api.DELETE("/time-entries/:id", timeEntryHandler.DeleteEntry)
+		api.GET("/reports/time", timeEntryHandler.GetReport)
+		api.GET("/stats", statsHandler.GetStats)
+		api.GET("/timeline", taskHandler.GetTimeline)
+
+		api.GET("/calendar", calendarHandler.GetSubscription)
+		api.POST("/calendar/token", calendarHandler.RegenerateToken)
+		api.DELETE("/calendar/token", calendarHandler.RevokeToken)
+
+		api.GET("/projects", projectHandler.GetProjects)
+		api.POST("/projects", projectHandler.CreateProject)
+		api.GET("/projects/:id", projectHandler.GetProject)
+		api.PUT("/projects/:id", projectHandler.UpdateProject)
+		api.DELETE("/projects/:id", projectHandler.DeleteProject)
+		api.GET("/projects/:id/fields", customFieldHandler.GetFields)
+		api.POST("/projects/:id/fields", customFieldHandler.CreateField)
+		api.PUT("/projects/:id/fields/:fieldId", customFieldHandler.UpdateField)
+		api.DELETE("/projects/:id/fields/:fieldId", customFieldHandler.DeleteField)
+
+		api.GET("/workflows", workflowHandler.GetWorkflows)
+		api.POST("/workflows", workflowHandler.CreateWorkflow)
+		api.GET("/workflows/:id", workflowHandler.GetWorkflow)
+		api.PUT("/workflows/:id", workflowHandler.UpdateWorkflow)
+		api.DELETE("/workflows/:id", workflowHandler.DeleteWorkflow)
 	}
 
 	// Запускаем сервер
@@ -1,6 +1,12 @@
 package config
 
-import "os"
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strconv"
+)
 
 // Config содержит конфигурацию приложения
 type Config struct {
@@ -8,15 +14,48 @@ type Config struct {
 	DatabasePath string
 	JWTSecret    string
 	GinMode      string
+
+	// CursorSecret ключ подписи курсоров пагинации; по умолчанию выводится из JWTSecret,
+	// чтобы подпись курсора нельзя было использовать как подпись токена
+	CursorSecret string
+
+	// TrashRetentionDays срок хранения задач в корзине; 0 отключает автоочистку
+	TrashRetentionDays int
+
+	// AutoArchiveDays через сколько дней после завершения задача помещается в архив; 0 отключает автоархивирование
+	AutoArchiveDays int
+
+	// BulkMaxItems максимальное число задач в одной массовой операции
+	BulkMaxItems int
+
+	// IdempotencyTTLHours срок хранения ключей идемпотентности в часах
+	IdempotencyTTLHours int
+
+	// ImportMaxRows максимальное число записей в загружаемом файле задач
+	ImportMaxRows int
+	// ImportAsyncRows число записей, начиная с которого загрузка выполняется в фоне
+	ImportAsyncRows int
 }
 
 // New создает новую конфигурацию
 func New() *Config {
+	jwtSecret := getEnv("JWT_SECRET", "default-secret-key")
 	return &Config{
 		Port:         getEnv("PORT", "8080"),
 		DatabasePath: getEnv("DB_PATH", "./database.db"),
-		JWTSecret:    getEnv("JWT_SECRET", "default-secret-key"),
+		JWTSecret:    jwtSecret,
 		GinMode:      getEnv("GIN_MODE", "debug"),
+
+		CursorSecret: getEnv("CURSOR_SECRET", deriveSecret(jwtSecret, "cursor")),
+
+		TrashRetentionDays: getEnvInt("TRASH_RETENTION_DAYS", 30),
+		AutoArchiveDays:    getEnvInt("AUTO_ARCHIVE_DAYS", 0),
+		BulkMaxItems:       getEnvInt("BULK_MAX_ITEMS", 100),
+
+		IdempotencyTTLHours: getEnvInt("IDEMPOTENCY_TTL_HOURS", 24),
+
+		ImportMaxRows:   getEnvInt("IMPORT_MAX_ROWS", 10000),
+		ImportAsyncRows: getEnvInt("IMPORT_ASYNC_ROWS", 500),
 	}
 }
 
@@ -26,4 +65,21 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-} 
\ No newline at end of file
+}
+
+// deriveSecret выводит из secret отдельный ключ для назначения purpose (HMAC-SHA256)
+func deriveSecret(secret, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// getEnvInt получает числовую переменную окружения или возвращает значение по умолчанию
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if number, err := strconv.Atoi(value); err == nil {
+			return number
+		}
+	}
+	return defaultValue
+}
\ No newline at end of file
@@ -1,6 +1,9 @@
 package database
 
 import (
+	"strings"
+	"time"
+
 	"golang_server/internal/models"
 
 	"gorm.io/driver/sqlite"
@@ -12,15 +15,28 @@ import (
 
 // Init инициализирует подключение к базе данных
 func Init(databasePath string) (*gorm.DB, error) {
-	// Настройки GORM
+	// Настройки GORM. Время записывается в UTC: драйвер хранит его текстом
+	// со смещением, и сравнение в SQL верно только при едином часовом поясе.
 	config := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+
+	// Параллельные запросы ждут освобождения блокировки, а транзакции сразу берут
+	// блокировку на запись: иначе конкурентные изменения завершаются SQLITE_BUSY.
+	// В режиме WAL чтение не блокируется транзакцией записи, даже когда ее изменения
+	// не помещаются в кэш страниц.
+	dsn := databasePath
+	if !strings.Contains(dsn, "?") {
+		dsn += "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
 	}
 
 	// Подключаемся к SQLite с modernc.org/sqlite драйвером
 	db, err := gorm.Open(sqlite.Dialector{
 		DriverName: "sqlite",
-		DSN:        databasePath,
+		DSN:        dsn,
 	}, config)
 	if err != nil {
 		return nil, err
@@ -30,11 +46,39 @@ func Init(databasePath string) (*gorm.DB, error) {
 	err = db.AutoMigrate(
 		&models.User{},
 		&models.Task{},
+		&models.Workflow{},
+		&models.WorkflowStatus{},
+		&models.WorkflowTransition{},
+		&models.Project{},
+		&models.CustomField{},
+		&models.CustomFieldValue{},
+		&models.TimeEntry{},
+		&models.TaskRevision{},
+		&models.IdempotencyKey{},
+		&models.SavedView{},
+		&models.TaskTemplate{},
+		&models.ChecklistItem{},
+		&models.TaskWatcher{},
+		&models.Notification{},
+		&models.NotificationPreference{},
+		&models.SLAPolicy{},
+		&models.ImportJob{},
+		&models.CalendarFeed{},
+		&models.CalendarObject{},
+		&models.AppPassword{},
+		&models.TaskDependency{},
+		&models.WorkingCalendar{},
+		&models.Holiday{},
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// Выполняем миграции данных
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
 
@@ -0,0 +1,298 @@
+package database
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/pkg/rank"
+
+	"gorm.io/gorm"
+)
+
+// migrate выполняет миграции данных, которые не покрывает AutoMigrate.
+// Миграции затрагивают и задачи в корзине, чтобы их можно было восстановить.
+func migrate(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := backfillTaskPositions(tx); err != nil {
+			return err
+		}
+		if err := backfillDefaultWorkflows(tx); err != nil {
+			return err
+		}
+		if err := createRunningTimerIndex(tx); err != nil {
+			return err
+		}
+		if err := createDefaultViewIndex(tx); err != nil {
+			return err
+		}
+		if err := createDefaultWorkflowIndex(tx); err != nil {
+			return err
+		}
+		if err := runOnce(tx, "normalize_times_to_utc", normalizeTimesToUTC); err != nil {
+			return err
+		}
+		return createTaskSearchIndex(tx)
+	})
+}
+
+// runOnce выполняет миграцию name, если она еще не отмечена в таблице schema_migrations,
+// и отмечает ее выполненной в той же транзакции. Так выполняются миграции, которые
+// просматривают все данные и не нужны при каждом запуске.
+func runOnce(tx *gorm.DB, name string, migration func(tx *gorm.DB) error) error {
+	err := tx.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (name TEXT PRIMARY KEY, applied_at DATETIME NOT NULL)").Error
+	if err != nil {
+		return err
+	}
+
+	var applied int64
+	if err := tx.Raw("SELECT COUNT(*) FROM schema_migrations WHERE name = ?", name).Scan(&applied).Error; err != nil {
+		return err
+	}
+	if applied > 0 {
+		return nil
+	}
+
+	if err := migration(tx); err != nil {
+		return err
+	}
+	return tx.Exec("INSERT INTO schema_migrations (name, applied_at) VALUES (?, ?)", name, time.Now().UTC()).Error
+}
+
+// backfillTaskPositions назначает позиции на доске задачам, созданным до их появления.
+// Задачи без позиции встают в конец своей колонки в порядке создания.
+func backfillTaskPositions(tx *gorm.DB) error {
+	var tasks []models.Task
+	err := tx.Unscoped().Select("id", "user_id", "status").
+		Where("position = '' OR position IS NULL").
+		Order("created_at ASC, id ASC").
+		Find(&tasks).Error
+	if err != nil {
+		return err
+	}
+
+	type column struct {
+		userID uint
+		status models.TaskStatus
+	}
+	last := make(map[column]string)
+
+	for _, task := range tasks {
+		key := column{userID: task.UserID, status: task.Status}
+		position, ok := last[key]
+		if !ok {
+			err := tx.Unscoped().Model(&models.Task{}).
+				Where("user_id = ? AND status = ? AND position <> ''", task.UserID, task.Status).
+				Select("COALESCE(MAX(position), '')").
+				Scan(&position).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		position = rank.After(position)
+		last[key] = position
+
+		if err := tx.Unscoped().Model(&models.Task{}).Where("id = ?", task.ID).UpdateColumn("position", position).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backfillDefaultWorkflows создает процесс по умолчанию для пользователей без него
+// и привязывает к нему задачи, созданные до появления рабочих процессов.
+// Для задач в статусах категории done момент завершения берется из даты последнего изменения.
+func backfillDefaultWorkflows(tx *gorm.DB) error {
+	var userIDs []uint
+	err := tx.Unscoped().Model(&models.Task{}).
+		Where("workflow_id IS NULL").
+		Distinct().
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		var workflow models.Workflow
+		err := tx.Where("user_id = ? AND is_default = ?", userID, true).First(&workflow).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			workflow = *models.DefaultWorkflow(userID)
+			err = tx.Create(&workflow).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Model(&models.Task{}).
+			Where("user_id = ? AND workflow_id IS NULL AND project_id IS NULL", userID).
+			UpdateColumn("workflow_id", workflow.ID).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Unscoped().Model(&models.Task{}).
+		Where("completed_at IS NULL").
+		Where("EXISTS (SELECT 1 FROM workflow_statuses ws WHERE ws.workflow_id = tasks.workflow_id AND ws.key = tasks.status AND ws.category = ?)", models.StatusCategoryDone).
+		UpdateColumn("completed_at", gorm.Expr("updated_at")).Error
+}
+
+// createRunningTimerIndex создает частичный уникальный индекс,
+// который не дает пользователю запустить больше одного таймера
+func createRunningTimerIndex(tx *gorm.DB) error {
+	return tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries (user_id) WHERE ended_at IS NULL").Error
+}
+
+// createDefaultViewIndex создает частичный уникальный индекс,
+// который не дает пользователю отметить больше одного представления по умолчанию
+func createDefaultViewIndex(tx *gorm.DB) error {
+	return tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_views_default ON saved_views (user_id) WHERE is_default").Error
+}
+
+// createDefaultWorkflowIndex создает частичный уникальный индекс, который не дает
+// одновременным запросам создать пользователю второй процесс по умолчанию.
+// Если такие процессы уже появились, отметка остается только у первого из них.
+func createDefaultWorkflowIndex(tx *gorm.DB) error {
+	err := tx.Exec("UPDATE workflows SET is_default = false WHERE is_default AND id <> " +
+		"(SELECT MIN(first.id) FROM workflows AS first WHERE first.user_id = workflows.user_id AND first.is_default)").Error
+	if err != nil {
+		return err
+	}
+	return tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_workflows_default ON workflows (user_id) WHERE is_default").Error
+}
+
+// createTaskSearchIndex создает полнотекстовый индекс FTS5 по названию и описанию задач.
+// Индекс хранит только токены, а текст читает из таблицы tasks (external content),
+// и поддерживается в актуальном состоянии триггерами. Задачи, созданные до появления
+// индекса, индексируются при его создании.
+func createTaskSearchIndex(tx *gorm.DB) error {
+	var exists int64
+	err := tx.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'tasks_fts'").Scan(&exists).Error
+	if err != nil {
+		return err
+	}
+
+	statements := []string{
+		`CREATE VIRTUAL TABLE IF NOT EXISTS tasks_fts USING fts5(
+			title, description,
+			content='tasks', content_rowid='id',
+			tokenize='unicode61 remove_diacritics 2'
+		)`,
+		`CREATE TRIGGER IF NOT EXISTS tasks_fts_insert AFTER INSERT ON tasks BEGIN
+			INSERT INTO tasks_fts (rowid, title, description) VALUES (new.id, new.title, COALESCE(new.description, ''));
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS tasks_fts_delete AFTER DELETE ON tasks BEGIN
+			INSERT INTO tasks_fts (tasks_fts, rowid, title, description) VALUES ('delete', old.id, old.title, COALESCE(old.description, ''));
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS tasks_fts_update AFTER UPDATE OF title, description ON tasks BEGIN
+			INSERT INTO tasks_fts (tasks_fts, rowid, title, description) VALUES ('delete', old.id, old.title, COALESCE(old.description, ''));
+			INSERT INTO tasks_fts (rowid, title, description) VALUES (new.id, new.title, COALESCE(new.description, ''));
+		END`,
+	}
+	for _, statement := range statements {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	if exists > 0 {
+		return nil
+	}
+	return tx.Exec("INSERT INTO tasks_fts (tasks_fts) VALUES ('rebuild')").Error
+}
+
+// normalizeTimesToUTC переводит в UTC значения столбцов datetime, записанные
+// с другим смещением. Драйвер хранит время текстом вида "2006-01-02 15:04:05 -0700 MST",
+// и условия вроде end_date < ? сравнивают строки, поэтому все значения должны быть в UTC.
+// Новые значения записываются в UTC, поэтому миграция выполняется один раз.
+func normalizeTimesToUTC(tx *gorm.DB) error {
+	var tables []string
+	err := tx.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND sql NOT LIKE 'CREATE VIRTUAL TABLE%'").
+		Scan(&tables).Error
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		var columns []struct {
+			Name string
+			Type string
+		}
+		if err := tx.Raw("SELECT name, type FROM pragma_table_info(?)", table).Scan(&columns).Error; err != nil {
+			return err
+		}
+
+		for _, column := range columns {
+			if !strings.EqualFold(column.Type, "datetime") {
+				continue
+			}
+			if err := normalizeColumnToUTC(tx, table, column.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeColumnToUTC переводит в UTC значения одного столбца
+func normalizeColumnToUTC(tx *gorm.DB, table, column string) error {
+	type value struct {
+		rowID int64
+		time  time.Time
+	}
+
+	quotedTable, quotedColumn := tx.Statement.Quote(table), tx.Statement.Quote(column)
+	rows, err := tx.Raw("SELECT rowid, CAST(" + quotedColumn + " AS TEXT) FROM " + quotedTable +
+		" WHERE " + quotedColumn + " IS NOT NULL AND " + quotedColumn + " NOT LIKE '% +0000 UTC%'").Rows()
+	if err != nil {
+		return err
+	}
+	var values []value
+	for rows.Next() {
+		var rowID int64
+		var text string
+		if err := rows.Scan(&rowID, &text); err != nil {
+			rows.Close()
+			return err
+		}
+		if t, ok := parseStoredTime(text); ok {
+			values = append(values, value{rowID: rowID, time: t})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, v := range values {
+		err := tx.Exec("UPDATE "+quotedTable+" SET "+quotedColumn+" = ? WHERE rowid = ?", v.time.UTC(), v.rowID).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseStoredTime разбирает время, записанное драйвером как time.Time.String().
+// Для смещения без названия пояса String() повторяет его вместо названия ("+0300 +0300").
+func parseStoredTime(text string) (time.Time, bool) {
+	text, _, _ = strings.Cut(text, " m=")
+	fields := strings.Fields(text)
+	if len(fields) == 4 {
+		if t, err := time.Parse("2006-01-02 15:04:05.999999999 -0700", strings.Join(fields[:3], " ")); err == nil {
+			return t, true
+		}
+	}
+	for _, layout := range []string{
+		"2006-01-02 15:04:05.999999999-07:00",
+		"2006-01-02T15:04:05.999999999-07:00",
+	} {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AppPasswordHandler обработчик для паролей приложений
+type AppPasswordHandler struct {
+	appPasswordService services.AppPasswordService
+}
+
+// NewAppPasswordHandler создает новый обработчик паролей приложений
+func NewAppPasswordHandler(appPasswordService services.AppPasswordService) *AppPasswordHandler {
+	return &AppPasswordHandler{
+		appPasswordService: appPasswordService,
+	}
+}
+
+// GetPasswords получает пароли приложений пользователя
+func (h *AppPasswordHandler) GetPasswords(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	passwords, err := h.appPasswordService.GetPasswords(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get app passwords",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"app_passwords": passwords,
+	})
+}
+
+// CreatePassword создает пароль приложения. Токен показывается только в этом ответе.
+func (h *AppPasswordHandler) CreatePassword(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var req models.CreateAppPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	password, err := h.appPasswordService.CreatePassword(userID, req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid name") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to create app password",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "App password created successfully",
+		"app_password": password,
+	})
+}
+
+// DeletePassword отзывает пароль приложения
+func (h *AppPasswordHandler) DeletePassword(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid app password ID",
+		})
+		return
+	}
+
+	if err := h.appPasswordService.DeletePassword(userID, uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "app password not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to delete app password",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "App password deleted successfully",
+	})
+}
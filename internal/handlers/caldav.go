@@ -0,0 +1,504 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+	"golang_server/pkg/dav"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Адреса ресурсов CalDAV: принципал пользователя, домашняя коллекция календарей
+// и единственная коллекция дел с задачами
+const (
+	CalDAVRoot           = "/caldav/"
+	caldavPrincipalPath  = CalDAVRoot + "principal/"
+	caldavHomePath       = CalDAVRoot + "calendars/"
+	caldavCollectionPath = caldavHomePath + "tasks/"
+)
+
+// maxCalDAVBodySize максимальный размер тела запроса CalDAV
+const maxCalDAVBodySize = 1 << 20
+
+// CalDAVMethods методы HTTP, которые обслуживает CalDAV
+var CalDAVMethods = []string{"OPTIONS", "PROPFIND", "REPORT", "GET", "HEAD", "PUT", "DELETE"}
+
+// caldavResource вид ресурса CalDAV по адресу запроса
+type caldavResource int
+
+const (
+	caldavUnknown caldavResource = iota
+	caldavRootResource
+	caldavPrincipal
+	caldavHome
+	caldavCollection
+	caldavObject
+)
+
+// CalDAVHandler обработчик протокола CalDAV для синхронизации задач с приложениями
+// напоминаний и календарей
+type CalDAVHandler struct {
+	caldavService services.CalDAVService
+}
+
+// NewCalDAVHandler создает новый обработчик CalDAV
+func NewCalDAVHandler(caldavService services.CalDAVService) *CalDAVHandler {
+	return &CalDAVHandler{
+		caldavService: caldavService,
+	}
+}
+
+// WellKnown направляет клиента, нашедшего сервер по /.well-known/caldav (RFC 6764), к корню CalDAV
+func (h *CalDAVHandler) WellKnown(c *gin.Context) {
+	c.Redirect(http.StatusMovedPermanently, CalDAVRoot)
+}
+
+// Serve обрабатывает запрос CalDAV к ресурсу по пути *path
+func (h *CalDAVHandler) Serve(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	resource, name := parseCalDAVPath(c.Param("path"))
+	if resource == caldavUnknown {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCalDAVBodySize)
+
+	switch c.Request.Method {
+	case "OPTIONS":
+		c.Header("DAV", "1, 3, calendar-access")
+		c.Header("Allow", strings.Join(CalDAVMethods, ", "))
+		c.Status(http.StatusOK)
+	case "PROPFIND":
+		h.propfind(c, userID, resource, name)
+	case "REPORT":
+		h.report(c, userID, resource)
+	case http.MethodGet, http.MethodHead:
+		h.get(c, userID, resource, name)
+	case http.MethodPut:
+		h.put(c, userID, resource, name)
+	case http.MethodDelete:
+		h.delete(c, userID, resource, name)
+	default:
+		c.Header("Allow", strings.Join(CalDAVMethods, ", "))
+		c.Status(http.StatusMethodNotAllowed)
+	}
+}
+
+// propfind возвращает свойства ресурса, а при Depth: 1 — и его дочерних ресурсов
+func (h *CalDAVHandler) propfind(c *gin.Context, userID uint, resource caldavResource, name string) {
+	propfind, err := dav.ParsePropfind(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+	// Depth: infinity не поддерживается и обрабатывается как 1
+	depth := c.GetHeader("Depth") != "0"
+
+	email, _ := middleware.GetUserEmail(c)
+	var responses []dav.Response
+	switch resource {
+	case caldavRootResource:
+		responses = append(responses, propfindResponse(CalDAVRoot, rootProperties(), propfind))
+		if depth {
+			responses = append(responses, propfindResponse(caldavPrincipalPath, principalProperties(email), propfind))
+			responses = append(responses, propfindResponse(caldavHomePath, homeProperties(), propfind))
+		}
+	case caldavPrincipal:
+		responses = append(responses, propfindResponse(caldavPrincipalPath, principalProperties(email), propfind))
+	case caldavHome:
+		responses = append(responses, propfindResponse(caldavHomePath, homeProperties(), propfind))
+		if depth {
+			collection, err := h.caldavService.GetCollection(userID)
+			if err != nil {
+				caldavError(c, err)
+				return
+			}
+			responses = append(responses, propfindResponse(caldavCollectionPath, collectionProperties(collection), propfind))
+		}
+	case caldavCollection:
+		collection, err := h.caldavService.GetCollection(userID)
+		if err != nil {
+			caldavError(c, err)
+			return
+		}
+		responses = append(responses, propfindResponse(caldavCollectionPath, collectionProperties(collection), propfind))
+		if depth {
+			objects, err := h.caldavService.GetObjects(userID, models.CalDAVFilter{})
+			if err != nil {
+				caldavError(c, err)
+				return
+			}
+			for i := range objects {
+				responses = append(responses, propfindResponse(caldavObjectHref(objects[i].Name), objectProperties(&objects[i], false), propfind))
+			}
+		}
+	case caldavObject:
+		object, err := h.caldavService.GetObject(userID, name)
+		if err != nil {
+			caldavError(c, err)
+			return
+		}
+		responses = append(responses, propfindResponse(caldavObjectHref(object.Name), objectProperties(object, false), propfind))
+	}
+
+	writeMultistatus(c, responses)
+}
+
+// report выполняет calendar-query или calendar-multiget над коллекцией дел
+func (h *CalDAVHandler) report(c *gin.Context, userID uint, resource caldavResource) {
+	report, err := dav.ParseReport(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if resource != caldavCollection || (report.Name != dav.CalDAVName("calendar-query") && report.Name != dav.CalDAVName("calendar-multiget")) {
+		writeDAVError(c, http.StatusForbidden, dav.Name("supported-report"))
+		return
+	}
+
+	filter := models.CalDAVFilter{Component: report.Component, Start: report.Start, End: report.End}
+	if report.Name == dav.CalDAVName("calendar-multiget") {
+		filter = models.CalDAVFilter{}
+	}
+	objects, err := h.caldavService.GetObjects(userID, filter)
+	if err != nil {
+		caldavError(c, err)
+		return
+	}
+
+	propfind := &dav.Propfind{AllProp: report.AllProp, Props: report.Props}
+	var responses []dav.Response
+	if report.Name == dav.CalDAVName("calendar-query") {
+		for i := range objects {
+			responses = append(responses, propfindResponse(caldavObjectHref(objects[i].Name), objectProperties(&objects[i], true), propfind))
+		}
+	} else {
+		byName := make(map[string]*models.CalDAVObject, len(objects))
+		for i := range objects {
+			byName[objects[i].Name] = &objects[i]
+		}
+		for _, href := range report.Hrefs {
+			object, ok := byName[caldavObjectName(href)]
+			if !ok {
+				responses = append(responses, dav.Response{Href: href, Status: http.StatusNotFound})
+				continue
+			}
+			responses = append(responses, propfindResponse(href, objectProperties(object, true), propfind))
+		}
+	}
+
+	writeMultistatus(c, responses)
+}
+
+// get отдает задачу ресурса в формате iCalendar
+func (h *CalDAVHandler) get(c *gin.Context, userID uint, resource caldavResource, name string) {
+	if resource != caldavObject {
+		c.Header("Allow", "OPTIONS, PROPFIND, REPORT")
+		c.Status(http.StatusMethodNotAllowed)
+		return
+	}
+
+	object, err := h.caldavService.GetObject(userID, name)
+	if err != nil {
+		caldavError(c, err)
+		return
+	}
+
+	etag := taskETag(object.Version)
+	c.Header("ETag", etag)
+	if header := c.GetHeader("If-None-Match"); header != "" && ifNoneMatch(header, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", object.Data)
+}
+
+// put создает или заменяет задачу ресурса. Сервер меняет представление дела,
+// поэтому ETag в ответе не возвращается и клиент перечитывает ресурс (RFC 4791, 5.3.4).
+func (h *CalDAVHandler) put(c *gin.Context, userID uint, resource caldavResource, name string) {
+	if resource != caldavObject {
+		c.Header("Allow", "OPTIONS, PROPFIND, REPORT")
+		c.Status(http.StatusMethodNotAllowed)
+		return
+	}
+
+	ifMatch := c.GetHeader("If-Match")
+	expectedVersion, err := parseIfMatch(ifMatch, h.objectEntityTag(userID, name))
+	if err != nil {
+		ifMatchFailed(c, err)
+		return
+	}
+	mustExist := strings.TrimSpace(ifMatch) == "*"
+	mustNotExist := strings.TrimSpace(c.GetHeader("If-None-Match")) == "*"
+
+	created, err := h.caldavService.PutObject(userID, name, c.Request.Body, expectedVersion, mustExist, mustNotExist)
+	if err != nil {
+		caldavError(c, err)
+		return
+	}
+
+	if created {
+		c.Header("Location", caldavObjectHref(name))
+		c.Status(http.StatusCreated)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// delete перемещает задачу ресурса в корзину
+func (h *CalDAVHandler) delete(c *gin.Context, userID uint, resource caldavResource, name string) {
+	if resource != caldavObject {
+		c.Header("Allow", "OPTIONS, PROPFIND, REPORT")
+		c.Status(http.StatusMethodNotAllowed)
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"), h.objectEntityTag(userID, name))
+	if err != nil {
+		ifMatchFailed(c, err)
+		return
+	}
+
+	if err := h.caldavService.DeleteObject(userID, name, expectedVersion); err != nil {
+		caldavError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// objectEntityTag возвращает функцию, читающую текущий ETag и версию ресурса для parseIfMatch
+func (h *CalDAVHandler) objectEntityTag(userID uint, name string) func() (string, int, error) {
+	return func() (string, int, error) {
+		object, err := h.caldavService.GetObject(userID, name)
+		if err != nil {
+			return "", 0, err
+		}
+		return taskETag(object.Version), object.Version, nil
+	}
+}
+
+// parseCalDAVPath определяет ресурс по пути внутри корня CalDAV
+func parseCalDAVPath(path string) (caldavResource, string) {
+	switch strings.Trim(path, "/") {
+	case "":
+		return caldavRootResource, ""
+	case "principal":
+		return caldavPrincipal, ""
+	case "calendars":
+		return caldavHome, ""
+	case "calendars/tasks":
+		return caldavCollection, ""
+	}
+
+	name, ok := strings.CutPrefix(strings.TrimPrefix(path, "/"), "calendars/tasks/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return caldavUnknown, ""
+	}
+	return caldavObject, name
+}
+
+// caldavObjectHref возвращает адрес ресурса коллекции
+func caldavObjectHref(name string) string {
+	return caldavCollectionPath + url.PathEscape(name)
+}
+
+// caldavObjectName извлекает имя ресурса из адреса, в том числе абсолютного
+func caldavObjectName(href string) string {
+	parsed, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	name, ok := strings.CutPrefix(parsed.Path, caldavCollectionPath)
+	if !ok {
+		return ""
+	}
+	return name
+}
+
+// rootProperties свойства корня CalDAV: по ним клиент находит принципала
+func rootProperties() []dav.Property {
+	return []dav.Property{
+		{Name: dav.Name("resourcetype"), Inner: dav.Element(dav.Name("collection"), "")},
+		dav.HrefProperty(dav.Name("current-user-principal"), caldavPrincipalPath),
+		caldavPrivileges(false),
+	}
+}
+
+// principalProperties свойства принципала пользователя
+func principalProperties(email string) []dav.Property {
+	return []dav.Property{
+		{Name: dav.Name("resourcetype"), Inner: dav.Element(dav.Name("collection"), "") + dav.Element(dav.Name("principal"), "")},
+		dav.TextProperty(dav.Name("displayname"), email),
+		dav.HrefProperty(dav.Name("current-user-principal"), caldavPrincipalPath),
+		dav.HrefProperty(dav.Name("principal-URL"), caldavPrincipalPath),
+		dav.HrefProperty(dav.CalDAVName("calendar-home-set"), caldavHomePath),
+		dav.HrefProperty(dav.CalDAVName("calendar-user-address-set"), "mailto:"+email),
+		caldavPrivileges(false),
+	}
+}
+
+// homeProperties свойства домашней коллекции календарей
+func homeProperties() []dav.Property {
+	return []dav.Property{
+		{Name: dav.Name("resourcetype"), Inner: dav.Element(dav.Name("collection"), "")},
+		dav.HrefProperty(dav.Name("current-user-principal"), caldavPrincipalPath),
+		dav.HrefProperty(dav.Name("owner"), caldavPrincipalPath),
+		caldavPrivileges(false),
+	}
+}
+
+// collectionProperties свойства коллекции дел
+func collectionProperties(collection *models.CalDAVCollection) []dav.Property {
+	return []dav.Property{
+		{Name: dav.Name("resourcetype"), Inner: dav.Element(dav.Name("collection"), "") + dav.Element(dav.CalDAVName("calendar"), "")},
+		dav.TextProperty(dav.Name("displayname"), collection.DisplayName),
+		dav.HrefProperty(dav.Name("current-user-principal"), caldavPrincipalPath),
+		dav.HrefProperty(dav.Name("owner"), caldavPrincipalPath),
+		{Name: dav.CalDAVName("supported-calendar-component-set"), Inner: `<c:comp name="VTODO"/>`},
+		{Name: dav.CalDAVName("supported-calendar-data"), Inner: `<c:calendar-data content-type="text/calendar" version="2.0"/>`},
+		{Name: dav.Name("supported-report-set"), Inner: supportedReport("calendar-query") + supportedReport("calendar-multiget")},
+		dav.TextProperty(xml.Name{Space: dav.NamespaceCalendarServer, Local: "getctag"}, collection.CTag),
+		dav.TextProperty(dav.Name("getetag"), `"`+collection.CTag+`"`),
+		caldavPrivileges(true),
+	}
+}
+
+// objectProperties свойства ресурса с задачей; calendar-data выводится только в отчетах
+func objectProperties(object *models.CalDAVObject, withData bool) []dav.Property {
+	properties := []dav.Property{
+		{Name: dav.Name("resourcetype")},
+		dav.TextProperty(dav.Name("getetag"), taskETag(object.Version)),
+		dav.TextProperty(dav.Name("getcontenttype"), "text/calendar; charset=utf-8; component=VTODO"),
+	}
+	if withData {
+		properties = append(properties, dav.TextProperty(dav.CalDAVName("calendar-data"), string(object.Data)))
+	}
+	return properties
+}
+
+// supportedReport описывает поддерживаемый отчет CalDAV
+func supportedReport(name string) string {
+	return dav.Element(dav.Name("supported-report"), dav.Element(dav.Name("report"), dav.Element(dav.CalDAVName(name), "")))
+}
+
+// caldavPrivileges перечисляет права пользователя; в коллекции дел он может создавать,
+// изменять и удалять ресурсы
+func caldavPrivileges(writable bool) dav.Property {
+	privileges := []string{"read", "read-current-user-privilege-set"}
+	if writable {
+		privileges = append(privileges, "write", "write-content", "bind", "unbind")
+	}
+	var inner strings.Builder
+	for _, privilege := range privileges {
+		inner.WriteString(dav.Element(dav.Name("privilege"), dav.Element(dav.Name(privilege), "")))
+	}
+	return dav.Property{Name: dav.Name("current-user-privilege-set"), Inner: inner.String()}
+}
+
+// propfindResponse отбирает запрошенные свойства ресурса: найденные и ненайденные
+func propfindResponse(href string, properties []dav.Property, propfind *dav.Propfind) dav.Response {
+	response := dav.Response{Href: href}
+	switch {
+	case propfind.PropName:
+		for _, property := range properties {
+			response.Props = append(response.Props, dav.Property{Name: property.Name})
+		}
+	case propfind.AllProp:
+		response.Props = properties
+	default:
+		for _, name := range propfind.Props {
+			found := false
+			for _, property := range properties {
+				if property.Name == name {
+					response.Props = append(response.Props, property)
+					found = true
+					break
+				}
+			}
+			if !found {
+				response.NotFound = append(response.NotFound, name)
+			}
+		}
+	}
+	return response
+}
+
+// writeMultistatus отправляет ответ 207 Multi-Status
+func writeMultistatus(c *gin.Context, responses []dav.Response) {
+	var body bytes.Buffer
+	if err := dav.WriteMultistatus(&body, responses); err != nil {
+		caldavError(c, err)
+		return
+	}
+	c.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", body.Bytes())
+}
+
+// writeDAVError отправляет ответ с телом <error> о нарушенном предусловии
+func writeDAVError(c *gin.Context, status int, precondition xml.Name) {
+	var body bytes.Buffer
+	dav.WriteError(&body, precondition)
+	c.Data(status, "application/xml; charset=utf-8", body.Bytes())
+}
+
+// caldavError отправляет ответ с ошибкой CalDAV. Нарушения предусловий CalDAV
+// описываются телом <error>, остальные ошибки — как в API задач.
+func caldavError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		writeDAVError(c, http.StatusRequestEntityTooLarge, dav.CalDAVName("max-resource-size"))
+		return
+	case strings.HasPrefix(err.Error(), "invalid calendar data"):
+		writeDAVError(c, http.StatusForbidden, dav.CalDAVName("valid-calendar-data"))
+		return
+	case strings.HasPrefix(err.Error(), "unsupported calendar component"):
+		writeDAVError(c, http.StatusForbidden, dav.CalDAVName("supported-calendar-component"))
+		return
+	}
+
+	status := http.StatusInternalServerError
+	switch {
+	case err.Error() == "calendar object not found" || err.Error() == "task not found":
+		status = http.StatusNotFound
+	case err.Error() == "access denied":
+		status = http.StatusForbidden
+	case err.Error() == "version conflict":
+		status = http.StatusPreconditionFailed
+	case err.Error() == "required checklist items are not completed" || err.Error() == "status transition not allowed":
+		status = http.StatusConflict
+	case strings.HasPrefix(err.Error(), "invalid calendar object name") || err.Error() == "invalid priority" || err.Error() == "end date cannot be before start date" || strings.HasPrefix(err.Error(), "invalid tags"):
+		status = http.StatusBadRequest
+	}
+	if status == http.StatusInternalServerError {
+		log.Printf("CalDAV request failed: %v", err)
+	}
+	c.JSON(status, gin.H{
+		"error":   "CalDAV request failed",
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+	"golang_server/pkg/ical"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarHandler обработчик для календаря задач в формате iCalendar
+type CalendarHandler struct {
+	calendarService services.CalendarService
+}
+
+// NewCalendarHandler создает новый обработчик календаря задач
+func NewCalendarHandler(calendarService services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// GetSubscription получает состояние подписки на календарь
+func (h *CalendarHandler) GetSubscription(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	subscription, err := h.calendarService.GetSubscription(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get calendar subscription",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"calendar": subscription,
+	})
+}
+
+// RegenerateToken создает новый секретный адрес подписки на календарь
+func (h *CalendarHandler) RegenerateToken(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	subscription, err := h.calendarService.RegenerateToken(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create calendar token",
+			"message": err.Error(),
+		})
+		return
+	}
+	subscription.URL = calendarFeedURL(c, subscription.Token)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Calendar token created successfully",
+		"calendar": subscription,
+	})
+}
+
+// RevokeToken отключает подписку на календарь
+func (h *CalendarHandler) RevokeToken(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	if err := h.calendarService.RevokeToken(userID); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "calendar feed not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to revoke calendar token",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Calendar token revoked successfully",
+	})
+}
+
+// GetFeed отдает календарь задач по секретному токену. Маршрут не требует авторизации:
+// календарные приложения обращаются к адресу подписки без заголовков.
+func (h *CalendarHandler) GetFeed(c *gin.Context) {
+	var params models.CalendarFeedParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	calendar, err := h.calendarService.BuildFeed(c.Param("token"), params)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "calendar feed not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to get calendar",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", `inline; filename="tasks.ics"`)
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Status(http.StatusOK)
+	if err := ical.Encode(c.Writer, calendar); err != nil {
+		log.Printf("Calendar feed write failed: %v", err)
+	}
+}
+
+// calendarFeedURL строит адрес подписки на календарь по адресу текущего запроса
+func calendarFeedURL(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host + "/api/calendar/feed/" + token
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChecklistHandler обработчик для чек-листов задач
+type ChecklistHandler struct {
+	checklistService services.ChecklistService
+}
+
+// NewChecklistHandler создает новый обработчик чек-листов
+func NewChecklistHandler(checklistService services.ChecklistService) *ChecklistHandler {
+	return &ChecklistHandler{
+		checklistService: checklistService,
+	}
+}
+
+// checklistErrorStatus возвращает HTTP-статус для ошибки сервиса чек-листов
+func checklistErrorStatus(err error) int {
+	switch {
+	case err.Error() == "task not found" || err.Error() == "checklist item not found":
+		return http.StatusNotFound
+	case err.Error() == "access denied":
+		return http.StatusForbidden
+	case strings.HasPrefix(err.Error(), "checklist is full"):
+		return http.StatusConflict
+	case strings.HasPrefix(err.Error(), "invalid order"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// GetItems получает пункты чек-листа задачи
+func (h *ChecklistHandler) GetItems(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	items, err := h.checklistService.GetItems(userID, uint(taskID))
+	if err != nil {
+		c.JSON(checklistErrorStatus(err), gin.H{
+			"error":   "Failed to get checklist",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"checklist": items,
+	})
+}
+
+// CreateItem добавляет пункт в чек-лист задачи
+func (h *ChecklistHandler) CreateItem(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	var req models.CreateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	item, err := h.checklistService.CreateItem(userID, uint(taskID), req)
+	if err != nil {
+		c.JSON(checklistErrorStatus(err), gin.H{
+			"error":   "Checklist item creation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Checklist item created successfully",
+		"item":    item,
+	})
+}
+
+// UpdateItem изменяет пункт чек-листа
+func (h *ChecklistHandler) UpdateItem(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid checklist item ID",
+		})
+		return
+	}
+
+	var req models.UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	item, err := h.checklistService.UpdateItem(userID, uint(taskID), uint(itemID), req)
+	if err != nil {
+		c.JSON(checklistErrorStatus(err), gin.H{
+			"error":   "Checklist item update failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Checklist item updated successfully",
+		"item":    item,
+	})
+}
+
+// ToggleItem переключает отметку выполнения пункта чек-листа
+func (h *ChecklistHandler) ToggleItem(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid checklist item ID",
+		})
+		return
+	}
+
+	item, err := h.checklistService.ToggleItem(userID, uint(taskID), uint(itemID))
+	if err != nil {
+		c.JSON(checklistErrorStatus(err), gin.H{
+			"error":   "Checklist item update failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Checklist item updated successfully",
+		"item":    item,
+	})
+}
+
+// DeleteItem удаляет пункт чек-листа
+func (h *ChecklistHandler) DeleteItem(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid checklist item ID",
+		})
+		return
+	}
+
+	err = h.checklistService.DeleteItem(userID, uint(taskID), uint(itemID))
+	if err != nil {
+		c.JSON(checklistErrorStatus(err), gin.H{
+			"error":   "Checklist item deletion failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Checklist item deleted successfully",
+	})
+}
+
+// ReorderItems меняет порядок пунктов чек-листа
+func (h *ChecklistHandler) ReorderItems(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	var req models.ReorderChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	items, err := h.checklistService.ReorderItems(userID, uint(taskID), req)
+	if err != nil {
+		c.JSON(checklistErrorStatus(err), gin.H{
+			"error":   "Checklist reorder failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Checklist reordered successfully",
+		"checklist": items,
+	})
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CustomFieldHandler обработчик для пользовательских полей проектов
+type CustomFieldHandler struct {
+	customFieldService services.CustomFieldService
+}
+
+// NewCustomFieldHandler создает новый обработчик пользовательских полей
+func NewCustomFieldHandler(customFieldService services.CustomFieldService) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		customFieldService: customFieldService,
+	}
+}
+
+// CreateField создает пользовательское поле проекта
+func (h *CustomFieldHandler) CreateField(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid project ID",
+		})
+		return
+	}
+
+	var req models.CreateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	field, err := h.customFieldService.CreateField(userID, uint(projectID), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "project not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		} else if err.Error() == "custom field with this key already exists" {
+			status = http.StatusConflict
+		} else if strings.HasPrefix(err.Error(), "invalid custom field") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Custom field creation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Custom field created successfully",
+		"field":   field,
+	})
+}
+
+// GetFields получает пользовательские поля проекта
+func (h *CustomFieldHandler) GetFields(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid project ID",
+		})
+		return
+	}
+
+	fields, err := h.customFieldService.GetFields(userID, uint(projectID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "project not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to get custom fields",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"fields": fields,
+	})
+}
+
+// UpdateField обновляет пользовательское поле проекта
+func (h *CustomFieldHandler) UpdateField(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid project ID",
+		})
+		return
+	}
+
+	fieldIDStr := c.Param("fieldId")
+	fieldID, err := strconv.ParseUint(fieldIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid custom field ID",
+		})
+		return
+	}
+
+	var req models.UpdateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	field, err := h.customFieldService.UpdateField(userID, uint(projectID), uint(fieldID), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "project not found" || err.Error() == "custom field not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		} else if err.Error() == "option is in use by tasks" {
+			status = http.StatusConflict
+		} else if strings.HasPrefix(err.Error(), "invalid custom field") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Custom field update failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Custom field updated successfully",
+		"field":   field,
+	})
+}
+
+// DeleteField удаляет пользовательское поле проекта
+func (h *CustomFieldHandler) DeleteField(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid project ID",
+		})
+		return
+	}
+
+	fieldIDStr := c.Param("fieldId")
+	fieldID, err := strconv.ParseUint(fieldIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid custom field ID",
+		})
+		return
+	}
+
+	err = h.customFieldService.DeleteField(userID, uint(projectID), uint(fieldID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "project not found" || err.Error() == "custom field not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Custom field deletion failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Custom field deleted successfully",
+	})
+}
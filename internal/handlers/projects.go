@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProjectHandler обработчик для проектов
+type ProjectHandler struct {
+	projectService services.ProjectService
+}
+
+// NewProjectHandler создает новый обработчик проектов
+func NewProjectHandler(projectService services.ProjectService) *ProjectHandler {
+	return &ProjectHandler{
+		projectService: projectService,
+	}
+}
+
+// CreateProject создает новый проект
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var req models.CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	project, err := h.projectService.CreateProject(userID, req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "workflow not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Project creation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Project created successfully",
+		"project": project,
+	})
+}
+
+// GetProjects получает список проектов
+func (h *ProjectHandler) GetProjects(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	projects, err := h.projectService.GetProjects(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get projects",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"projects": projects,
+	})
+}
+
+// GetProject получает проект по ID
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid project ID",
+		})
+		return
+	}
+
+	project, err := h.projectService.GetProjectByID(userID, uint(projectID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "project not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to get project",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"project": project,
+	})
+}
+
+// UpdateProject обновляет проект
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid project ID",
+		})
+		return
+	}
+
+	var req models.UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	project, err := h.projectService.UpdateProject(userID, uint(projectID), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "project not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Project update failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project updated successfully",
+		"project": project,
+	})
+}
+
+// DeleteProject удаляет проект
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid project ID",
+		})
+		return
+	}
+
+	err = h.projectService.DeleteProject(userID, uint(projectID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "project not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		} else if err.Error() == "project has tasks" {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"error":   "Project deletion failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project deleted successfully",
+	})
+}
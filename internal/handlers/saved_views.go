@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SavedViewHandler обработчик для сохраненных представлений
+type SavedViewHandler struct {
+	savedViewService services.SavedViewService
+}
+
+// NewSavedViewHandler создает новый обработчик представлений
+func NewSavedViewHandler(savedViewService services.SavedViewService) *SavedViewHandler {
+	return &SavedViewHandler{
+		savedViewService: savedViewService,
+	}
+}
+
+// savedViewErrorStatus возвращает HTTP-статус для ошибки сервиса представлений
+func savedViewErrorStatus(err error) int {
+	switch {
+	case err.Error() == "view not found" || err.Error() == "project not found":
+		return http.StatusNotFound
+	case err.Error() == "access denied":
+		return http.StatusForbidden
+	case strings.HasPrefix(err.Error(), "invalid view"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// CreateView создает новое представление
+func (h *SavedViewHandler) CreateView(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var req models.CreateSavedViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	view, err := h.savedViewService.CreateView(userID, req)
+	if err != nil {
+		c.JSON(savedViewErrorStatus(err), gin.H{
+			"error":   "View creation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "View created successfully",
+		"view":    view,
+	})
+}
+
+// GetViews получает список представлений
+func (h *SavedViewHandler) GetViews(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var projectID *uint
+	if projectIDStr := c.Query("project_id"); projectIDStr != "" {
+		id, err := strconv.ParseUint(projectIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "Invalid project ID",
+			})
+			return
+		}
+		value := uint(id)
+		projectID = &value
+	}
+
+	views, err := h.savedViewService.GetViews(userID, projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get views",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"views": views,
+	})
+}
+
+// GetView получает представление по ID
+func (h *SavedViewHandler) GetView(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	viewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid view ID",
+		})
+		return
+	}
+
+	view, err := h.savedViewService.GetView(userID, uint(viewID))
+	if err != nil {
+		c.JSON(savedViewErrorStatus(err), gin.H{
+			"error":   "Failed to get view",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"view": view,
+	})
+}
+
+// UpdateView обновляет представление
+func (h *SavedViewHandler) UpdateView(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	viewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid view ID",
+		})
+		return
+	}
+
+	var req models.UpdateSavedViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	view, err := h.savedViewService.UpdateView(userID, uint(viewID), req)
+	if err != nil {
+		c.JSON(savedViewErrorStatus(err), gin.H{
+			"error":   "View update failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "View updated successfully",
+		"view":    view,
+	})
+}
+
+// DeleteView удаляет представление
+func (h *SavedViewHandler) DeleteView(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	viewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid view ID",
+		})
+		return
+	}
+
+	if err := h.savedViewService.DeleteView(userID, uint(viewID)); err != nil {
+		c.JSON(savedViewErrorStatus(err), gin.H{
+			"error":   "View deletion failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "View deleted successfully",
+	})
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SLAPolicyHandler обработчик для политик SLA
+type SLAPolicyHandler struct {
+	slaPolicyService services.SLAPolicyService
+}
+
+// NewSLAPolicyHandler создает новый обработчик политик SLA
+func NewSLAPolicyHandler(slaPolicyService services.SLAPolicyService) *SLAPolicyHandler {
+	return &SLAPolicyHandler{
+		slaPolicyService: slaPolicyService,
+	}
+}
+
+// slaPolicyErrorStatus возвращает HTTP-статус для ошибки сервиса политик SLA
+func slaPolicyErrorStatus(err error) int {
+	switch err.Error() {
+	case "sla policy not found", "project not found":
+		return http.StatusNotFound
+	case "access denied":
+		return http.StatusForbidden
+	case "sla policy already exists for this project and priority":
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// GetPolicies получает политики SLA пользователя
+func (h *SLAPolicyHandler) GetPolicies(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	policies, err := h.slaPolicyService.GetPolicies(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get SLA policies",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"policies": policies,
+	})
+}
+
+// CreatePolicy создает политику SLA
+func (h *SLAPolicyHandler) CreatePolicy(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var req models.CreateSLAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	policy, err := h.slaPolicyService.CreatePolicy(userID, req)
+	if err != nil {
+		c.JSON(slaPolicyErrorStatus(err), gin.H{
+			"error":   "SLA policy creation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "SLA policy created successfully",
+		"policy":  policy,
+	})
+}
+
+// UpdatePolicy обновляет политику SLA
+func (h *SLAPolicyHandler) UpdatePolicy(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	policyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid SLA policy ID",
+		})
+		return
+	}
+
+	var req models.UpdateSLAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	policy, err := h.slaPolicyService.UpdatePolicy(userID, uint(policyID), req)
+	if err != nil {
+		c.JSON(slaPolicyErrorStatus(err), gin.H{
+			"error":   "SLA policy update failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "SLA policy updated successfully",
+		"policy":  policy,
+	})
+}
+
+// DeletePolicy удаляет политику SLA
+func (h *SLAPolicyHandler) DeletePolicy(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	policyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid SLA policy ID",
+		})
+		return
+	}
+
+	if err := h.slaPolicyService.DeletePolicy(userID, uint(policyID)); err != nil {
+		c.JSON(slaPolicyErrorStatus(err), gin.H{
+			"error":   "SLA policy deletion failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "SLA policy deleted successfully",
+	})
+}
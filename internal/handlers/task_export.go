@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportFlushEvery через сколько задач выгрузка отправляется клиенту
+const exportFlushEvery = 100
+
+// taskExportColumns колонки CSV выгрузки задач; за ними идут колонки cf.<ключ>
+var taskExportColumns = []string{
+	"id", "title", "description", "status", "priority", "start_date", "end_date",
+	"project_id", "parent_id", "tags", "completed_at", "archived_at", "created_at", "updated_at",
+}
+
+// ExportTasks выгружает задачи в CSV, JSON или NDJSON с теми же фильтрами, что и список задач.
+// Задачи отправляются клиенту по мере чтения из базы.
+func (h *TaskHandler) ExportTasks(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var params models.TaskQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+	params.CustomFields = c.QueryMap("cf")
+
+	format := models.ImportFormat(c.DefaultQuery("format", string(models.ImportFormatJSON)))
+	switch format {
+	case models.ImportFormatCSV, models.ImportFormatJSON, models.ImportFormatNDJSON:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid format, expected csv, json or ndjson",
+		})
+		return
+	}
+
+	if _, err := h.savedViewService.ApplyView(userID, c.Query("view"), &params); err != nil {
+		c.JSON(savedViewErrorStatus(err), gin.H{
+			"error":   "Failed to export tasks",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	exporter := &taskExporter{c: c, format: format}
+	if err := h.taskService.ExportTasks(userID, params, exporter); err != nil {
+		// После начала выгрузки статус ответа уже отправлен, поэтому ошибка только записывается в журнал
+		if exporter.started {
+			log.Printf("Task export failed after %d tasks: %v", exporter.count, err)
+			return
+		}
+		c.JSON(taskQueryErrorStatus(err), gin.H{
+			"error":   "Failed to export tasks",
+			"message": err.Error(),
+		})
+	}
+}
+
+// taskExporter записывает выгружаемые задачи в ответ в выбранном формате
+type taskExporter struct {
+	c       *gin.Context
+	format  models.ImportFormat
+	csv     *csv.Writer
+	keys    []string
+	started bool
+	count   int
+}
+
+// Begin отправляет заголовки ответа и начало файла
+func (e *taskExporter) Begin(customFieldKeys []string) error {
+	e.started = true
+	e.keys = customFieldKeys
+
+	contentType := "application/json; charset=utf-8"
+	switch e.format {
+	case models.ImportFormatCSV:
+		contentType = "text/csv; charset=utf-8"
+	case models.ImportFormatNDJSON:
+		contentType = "application/x-ndjson"
+	}
+	e.c.Header("Content-Type", contentType)
+	e.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, e.format))
+	e.c.Status(http.StatusOK)
+
+	switch e.format {
+	case models.ImportFormatCSV:
+		e.csv = csv.NewWriter(e.c.Writer)
+		header := append([]string{}, taskExportColumns...)
+		for _, key := range customFieldKeys {
+			header = append(header, "cf."+key)
+		}
+		return e.csv.Write(header)
+	case models.ImportFormatJSON:
+		_, err := e.c.Writer.WriteString("[")
+		return err
+	}
+	return nil
+}
+
+// Write записывает одну задачу
+func (e *taskExporter) Write(task models.TaskResponse) error {
+	var err error
+	switch e.format {
+	case models.ImportFormatCSV:
+		err = e.csv.Write(e.csvRow(task))
+	case models.ImportFormatJSON:
+		if e.count > 0 {
+			if _, err = e.c.Writer.WriteString(","); err != nil {
+				return err
+			}
+		}
+		err = json.NewEncoder(e.c.Writer).Encode(task)
+	case models.ImportFormatNDJSON:
+		err = json.NewEncoder(e.c.Writer).Encode(task)
+	}
+	if err != nil {
+		return err
+	}
+
+	e.count++
+	if e.count%exportFlushEvery == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+// End записывает конец файла и отправляет остаток выгрузки
+func (e *taskExporter) End() error {
+	if e.format == models.ImportFormatJSON {
+		if _, err := e.c.Writer.WriteString("]\n"); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
+
+// flush отправляет клиенту записанную часть выгрузки
+func (e *taskExporter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	e.c.Writer.Flush()
+	return nil
+}
+
+// csvRow форматирует задачу как строку CSV
+func (e *taskExporter) csvRow(task models.TaskResponse) []string {
+	row := []string{
+		strconv.FormatUint(uint64(task.ID), 10),
+		task.Title,
+		task.Description,
+		string(task.Status),
+		string(task.Priority),
+		task.StartDate.Format(time.RFC3339),
+		task.EndDate.Format(time.RFC3339),
+		formatOptionalID(task.ProjectID),
+		formatOptionalID(task.ParentID),
+		strings.Join(task.Tags, ", "),
+		formatOptionalTime(task.CompletedAt),
+		formatOptionalTime(task.ArchivedAt),
+		task.CreatedAt.Format(time.RFC3339),
+		task.UpdatedAt.Format(time.RFC3339),
+	}
+	for _, key := range e.keys {
+		row = append(row, formatCustomFieldValue(task.CustomFields[key]))
+	}
+	return row
+}
+
+// formatOptionalTime форматирует необязательный момент времени для CSV
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatCustomFieldValue форматирует значение пользовательского поля для CSV;
+// варианты multi_select перечисляются через запятую
+func formatCustomFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ", ")
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize максимальный размер загружаемого файла задач
+const maxImportSize = 32 << 20
+
+// ImportHandler обработчик для загрузки задач из файлов
+type ImportHandler struct {
+	importService services.ImportService
+}
+
+// NewImportHandler создает новый обработчик загрузки задач
+func NewImportHandler(importService services.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// ImportTasks загружает задачи из CSV, JSON или NDJSON. Файл передается полем file формы
+// multipart/form-data или телом запроса; параметры — полями формы или строкой запроса.
+func (h *ImportHandler) ImportTasks(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	var params models.TaskImportParams
+	var file io.Reader
+	var fileName, contentType string
+	if c.ContentType() == "multipart/form-data" {
+		if err := c.ShouldBind(&params); err != nil {
+			c.JSON(importErrorStatus(err), gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		header, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "File is required in the file form field",
+			})
+			return
+		}
+		upload, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to import tasks",
+				"message": err.Error(),
+			})
+			return
+		}
+		defer upload.Close()
+
+		file = upload
+		fileName = header.Filename
+		contentType = header.Header.Get("Content-Type")
+	} else {
+		if err := c.ShouldBindQuery(&params); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+		file = c.Request.Body
+		contentType = c.ContentType()
+	}
+
+	req := models.TaskImportRequest{
+		Format:    params.Format,
+		DryRun:    params.DryRun,
+		ProjectID: params.ProjectID,
+	}
+	if req.Format == "" {
+		req.Format = detectImportFormat(fileName, contentType)
+		if req.Format == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "Cannot determine file format, specify format: csv, json or ndjson",
+			})
+			return
+		}
+	}
+	if params.Mapping != "" {
+		if err := json.Unmarshal([]byte(params.Mapping), &req.Mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "invalid mapping: expected a JSON object of column names to task fields",
+			})
+			return
+		}
+	}
+
+	report, job, err := h.importService.ImportTasks(userID, req, file)
+	if err != nil {
+		c.JSON(importErrorStatus(err), gin.H{
+			"error":   "Failed to import tasks",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if job != nil {
+		c.Header("Location", "/api/tasks/import/jobs/"+strconv.FormatUint(uint64(job.ID), 10))
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "Import started",
+			"job":     job,
+		})
+		return
+	}
+
+	switch {
+	case report.Invalid > 0:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Import contains invalid records",
+			"message": "No tasks were imported",
+			"report":  report,
+		})
+	case report.DryRun:
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Import validated successfully",
+			"report":  report,
+		})
+	default:
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Tasks imported successfully",
+			"report":  report,
+		})
+	}
+}
+
+// GetJob получает состояние фоновой загрузки задач
+func (h *ImportHandler) GetJob(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid import job ID",
+		})
+		return
+	}
+
+	job, err := h.importService.GetJob(userID, uint(jobID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "import job not found":
+			status = http.StatusNotFound
+		case "access denied":
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to get import job",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job": job,
+	})
+}
+
+// importErrorStatus возвращает HTTP-статус для ошибки загрузки задач
+func importErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge
+	case strings.HasPrefix(err.Error(), "invalid import") || strings.HasPrefix(err.Error(), "invalid mapping"):
+		return http.StatusBadRequest
+	case err.Error() == "project not found":
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// detectImportFormat определяет формат файла по расширению имени или типу содержимого
+func detectImportFormat(fileName, contentType string) models.ImportFormat {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return models.ImportFormatCSV
+	case ".json":
+		return models.ImportFormatJSON
+	case ".ndjson", ".jsonl":
+		return models.ImportFormatNDJSON
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return models.ImportFormatCSV
+	case "application/json":
+		return models.ImportFormatJSON
+	case "application/x-ndjson", "application/jsonl":
+		return models.ImportFormatNDJSON
+	}
+	return ""
+}
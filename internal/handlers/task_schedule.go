@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dependencyErrorStatus возвращает HTTP-статус для ошибки работы с зависимостями
+func dependencyErrorStatus(err error) int {
+	switch err.Error() {
+	case "task not found", "dependency task not found", "dependency not found":
+		return http.StatusNotFound
+	case "access denied":
+		return http.StatusForbidden
+	case "dependency already exists":
+		return http.StatusConflict
+	}
+	if strings.HasPrefix(err.Error(), "invalid dependency") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// GetDependencies возвращает зависимости задачи
+func (h *TaskHandler) GetDependencies(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	dependencies, err := h.taskService.GetDependencies(userID, uint(taskID))
+	if err != nil {
+		c.JSON(dependencyErrorStatus(err), gin.H{
+			"error":   "Failed to get dependencies",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dependencies)
+}
+
+// AddDependency добавляет зависимость задачи от другой задачи
+func (h *TaskHandler) AddDependency(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	var req models.CreateTaskDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	dependency, err := h.taskService.AddDependency(userID, uint(taskID), req)
+	if err != nil {
+		c.JSON(dependencyErrorStatus(err), gin.H{
+			"error":   "Failed to add dependency",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Dependency added successfully",
+		"dependency": dependency,
+	})
+}
+
+// RemoveDependency удаляет зависимость задачи от другой задачи
+func (h *TaskHandler) RemoveDependency(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	dependsOnID, err := strconv.ParseUint(c.Param("dependsOnId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid dependency task ID",
+		})
+		return
+	}
+
+	if err := h.taskService.RemoveDependency(userID, uint(taskID), uint(dependsOnID)); err != nil {
+		c.JSON(dependencyErrorStatus(err), gin.H{
+			"error":   "Failed to remove dependency",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Dependency removed successfully",
+	})
+}
+
+// GetTimeline возвращает задачи за период с зависимостями и конфликтами расписания
+func (h *TaskHandler) GetTimeline(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var params models.TimelineParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	timeline, err := h.taskService.GetTimeline(userID, params)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid range") {
+			status = http.StatusBadRequest
+		} else if err.Error() == "project not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to get timeline",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"timeline": timeline,
+	})
+}
+
+// ShiftTask переносит задачу и зависящие от нее задачи
+func (h *TaskHandler) ShiftTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"), h.taskEntityTag(userID, uint(taskID)))
+	if err != nil {
+		ifMatchFailed(c, err)
+		return
+	}
+
+	var req models.ShiftTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+	req.ExpectedVersion = expectedVersion
+
+	result, err := h.taskService.ShiftTask(userID, uint(taskID), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "task not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		} else if err.Error() == "version conflict" {
+			status = versionConflictStatus(expectedVersion)
+		} else if strings.HasPrefix(err.Error(), "invalid shift") || err.Error() == "end date cannot be before start date" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Task shift failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task shifted successfully",
+		"shift":   result,
+	})
+}
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TaskTemplateHandler обработчик для шаблонов задач
+type TaskTemplateHandler struct {
+	templateService services.TaskTemplateService
+}
+
+// NewTaskTemplateHandler создает новый обработчик шаблонов задач
+func NewTaskTemplateHandler(templateService services.TaskTemplateService) *TaskTemplateHandler {
+	return &TaskTemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// taskTemplateErrorStatus возвращает HTTP-статус для ошибки сервиса шаблонов задач
+func taskTemplateErrorStatus(err error) int {
+	switch {
+	case err.Error() == "template not found" || err.Error() == "project not found":
+		return http.StatusNotFound
+	case err.Error() == "access denied":
+		return http.StatusForbidden
+	case err.Error() == "invalid priority" || err.Error() == "end date cannot be before start date":
+		return http.StatusBadRequest
+	case strings.HasPrefix(err.Error(), "invalid template") || strings.HasPrefix(err.Error(), "invalid variables") ||
+		strings.HasPrefix(err.Error(), "invalid anchor date") || strings.HasPrefix(err.Error(), "invalid tags") || strings.HasPrefix(err.Error(), "invalid checklist") ||
+		strings.HasPrefix(err.Error(), "invalid custom field"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// CreateTemplate создает новый шаблон
+func (h *TaskTemplateHandler) CreateTemplate(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var req models.CreateTaskTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(userID, req)
+	if err != nil {
+		c.JSON(taskTemplateErrorStatus(err), gin.H{
+			"error":   "Template creation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Template created successfully",
+		"template": template,
+	})
+}
+
+// GetTemplates получает список шаблонов
+func (h *TaskTemplateHandler) GetTemplates(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var projectID *uint
+	if projectIDStr := c.Query("project_id"); projectIDStr != "" {
+		id, err := strconv.ParseUint(projectIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "Invalid project ID",
+			})
+			return
+		}
+		value := uint(id)
+		projectID = &value
+	}
+
+	templates, err := h.templateService.GetTemplates(userID, projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get templates",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+	})
+}
+
+// GetTemplate получает шаблон по ID
+func (h *TaskTemplateHandler) GetTemplate(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid template ID",
+		})
+		return
+	}
+
+	template, err := h.templateService.GetTemplate(userID, uint(templateID))
+	if err != nil {
+		c.JSON(taskTemplateErrorStatus(err), gin.H{
+			"error":   "Failed to get template",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"template": template,
+	})
+}
+
+// UpdateTemplate обновляет шаблон
+func (h *TaskTemplateHandler) UpdateTemplate(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid template ID",
+		})
+		return
+	}
+
+	var req models.UpdateTaskTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(userID, uint(templateID), req)
+	if err != nil {
+		c.JSON(taskTemplateErrorStatus(err), gin.H{
+			"error":   "Template update failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Template updated successfully",
+		"template": template,
+	})
+}
+
+// DeleteTemplate удаляет шаблон
+func (h *TaskTemplateHandler) DeleteTemplate(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid template ID",
+		})
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(userID, uint(templateID)); err != nil {
+		c.JSON(taskTemplateErrorStatus(err), gin.H{
+			"error":   "Template deletion failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Template deleted successfully",
+	})
+}
+
+// InstantiateTemplate создает задачи по шаблону
+func (h *TaskTemplateHandler) InstantiateTemplate(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid template ID",
+		})
+		return
+	}
+
+	var req models.InstantiateTaskTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	instance, err := h.templateService.InstantiateTemplate(userID, uint(templateID), req)
+	if err != nil {
+		c.JSON(taskTemplateErrorStatus(err), gin.H{
+			"error":   "Template instantiation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Tasks created from template successfully",
+		"task":     instance.Task,
+		"subtasks": instance.Subtasks,
+	})
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"golang_server/internal/middleware"
 	"golang_server/internal/models"
@@ -13,13 +16,15 @@ import (
 
 // TaskHandler обработчик для задач
 type TaskHandler struct {
-	taskService services.TaskService
+	taskService      services.TaskService
+	savedViewService services.SavedViewService
 }
 
 // NewTaskHandler создает новый обработчик задач
-func NewTaskHandler(taskService services.TaskService) *TaskHandler {
+func NewTaskHandler(taskService services.TaskService, savedViewService services.SavedViewService) *TaskHandler {
 	return &TaskHandler{
-		taskService: taskService,
+		taskService:      taskService,
+		savedViewService: savedViewService,
 	}
 }
 
@@ -45,7 +50,17 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 
 	task, err := h.taskService.CreateTask(userID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "project not found":
+			status = http.StatusNotFound
+		case "invalid priority", "end date cannot be before start date":
+			status = http.StatusBadRequest
+		}
+		if strings.HasPrefix(err.Error(), "invalid custom field") || strings.HasPrefix(err.Error(), "invalid tags") || strings.HasPrefix(err.Error(), "invalid parent task") || strings.HasPrefix(err.Error(), "invalid checklist") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
 			"error":   "Task creation failed",
 			"message": err.Error(),
 		})
@@ -78,17 +93,112 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
 		return
 	}
 
-	tasks, total, err := h.taskService.GetTasks(userID, params)
+	// Фильтры по пользовательским полям передаются как cf[<ключ>]=<значение>
+	params.CustomFields = c.QueryMap("cf")
+
+	// Сохраненное представление дополняет параметры запроса
+	view, err := h.savedViewService.ApplyView(userID, c.Query("view"), &params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(savedViewErrorStatus(err), gin.H{
+			"error":   "Failed to get tasks",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	page, err := h.taskService.GetTasks(userID, params)
+	if err != nil {
+		c.JSON(taskQueryErrorStatus(err), gin.H{
 			"error":   "Failed to get tasks",
 			"message": err.Error(),
 		})
 		return
 	}
 
+	pagination := gin.H{
+		"limit":       params.Limit,
+		"next_cursor": nil,
+		"prev_cursor": nil,
+	}
+	if params.Cursor == "" {
+		pagination["page"] = params.Page
+	}
+	if page.Total != nil {
+		pagination["total"] = *page.Total
+	}
+
+	// Ссылки на соседние страницы также передаются в заголовке Link (RFC 8288)
+	var links []string
+	if page.NextCursor != "" {
+		pagination["next_cursor"] = page.NextCursor
+		links = append(links, "<"+cursorURL(c, page.NextCursor)+`>; rel="next"`)
+	}
+	if page.PrevCursor != "" {
+		pagination["prev_cursor"] = page.PrevCursor
+		links = append(links, "<"+cursorURL(c, page.PrevCursor)+`>; rel="prev"`)
+	}
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"tasks": tasks,
+		"tasks":      page.Tasks,
+		"pagination": pagination,
+		"view":       view,
+	})
+}
+
+// taskQueryErrorStatus возвращает HTTP-статус для ошибки в параметрах списка задач
+func taskQueryErrorStatus(err error) int {
+	if strings.HasPrefix(err.Error(), "invalid custom field") || strings.HasPrefix(err.Error(), "invalid filter") || strings.HasPrefix(err.Error(), "invalid sort") || strings.HasPrefix(err.Error(), "invalid cursor") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// cursorURL возвращает адрес текущего запроса с курсором вместо номера страницы
+func cursorURL(c *gin.Context, cursor string) string {
+	query := c.Request.URL.Query()
+	query.Del("page")
+	query.Set("cursor", cursor)
+	return c.Request.URL.Path + "?" + query.Encode()
+}
+
+// SearchTasks выполняет полнотекстовый поиск задач с подсветкой совпадений
+func (h *TaskHandler) SearchTasks(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var params models.TaskSearchParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	results, total, err := h.taskService.SearchTasks(userID, params)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid search query") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to search tasks",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": results,
 		"pagination": gin.H{
 			"total": total,
 			"page":  params.Page,
@@ -97,6 +207,55 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
 	})
 }
 
+// BulkTasks применяет операцию обновления, удаления или перемещения к группе задач
+func (h *TaskHandler) BulkTasks(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var req models.BulkTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.taskService.BulkTasks(userID, req)
+	if err != nil {
+		// В атомарном режиме ошибка по одной задаче откатывает всю операцию
+		if result != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "Bulk operation failed",
+				"message": err.Error(),
+				"result":  result,
+			})
+			return
+		}
+
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid bulk request") || strings.HasPrefix(err.Error(), "too many tasks") || strings.HasPrefix(err.Error(), "invalid custom field") || strings.HasPrefix(err.Error(), "invalid filter") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Bulk operation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Bulk operation completed",
+		"result":  result,
+	})
+}
+
 // GetTask получает задачу по ID
 func (h *TaskHandler) GetTask(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
@@ -133,6 +292,13 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 		return
 	}
 
+	etag := taskResponseETag(task)
+	c.Header("ETag", etag)
+	if ifNoneMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"task": task,
 	})
@@ -159,6 +325,12 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		return
 	}
 
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"), h.taskEntityTag(userID, uint(taskID)))
+	if err != nil {
+		ifMatchFailed(c, err)
+		return
+	}
+
 	var req models.UpdateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -167,6 +339,7 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		})
 		return
 	}
+	req.ExpectedVersion = expectedVersion
 
 	task, err := h.taskService.UpdateTask(userID, uint(taskID), req)
 	if err != nil {
@@ -175,7 +348,88 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 			status = http.StatusNotFound
 		} else if err.Error() == "access denied" {
 			status = http.StatusForbidden
-		} else if err.Error() == "invalid status" || err.Error() == "end date cannot be before start date" {
+		} else if err.Error() == "version conflict" {
+			status = versionConflictStatus(expectedVersion)
+		} else if err.Error() == "required checklist items are not completed" {
+			status = http.StatusConflict
+		} else if err.Error() == "invalid status" || err.Error() == "status transition not allowed" || err.Error() == "invalid priority" || err.Error() == "end date cannot be before start date" {
+			status = http.StatusBadRequest
+		} else if strings.HasPrefix(err.Error(), "invalid custom field") || strings.HasPrefix(err.Error(), "invalid tags") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Task update failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Header("ETag", taskResponseETag(task))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task updated successfully",
+		"task":    task,
+	})
+}
+
+// PatchTask частично обновляет задачу по JSON Merge Patch или JSON Patch
+func (h *TaskHandler) PatchTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskIDStr := c.Param("id")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	patchType := models.PatchType(c.ContentType())
+	if patchType != models.PatchTypeMerge && patchType != models.PatchTypeJSON {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error":   "Unsupported Media Type",
+			"message": "Content-Type must be application/merge-patch+json or application/json-patch+json",
+		})
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"), h.taskEntityTag(userID, uint(taskID)))
+	if err != nil {
+		ifMatchFailed(c, err)
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	task, err := h.taskService.PatchTask(userID, uint(taskID), patchType, patch, expectedVersion)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "task not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		} else if err.Error() == "version conflict" {
+			status = versionConflictStatus(expectedVersion)
+		} else if err.Error() == "patch test failed" || err.Error() == "required checklist items are not completed" {
+			status = http.StatusConflict
+		} else if err.Error() == "invalid status" || err.Error() == "status transition not allowed" || err.Error() == "invalid priority" || err.Error() == "end date cannot be before start date" {
+			status = http.StatusBadRequest
+		} else if strings.HasPrefix(err.Error(), "invalid patch") || strings.HasPrefix(err.Error(), "invalid custom field") || strings.HasPrefix(err.Error(), "invalid tags") {
 			status = http.StatusBadRequest
 		}
 		c.JSON(status, gin.H{
@@ -185,6 +439,7 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", taskResponseETag(task))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Task updated successfully",
 		"task":    task,
@@ -212,13 +467,21 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 		return
 	}
 
-	err = h.taskService.DeleteTask(userID, uint(taskID))
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"), h.taskEntityTag(userID, uint(taskID)))
+	if err != nil {
+		ifMatchFailed(c, err)
+		return
+	}
+
+	err = h.taskService.DeleteTask(userID, uint(taskID), expectedVersion)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "task not found" {
 			status = http.StatusNotFound
 		} else if err.Error() == "access denied" {
 			status = http.StatusForbidden
+		} else if err.Error() == "version conflict" {
+			status = versionConflictStatus(expectedVersion)
 		}
 		c.JSON(status, gin.H{
 			"error":   "Task deletion failed",
@@ -230,4 +493,593 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Task deleted successfully",
 	})
-} 
\ No newline at end of file
+}
+
+// GetTrash получает задачи пользователя в корзине
+func (h *TaskHandler) GetTrash(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var params models.TaskQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	tasks, total, err := h.taskService.GetTrash(userID, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get trash",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks": tasks,
+		"pagination": gin.H{
+			"total": total,
+			"page":  params.Page,
+			"limit": params.Limit,
+		},
+	})
+}
+
+// EmptyTrash безвозвратно удаляет все задачи из корзины
+func (h *TaskHandler) EmptyTrash(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	purged, err := h.taskService.EmptyTrash(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to empty trash",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Trash emptied successfully",
+		"purged":  purged,
+	})
+}
+
+// RestoreTask возвращает задачу из корзины
+func (h *TaskHandler) RestoreTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskIDStr := c.Param("id")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	task, err := h.taskService.RestoreTask(userID, uint(taskID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "task not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		} else if err.Error() == "task is not in trash" {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"error":   "Task restore failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Header("ETag", taskResponseETag(task))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task restored successfully",
+		"task":    task,
+	})
+}
+
+// archiveErrorStatus возвращает HTTP-статус для ошибки архивирования задачи
+func archiveErrorStatus(err error) int {
+	switch err.Error() {
+	case "task not found", "project not found":
+		return http.StatusNotFound
+	case "access denied":
+		return http.StatusForbidden
+	case "task is already archived", "task is not archived", "version conflict":
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// ArchiveTask помещает задачу в архив
+func (h *TaskHandler) ArchiveTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	task, err := h.taskService.ArchiveTask(userID, uint(taskID))
+	if err != nil {
+		c.JSON(archiveErrorStatus(err), gin.H{
+			"error":   "Task archive failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Header("ETag", taskResponseETag(task))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task archived successfully",
+		"task":    task,
+	})
+}
+
+// UnarchiveTask возвращает задачу из архива
+func (h *TaskHandler) UnarchiveTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	task, err := h.taskService.UnarchiveTask(userID, uint(taskID))
+	if err != nil {
+		c.JSON(archiveErrorStatus(err), gin.H{
+			"error":   "Task unarchive failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Header("ETag", taskResponseETag(task))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task unarchived successfully",
+		"task":    task,
+	})
+}
+
+// ArchiveCompleted помещает в архив задачи, завершенные больше заданного числа дней назад
+func (h *TaskHandler) ArchiveCompleted(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var req models.ArchiveCompletedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.taskService.ArchiveCompleted(userID, req)
+	if err != nil {
+		c.JSON(archiveErrorStatus(err), gin.H{
+			"error":   "Failed to archive completed tasks",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Completed tasks archived successfully",
+		"archived": result.Archived,
+		"skipped":  result.Skipped,
+	})
+}
+
+// PurgeTask удаляет задачу безвозвратно
+func (h *TaskHandler) PurgeTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskIDStr := c.Param("id")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	err = h.taskService.PurgeTask(userID, uint(taskID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "task not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Task purge failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task permanently deleted",
+	})
+}
+
+// GetTaskHistory получает историю изменений задачи
+func (h *TaskHandler) GetTaskHistory(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskIDStr := c.Param("id")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	var params models.TaskHistoryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	revisions, total, err := h.taskService.GetTaskHistory(userID, uint(taskID), params)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "task not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to get task history",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"history": revisions,
+		"pagination": gin.H{
+			"total": total,
+			"page":  params.Page,
+			"limit": params.Limit,
+		},
+	})
+}
+
+// RevertTask возвращает задачу к состоянию из истории изменений
+func (h *TaskHandler) RevertTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskIDStr := c.Param("id")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	revisionIDStr := c.Param("revisionId")
+	revisionID, err := strconv.ParseUint(revisionIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid revision ID",
+		})
+		return
+	}
+
+	task, err := h.taskService.RevertTask(userID, uint(taskID), uint(revisionID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "task not found" || err.Error() == "revision not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		} else if err.Error() == "version conflict" || err.Error() == "required checklist items are not completed" {
+			status = http.StatusConflict
+		} else if err.Error() == "invalid status" || err.Error() == "status transition not allowed" || err.Error() == "invalid priority" || err.Error() == "end date cannot be before start date" {
+			status = http.StatusBadRequest
+		} else if strings.HasPrefix(err.Error(), "invalid custom field") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Task revert failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Header("ETag", taskResponseETag(task))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task reverted successfully",
+		"task":    task,
+	})
+}
+
+// MoveTask перемещает задачу в другую колонку и/или позицию на доске
+func (h *TaskHandler) MoveTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskIDStr := c.Param("id")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	var req models.MoveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	task, err := h.taskService.MoveTask(userID, uint(taskID), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "task not found":
+			status = http.StatusNotFound
+		case "access denied":
+			status = http.StatusForbidden
+		case "invalid status", "status transition not allowed", "neighbor task not found", "neighbor task is not in the target column",
+			"task cannot be positioned relative to itself", "invalid neighbor order":
+			status = http.StatusBadRequest
+		case "version conflict", "required checklist items are not completed":
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"error":   "Task move failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Header("ETag", taskResponseETag(task))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task moved successfully",
+		"task":    task,
+	})
+}
+
+// GetBoard получает задачи, сгруппированные по колонкам статусов
+func (h *TaskHandler) GetBoard(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var projectID *uint
+	if projectIDStr := c.Query("project_id"); projectIDStr != "" {
+		id, err := strconv.ParseUint(projectIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "Invalid project ID",
+			})
+			return
+		}
+		pid := uint(id)
+		projectID = &pid
+	}
+
+	columns, err := h.taskService.GetBoard(userID, projectID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "project not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to get board",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"columns": columns,
+	})
+}
+
+// taskETag формирует ETag задачи по ее версии
+func taskETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// taskResponseETag формирует ETag представления задачи. Просрочка и срок SLA
+// вычисляются по текущему времени и политикам, не меняя версию задачи,
+// поэтому они добавляются к версии, например "3.o.s1792396800". Для просрочки
+// учитывается только ее наличие: число секунд растет постоянно и сделало бы
+// ETag просроченной задачи новым при каждом запросе.
+func taskResponseETag(task *models.TaskResponse) string {
+	tag := strconv.Itoa(task.Version)
+	if task.IsOverdue {
+		tag += ".o"
+	}
+	if task.SLADueAt != nil {
+		tag += ".s" + strconv.FormatInt(task.SLADueAt.Unix(), 10)
+	}
+	return `"` + tag + `"`
+}
+
+// errPreconditionFailed возвращается parseIfMatch, если ни один ETag из If-Match
+// не совпал с текущим представлением ресурса
+var errPreconditionFailed = errors.New("If-Match does not match the current entity tag")
+
+// parseIfMatch проверяет заголовок If-Match (RFC 9110, 13.1.1) и возвращает версию
+// задачи, которую ожидает клиент. Для If-Match используется только сильное сравнение:
+// ETag из списка должен полностью совпасть с текущим ETag, который возвращает current,
+// а слабые теги (W/"3") не совпадают никогда. Если совпадения нет или ресурс не удалось
+// прочитать, возвращается errPreconditionFailed. Пустой заголовок и "*" не ограничивают
+// версию и возвращают nil.
+func parseIfMatch(header string, current func() (string, int, error)) (*int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		opaque := strings.TrimPrefix(tag, "W/")
+		if len(opaque) < 2 || opaque[0] != '"' || opaque[len(opaque)-1] != '"' {
+			return nil, errors.New("If-Match must contain a list of entity tags")
+		}
+		tags = append(tags, tag)
+	}
+
+	etag, version, err := current()
+	if err != nil || !slices.Contains(tags, etag) {
+		return nil, errPreconditionFailed
+	}
+	return &version, nil
+}
+
+// ifMatchFailed отвечает на ошибку parseIfMatch: 412 при несовпадении ETag
+// и 400 при некорректном заголовке
+func ifMatchFailed(c *gin.Context, err error) {
+	if errors.Is(err, errPreconditionFailed) {
+		c.JSON(http.StatusPreconditionFailed, gin.H{
+			"error":   "Precondition Failed",
+			"message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":   "Bad Request",
+		"message": err.Error(),
+	})
+}
+
+// taskEntityTag возвращает функцию, читающую текущий ETag и версию задачи для parseIfMatch
+func (h *TaskHandler) taskEntityTag(userID, taskID uint) func() (string, int, error) {
+	return func() (string, int, error) {
+		task, err := h.taskService.GetTaskByID(userID, taskID)
+		if err != nil {
+			return "", 0, err
+		}
+		return taskResponseETag(task), task.Version, nil
+	}
+}
+
+// ifNoneMatch проверяет, совпадает ли один из ETag заголовка If-None-Match с etag.
+// Для If-None-Match используется слабое сравнение, поэтому префикс W/ игнорируется.
+func ifNoneMatch(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// versionConflictStatus выбирает код ответа при конфликте версий: 412, если клиент
+// передал If-Match, и 409, если задачу изменили параллельно во время запроса
+func versionConflictStatus(expectedVersion *int) int {
+	if expectedVersion != nil {
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusConflict
+}
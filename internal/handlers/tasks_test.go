@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang_server/internal/models"
+)
+
+func TestTaskResponseETag(t *testing.T) {
+	dueAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		task models.TaskResponse
+		want string
+	}{
+		{"plain", models.TaskResponse{Version: 3}, `"3"`},
+		{"overdue", models.TaskResponse{Version: 3, IsOverdue: true, OverdueBy: 120}, `"3.o"`},
+		{"sla", models.TaskResponse{Version: 3, SLADueAt: &dueAt}, `"3.s1792411200"`},
+		{"overdue and sla", models.TaskResponse{Version: 3, IsOverdue: true, OverdueBy: 60, SLADueAt: &dueAt}, `"3.o.s1792411200"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			etag := taskResponseETag(&tt.task)
+			if etag != tt.want {
+				t.Fatalf("etag = %s, want %s", etag, tt.want)
+			}
+
+			// If-Match с этим ETag ожидает версию задачи
+			current := func() (string, int, error) { return etag, tt.task.Version, nil }
+			version, err := parseIfMatch(etag, current)
+			if err != nil || version == nil || *version != tt.task.Version {
+				t.Errorf("parseIfMatch(%s) = %v, %v, want version %d", etag, version, err, tt.task.Version)
+			}
+		})
+	}
+}
+
+func TestIfNoneMatchSeesOverdueChange(t *testing.T) {
+	cached := taskResponseETag(&models.TaskResponse{Version: 3})
+	current := taskResponseETag(&models.TaskResponse{Version: 3, IsOverdue: true, OverdueBy: 1})
+	if ifNoneMatch(cached, current) {
+		t.Error("a task that became overdue must not answer 304 to the cached ETag")
+	}
+	if !ifNoneMatch(cached, cached) {
+		t.Error("an unchanged task must answer 304")
+	}
+
+	later := taskResponseETag(&models.TaskResponse{Version: 3, IsOverdue: true, OverdueBy: 3600})
+	if !ifNoneMatch(current, later) {
+		t.Error("a task that stays overdue must answer 304 as time passes")
+	}
+}
+
+func TestParseIfMatch(t *testing.T) {
+	current := func() (string, int, error) { return `"4.o"`, 4, nil }
+	for _, header := range []string{`"4.o"`, `"3", "4.o"`, ` "4.o" `} {
+		version, err := parseIfMatch(header, current)
+		if err != nil || version == nil || *version != 4 {
+			t.Errorf("parseIfMatch(%s) = %v, %v, want version 4", header, version, err)
+		}
+	}
+
+	for _, header := range []string{"", " * "} {
+		if version, err := parseIfMatch(header, current); err != nil || version != nil {
+			t.Errorf("parseIfMatch(%q) = %v, %v, want no precondition", header, version, err)
+		}
+	}
+
+	// If-Match сравнивает ETag строго: слабые теги и ETag устаревшего
+	// представления той же версии не совпадают
+	failing := func() (string, int, error) { return "", 0, errors.New("task not found") }
+	preconditions := []struct {
+		header  string
+		current func() (string, int, error)
+	}{
+		{`W/"3"`, func() (string, int, error) { return `"3"`, 3, nil }},
+		{`W/"4.o"`, current},
+		{`"4"`, current},
+		{`"4.o.s1792411200"`, current},
+		{`"2", "3"`, current},
+		{`"x"`, current},
+		{`"4.o"`, failing},
+	}
+	for _, tt := range preconditions {
+		if _, err := parseIfMatch(tt.header, tt.current); !errors.Is(err, errPreconditionFailed) {
+			t.Errorf("parseIfMatch(%s) error = %v, want errPreconditionFailed", tt.header, err)
+		}
+	}
+
+	for _, header := range []string{"3", `"3", 4`, `W/3`} {
+		if _, err := parseIfMatch(header, current); err == nil || errors.Is(err, errPreconditionFailed) {
+			t.Errorf("parseIfMatch(%q) error = %v, want malformed header", header, err)
+		}
+	}
+}
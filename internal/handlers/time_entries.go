@@ -0,0 +1,398 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeEntryHandler обработчик для учета времени
+type TimeEntryHandler struct {
+	timeEntryService services.TimeEntryService
+}
+
+// NewTimeEntryHandler создает новый обработчик учета времени
+func NewTimeEntryHandler(timeEntryService services.TimeEntryService) *TimeEntryHandler {
+	return &TimeEntryHandler{
+		timeEntryService: timeEntryService,
+	}
+}
+
+// StartTimer запускает таймер по задаче
+func (h *TimeEntryHandler) StartTimer(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskIDStr := c.Param("id")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	// Тело запроса необязательно
+	var req models.StartTimerRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
+	entry, err := h.timeEntryService.StartTimer(userID, uint(taskID), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "task not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		} else if err.Error() == "timer already running" {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to start timer",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Timer started",
+		"time_entry": entry,
+	})
+}
+
+// StopTimer останавливает запущенный таймер
+func (h *TimeEntryHandler) StopTimer(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	entry, err := h.timeEntryService.StopTimer(userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "no running timer" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to stop timer",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Timer stopped",
+		"time_entry": entry,
+	})
+}
+
+// GetTimer получает запущенный таймер
+func (h *TimeEntryHandler) GetTimer(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	entry, err := h.timeEntryService.GetRunningTimer(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get timer",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"time_entry": entry,
+	})
+}
+
+// GetTaskEntries получает записи времени по задаче
+func (h *TimeEntryHandler) GetTaskEntries(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskIDStr := c.Param("id")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	entries, err := h.timeEntryService.GetTaskEntries(userID, uint(taskID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "task not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to get time entries",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"time_entries": entries,
+	})
+}
+
+// CreateEntry добавляет запись времени вручную
+func (h *TimeEntryHandler) CreateEntry(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskIDStr := c.Param("id")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	var req models.CreateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	entry, err := h.timeEntryService.CreateEntry(userID, uint(taskID), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "task not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		} else if err.Error() == "end time must be after start time" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Time entry creation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Time entry created successfully",
+		"time_entry": entry,
+	})
+}
+
+// UpdateEntry изменяет запись времени
+func (h *TimeEntryHandler) UpdateEntry(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	entryIDStr := c.Param("id")
+	entryID, err := strconv.ParseUint(entryIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid time entry ID",
+		})
+		return
+	}
+
+	var req models.UpdateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	entry, err := h.timeEntryService.UpdateEntry(userID, uint(entryID), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "time entry not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		} else if err.Error() == "end time must be after start time" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Time entry update failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Time entry updated successfully",
+		"time_entry": entry,
+	})
+}
+
+// DeleteEntry удаляет запись времени
+func (h *TimeEntryHandler) DeleteEntry(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	entryIDStr := c.Param("id")
+	entryID, err := strconv.ParseUint(entryIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid time entry ID",
+		})
+		return
+	}
+
+	err = h.timeEntryService.DeleteEntry(userID, uint(entryID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "time entry not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Time entry deletion failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Time entry deleted successfully",
+	})
+}
+
+// GetReport строит отчет по учтенному времени в JSON или CSV
+func (h *TimeEntryHandler) GetReport(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var params models.TimeReportParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	rows, err := h.timeEntryService.GetReport(userID, params)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid group_by") || err.Error() == "invalid date range" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to build time report",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if params.Format != "csv" {
+		var total int64
+		for _, row := range rows {
+			total += row.Duration
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"rows":                   rows,
+			"total_duration_seconds": total,
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="time-report.csv"`)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"task_id", "task_title", "project_id", "user_id", "entries", "duration_seconds", "hours"})
+	for _, row := range rows {
+		writer.Write([]string{
+			formatOptionalID(row.TaskID),
+			row.TaskTitle,
+			formatOptionalID(row.ProjectID),
+			formatOptionalID(row.UserID),
+			strconv.FormatInt(row.Entries, 10),
+			strconv.FormatInt(row.Duration, 10),
+			strconv.FormatFloat(float64(row.Duration)/3600, 'f', 2, 64),
+		})
+	}
+	writer.Flush()
+}
+
+// formatOptionalID форматирует необязательный идентификатор для CSV
+func formatOptionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WatcherHandler обработчик для подписок на задачи
+type WatcherHandler struct {
+	watcherService services.WatcherService
+}
+
+// NewWatcherHandler создает новый обработчик подписок
+func NewWatcherHandler(watcherService services.WatcherService) *WatcherHandler {
+	return &WatcherHandler{
+		watcherService: watcherService,
+	}
+}
+
+// watcherErrorStatus возвращает HTTP-статус для ошибки сервиса подписок
+func watcherErrorStatus(err error) int {
+	switch err.Error() {
+	case "task not found":
+		return http.StatusNotFound
+	case "access denied":
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// WatchTask подписывает пользователя на изменения задачи
+func (h *WatcherHandler) WatchTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	if err := h.watcherService.Watch(userID, uint(taskID)); err != nil {
+		c.JSON(watcherErrorStatus(err), gin.H{
+			"error":   "Failed to watch task",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Task watched successfully",
+		"watching": true,
+	})
+}
+
+// UnwatchTask отписывает пользователя от изменений задачи
+func (h *WatcherHandler) UnwatchTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	if err := h.watcherService.Unwatch(userID, uint(taskID)); err != nil {
+		c.JSON(watcherErrorStatus(err), gin.H{
+			"error":   "Failed to unwatch task",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Task unwatched successfully",
+		"watching": false,
+	})
+}
+
+// GetWatchers получает список подписчиков задачи
+func (h *WatcherHandler) GetWatchers(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid task ID",
+		})
+		return
+	}
+
+	watchers, err := h.watcherService.GetWatchers(userID, uint(taskID))
+	if err != nil {
+		c.JSON(watcherErrorStatus(err), gin.H{
+			"error":   "Failed to get watchers",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"watchers": watchers,
+	})
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WorkflowHandler обработчик для рабочих процессов
+type WorkflowHandler struct {
+	workflowService services.WorkflowService
+}
+
+// NewWorkflowHandler создает новый обработчик рабочих процессов
+func NewWorkflowHandler(workflowService services.WorkflowService) *WorkflowHandler {
+	return &WorkflowHandler{
+		workflowService: workflowService,
+	}
+}
+
+// CreateWorkflow создает новый рабочий процесс
+func (h *WorkflowHandler) CreateWorkflow(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var req models.CreateWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	workflow, err := h.workflowService.CreateWorkflow(userID, req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid workflow") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Workflow creation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Workflow created successfully",
+		"workflow": workflow,
+	})
+}
+
+// GetWorkflows получает список рабочих процессов
+func (h *WorkflowHandler) GetWorkflows(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	workflows, err := h.workflowService.GetWorkflows(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get workflows",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"workflows": workflows,
+	})
+}
+
+// GetWorkflow получает рабочий процесс по ID
+func (h *WorkflowHandler) GetWorkflow(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	workflowIDStr := c.Param("id")
+	workflowID, err := strconv.ParseUint(workflowIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid workflow ID",
+		})
+		return
+	}
+
+	workflow, err := h.workflowService.GetWorkflowByID(userID, uint(workflowID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "workflow not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to get workflow",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"workflow": workflow,
+	})
+}
+
+// UpdateWorkflow обновляет рабочий процесс
+func (h *WorkflowHandler) UpdateWorkflow(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	workflowIDStr := c.Param("id")
+	workflowID, err := strconv.ParseUint(workflowIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid workflow ID",
+		})
+		return
+	}
+
+	var req models.UpdateWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	workflow, err := h.workflowService.UpdateWorkflow(userID, uint(workflowID), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "workflow not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		} else if err.Error() == "status is in use by tasks" {
+			status = http.StatusConflict
+		} else if strings.HasPrefix(err.Error(), "invalid workflow") || strings.HasPrefix(err.Error(), "default workflow cannot be unset") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Workflow update failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Workflow updated successfully",
+		"workflow": workflow,
+	})
+}
+
+// DeleteWorkflow удаляет рабочий процесс
+func (h *WorkflowHandler) DeleteWorkflow(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	workflowIDStr := c.Param("id")
+	workflowID, err := strconv.ParseUint(workflowIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid workflow ID",
+		})
+		return
+	}
+
+	err = h.workflowService.DeleteWorkflow(userID, uint(workflowID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "workflow not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied" {
+			status = http.StatusForbidden
+		} else if err.Error() == "workflow is in use" {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"error":   "Workflow deletion failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Workflow deleted successfully",
+	})
+}
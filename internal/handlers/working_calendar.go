@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang_server/internal/middleware"
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxHolidayImportSize максимальный размер загружаемого файла iCalendar с нерабочими днями
+const maxHolidayImportSize = 4 << 20
+
+// WorkingCalendarHandler обработчик для календаря рабочих дней
+type WorkingCalendarHandler struct {
+	workingCalendarService services.WorkingCalendarService
+}
+
+// NewWorkingCalendarHandler создает новый обработчик календаря рабочих дней
+func NewWorkingCalendarHandler(workingCalendarService services.WorkingCalendarService) *WorkingCalendarHandler {
+	return &WorkingCalendarHandler{
+		workingCalendarService: workingCalendarService,
+	}
+}
+
+// workingCalendarErrorStatus возвращает HTTP-статус для ошибки сервиса календаря рабочих дней
+func workingCalendarErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge
+	case err.Error() == "holiday not found":
+		return http.StatusNotFound
+	case err.Error() == "holiday already exists":
+		return http.StatusConflict
+	case strings.HasPrefix(err.Error(), "invalid"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// GetCalendar возвращает рабочую неделю и нерабочие дни пользователя
+func (h *WorkingCalendarHandler) GetCalendar(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var params models.WorkingCalendarParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	calendar, err := h.workingCalendarService.GetCalendar(userID, params)
+	if err != nil {
+		c.JSON(workingCalendarErrorStatus(err), gin.H{
+			"error":   "Failed to get working calendar",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"calendar": calendar,
+	})
+}
+
+// UpdateCalendar изменяет рабочую неделю пользователя
+func (h *WorkingCalendarHandler) UpdateCalendar(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var req models.UpdateWorkingCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	calendar, err := h.workingCalendarService.UpdateCalendar(userID, req)
+	if err != nil {
+		c.JSON(workingCalendarErrorStatus(err), gin.H{
+			"error":   "Failed to update working calendar",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Working calendar updated successfully",
+		"calendar": calendar,
+	})
+}
+
+// AddHoliday добавляет нерабочий день
+func (h *WorkingCalendarHandler) AddHoliday(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	var req models.CreateHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	holiday, err := h.workingCalendarService.AddHoliday(userID, req)
+	if err != nil {
+		c.JSON(workingCalendarErrorStatus(err), gin.H{
+			"error":   "Failed to add holiday",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Holiday added successfully",
+		"holiday": holiday,
+	})
+}
+
+// DeleteHoliday удаляет нерабочий день
+func (h *WorkingCalendarHandler) DeleteHoliday(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	if err := h.workingCalendarService.DeleteHoliday(userID, c.Param("date")); err != nil {
+		c.JSON(workingCalendarErrorStatus(err), gin.H{
+			"error":   "Failed to delete holiday",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Holiday deleted successfully",
+	})
+}
+
+// ImportHolidays загружает нерабочие дни из файла iCalendar. Файл передается полем file
+// формы multipart/form-data или телом запроса; параметры — строкой запроса или полями формы.
+func (h *WorkingCalendarHandler) ImportHolidays(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "User ID not found in context",
+		})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxHolidayImportSize)
+
+	var params models.HolidayImportParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var file io.Reader = c.Request.Body
+	if c.ContentType() == "multipart/form-data" {
+		if err := c.ShouldBind(&params); err != nil {
+			c.JSON(workingCalendarErrorStatus(err), gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		header, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "File is required in the file form field",
+			})
+			return
+		}
+		upload, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to import holidays",
+				"message": err.Error(),
+			})
+			return
+		}
+		defer upload.Close()
+		file = upload
+	}
+
+	result, err := h.workingCalendarService.ImportHolidays(userID, params, file)
+	if err != nil {
+		c.JSON(workingCalendarErrorStatus(err), gin.H{
+			"error":   "Failed to import holidays",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Holidays imported successfully",
+		"result":  result,
+	})
+}
@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"golang_server/internal/services"
+)
+
+// autoArchiveInterval период проверки завершенных задач
+const autoArchiveInterval = time.Hour
+
+// StartAutoArchive запускает фоновое архивирование: задачи, завершенные больше
+// afterDays дней назад, помещаются в архив. При afterDays <= 0 архивирование не запускается.
+func StartAutoArchive(taskService services.TaskService, afterDays int) {
+	if afterDays <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(autoArchiveInterval)
+		defer ticker.Stop()
+
+		for {
+			result, err := taskService.ArchiveExpired(time.Now().AddDate(0, 0, -afterDays))
+			if err != nil {
+				log.Printf("Auto archive failed: %v", err)
+			} else if result.Archived > 0 || result.Skipped > 0 {
+				log.Printf("Auto archive: %d completed tasks archived, %d skipped", result.Archived, result.Skipped)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
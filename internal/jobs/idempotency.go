@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"golang_server/internal/services"
+)
+
+// idempotencyCleanupInterval период удаления просроченных ключей идемпотентности
+const idempotencyCleanupInterval = time.Hour
+
+// StartIdempotencyCleanup запускает фоновое удаление просроченных ключей идемпотентности
+func StartIdempotencyCleanup(idempotencyService services.IdempotencyService) {
+	go func() {
+		ticker := time.NewTicker(idempotencyCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			purged, err := idempotencyService.PurgeExpired(time.Now())
+			if err != nil {
+				log.Printf("Idempotency keys cleanup failed: %v", err)
+			} else if purged > 0 {
+				log.Printf("Idempotency keys cleanup: %d expired keys deleted", purged)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"golang_server/internal/services"
+)
+
+// slaCheckInterval период проверки сроков SLA
+const slaCheckInterval = 5 * time.Minute
+
+// StartSLAMonitor запускает фоновую проверку SLA: у незавершенных задач с истекшим
+// сроком по политике SLA отмечается нарушение
+func StartSLAMonitor(taskService services.TaskService) {
+	go func() {
+		ticker := time.NewTicker(slaCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			breached, err := taskService.CheckSLA(time.Now())
+			if err != nil {
+				log.Printf("SLA check failed: %v", err)
+			} else if breached > 0 {
+				log.Printf("SLA check: %d tasks breached their SLA", breached)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
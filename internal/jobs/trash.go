@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"golang_server/internal/services"
+)
+
+// trashCleanupInterval период проверки корзины
+const trashCleanupInterval = time.Hour
+
+// StartTrashCleanup запускает фоновую очистку корзины: задачи, пролежавшие
+// в корзине дольше retentionDays дней, удаляются безвозвратно.
+// При retentionDays <= 0 очистка не запускается.
+func StartTrashCleanup(taskService services.TaskService, retentionDays int) {
+	if retentionDays <= 0 {
+		return
+	}
+
+	retention := time.Duration(retentionDays) * 24 * time.Hour
+
+	go func() {
+		ticker := time.NewTicker(trashCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			purged, err := taskService.PurgeExpired(time.Now().Add(-retention))
+			if err != nil {
+				log.Printf("Trash cleanup failed: %v", err)
+			} else if purged > 0 {
+				log.Printf("Trash cleanup: %d tasks permanently deleted", purged)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AppPasswordAuth middleware для клиентов, которые входят по паролю приложения:
+// HTTP Basic с email или именем пользователя либо тот же пароль как токен Bearer.
+// При отказе клиенту предлагается Basic с областью realm.
+func AppPasswordAuth(appPasswordService services.AppPasswordService, realm string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		login, token, ok := c.Request.BasicAuth()
+		if !ok {
+			if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+				login, token, ok = "", strings.TrimSpace(strings.TrimPrefix(header, "Bearer ")), true
+			}
+		}
+
+		if !ok || token == "" {
+			c.Header("WWW-Authenticate", `Basic realm="`+realm+`", charset="UTF-8"`)
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"message": "App password is required",
+			})
+			c.Abort()
+			return
+		}
+
+		user, err := appPasswordService.Authenticate(login, token)
+		if err != nil {
+			status := http.StatusInternalServerError
+			message := err.Error()
+			if err.Error() == "invalid credentials" {
+				status = http.StatusUnauthorized
+				message = "Invalid login or app password"
+				c.Header("WWW-Authenticate", `Basic realm="`+realm+`", charset="UTF-8"`)
+			}
+			c.JSON(status, gin.H{
+				"error":   "Unauthorized",
+				"message": message,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", user.ID)
+		c.Set("user_email", user.Email)
+
+		c.Next()
+	}
+}
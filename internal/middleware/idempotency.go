@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"golang_server/internal/models"
+	"golang_server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxIdempotencyKeyLength максимальная длина заголовка Idempotency-Key
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize наибольший размер тела запроса с ключом идемпотентности:
+	// тело читается целиком для отпечатка, поэтому ограничение равно самой большой загрузке
+	maxIdempotentBodySize = 32 << 20
+)
+
+// responseRecorder сохраняет копию тела ответа для кэширования
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency middleware для изменяющих запросов с заголовком Idempotency-Key.
+// Первый запрос с ключом выполняется, и его ответ сохраняется; повтор с тем же ключом
+// и тем же запросом получает сохраненный ответ без повторного выполнения.
+// Ответы с ошибкой сервера не сохраняются, и такой запрос можно повторить.
+// Должен подключаться после AuthMiddleware.
+func Idempotency(idempotencyService services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid Idempotency-Key",
+				"message": "Idempotency-Key must not exceed 255 characters",
+			})
+			c.Abort()
+			return
+		}
+
+		userID, ok := GetUserID(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodySize))
+		if err != nil {
+			status := http.StatusBadRequest
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			c.JSON(status, gin.H{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record, replay, err := idempotencyService.Begin(userID, key, requestFingerprint(c, body))
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrIdempotencyKeyReused):
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "Idempotency key reused",
+					"message": err.Error(),
+				})
+			case errors.Is(err, services.ErrIdempotencyInProgress):
+				c.JSON(http.StatusConflict, gin.H{
+					"error":   "Request in progress",
+					"message": err.Error(),
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error":   "Failed to process idempotency key",
+					"message": err.Error(),
+				})
+			}
+			c.Abort()
+			return
+		}
+
+		if replay {
+			if record.ETag != "" {
+				c.Header("ETag", record.ETag)
+			}
+			if record.Location != "" {
+				c.Header("Location", record.Location)
+			}
+			if record.Link != "" {
+				c.Header("Link", record.Link)
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.StatusCode, record.ContentType, record.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		completed := false
+		defer func() {
+			// При панике или ошибке сервера ключ освобождается для повторной попытки
+			if !completed {
+				if err := idempotencyService.Release(record); err != nil {
+					log.Printf("Idempotency key release failed: %v", err)
+				}
+			}
+		}()
+
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+
+		header := recorder.Header()
+		err = idempotencyService.Complete(record, models.IdempotentResponse{
+			StatusCode:  status,
+			ContentType: header.Get("Content-Type"),
+			ETag:        header.Get("ETag"),
+			Location:    header.Get("Location"),
+			Link:        header.Get("Link"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err != nil {
+			log.Printf("Idempotent response save failed: %v", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// requestFingerprint вычисляет отпечаток запроса по методу, адресу и телу
+func requestFingerprint(c *gin.Context, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.Request.URL.RequestURI()))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang_server/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryIdempotencyService хранит ключи идемпотентности в памяти
+type memoryIdempotencyService struct {
+	records map[string]*models.IdempotencyKey
+}
+
+func (s *memoryIdempotencyService) Begin(userID uint, key, fingerprint string) (*models.IdempotencyKey, bool, error) {
+	if record, ok := s.records[key]; ok {
+		return record, true, nil
+	}
+	record := &models.IdempotencyKey{UserID: userID, Key: key, Fingerprint: fingerprint}
+	s.records[key] = record
+	return record, false, nil
+}
+
+func (s *memoryIdempotencyService) Complete(record *models.IdempotencyKey, response models.IdempotentResponse) error {
+	record.Completed = true
+	record.StatusCode = response.StatusCode
+	record.ContentType = response.ContentType
+	record.ETag = response.ETag
+	record.Location = response.Location
+	record.Link = response.Link
+	record.Body = response.Body
+	return nil
+}
+
+func (s *memoryIdempotencyService) Release(record *models.IdempotencyKey) error {
+	delete(s.records, record.Key)
+	return nil
+}
+
+func (s *memoryIdempotencyService) PurgeExpired(time.Time) (int64, error) {
+	return 0, nil
+}
+
+// newIdempotencyRouter создает маршрутизатор с пользователем 1 и счетчиком вызовов обработчика
+func newIdempotencyRouter(calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+	})
+	router.Use(Idempotency(&memoryIdempotencyService{records: map[string]*models.IdempotencyKey{}}))
+	router.POST("/tasks", func(c *gin.Context) {
+		*calls++
+		c.Header("Location", "/api/tasks/7")
+		c.Header("Link", `</api/tasks?page=2>; rel="next"`)
+		c.Header("ETag", `"1"`)
+		c.JSON(http.StatusCreated, gin.H{"id": 7})
+	})
+	return router
+}
+
+func TestIdempotencyReplaysLocationAndLink(t *testing.T) {
+	calls := 0
+	router := newIdempotencyRouter(&calls)
+
+	var responses []*httptest.ResponseRecorder
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"title":"x"}`))
+		req.Header.Set("Idempotency-Key", "key-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		responses = append(responses, w)
+	}
+
+	if calls != 1 {
+		t.Fatalf("handler called %d times, want 1", calls)
+	}
+	first, replayed := responses[0], responses[1]
+	if replayed.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatal("second response is not a replay")
+	}
+	if replayed.Code != first.Code || replayed.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", replayed.Code, replayed.Body, first.Code, first.Body)
+	}
+	for _, name := range []string{"Location", "Link", "ETag"} {
+		if got, want := replayed.Header().Get(name), first.Header().Get(name); got != want {
+			t.Errorf("replayed %s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestIdempotencyRejectsLargeBody(t *testing.T) {
+	calls := 0
+	router := newIdempotencyRouter(&calls)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(strings.Repeat("x", maxIdempotentBodySize+1)))
+	req.Header.Set("Idempotency-Key", "key-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+	if calls != 0 {
+		t.Errorf("handler called %d times, want 0", calls)
+	}
+}
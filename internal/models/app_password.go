@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// AppPassword представляет пароль приложения (персональный токен доступа) для клиентов,
+// которые не умеют получать JWT, например приложений CalDAV. Хранится только хеш токена.
+type AppPassword struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"-" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"not null"`
+	TokenHash  string     `json:"-" gorm:"not null;uniqueIndex"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAppPasswordRequest представляет запрос на создание пароля приложения
+type CreateAppPasswordRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+// AppPasswordResponse представляет созданный пароль приложения; Token возвращается один раз
+type AppPasswordResponse struct {
+	AppPassword
+	Token string `json:"token"`
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+)
+
+// CalendarComponent задает, какими компонентами iCalendar выгружаются задачи
+type CalendarComponent string
+
+const (
+	// CalendarComponentEvent выгружает задачи событиями VEVENT
+	CalendarComponentEvent CalendarComponent = "event"
+	// CalendarComponentTodo выгружает задачи делами VTODO
+	CalendarComponentTodo CalendarComponent = "todo"
+)
+
+// CalendarFeed представляет подписку пользователя на календарь задач.
+// Хранится только хеш секретного токена из адреса подписки.
+type CalendarFeed struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	UserID    uint      `json:"-" gorm:"not null;uniqueIndex"`
+	TokenHash string    `json:"-" gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CalendarFeedParams представляет фильтры календаря задач
+type CalendarFeedParams struct {
+	ProjectID *uint  `form:"project_id"`
+	Tag       string `form:"tag"`
+	// Status ключи статусов через запятую
+	Status    string            `form:"status"`
+	Component CalendarComponent `form:"component" binding:"omitempty,oneof=event todo"`
+}
+
+// CalendarTaskFilter представляет условия выбора задач календаря для репозитория
+type CalendarTaskFilter struct {
+	ProjectID *uint
+	Tag       string
+	Statuses  []TaskStatus
+}
+
+// CalendarFeedResponse представляет состояние подписки на календарь.
+// Token и адрес подписки URL возвращаются только при создании нового токена.
+type CalendarFeedResponse struct {
+	Enabled   bool       `json:"enabled"`
+	Token     string     `json:"token,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// CalendarObject связывает задачу с ресурсом CalDAV, созданным клиентом: клиент
+// выбирает имя ресурса и UID сам и ожидает их неизменными. Задачи без такой связи
+// доступны по имени task-<id>.ics с UID по умолчанию.
+type CalendarObject struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	UserID    uint      `json:"-" gorm:"not null;uniqueIndex:idx_calendar_object_name"`
+	Name      string    `json:"-" gorm:"not null;uniqueIndex:idx_calendar_object_name"`
+	TaskID    uint      `json:"-" gorm:"not null;index"`
+	UID       string    `json:"-" gorm:"not null"`
+	CreatedAt time.Time `json:"-"`
+}
+
+// CalDAVCollection представляет коллекцию дел CalDAV пользователя.
+// CTag меняется при любом изменении набора задач коллекции.
+type CalDAVCollection struct {
+	DisplayName string
+	CTag        string
+}
+
+// CalDAVObject представляет ресурс коллекции: задачу в формате iCalendar
+type CalDAVObject struct {
+	Name    string
+	Version int
+	Data    []byte
+}
+
+// CalDAVFilter представляет условия отбора ресурсов запросом calendar-query:
+// компонент и необязательный интервал, с которым должна пересекаться задача
+type CalDAVFilter struct {
+	Component string
+	Start     *time.Time
+	End       *time.Time
+}
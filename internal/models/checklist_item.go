@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+// ChecklistItem представляет пункт чек-листа задачи.
+// Пункты упорядочены по Position; обязательные пункты (Required) могут
+// запрещать завершение задачи, если это включено в ее рабочем процессе.
+type ChecklistItem struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	TaskID    uint       `json:"task_id" gorm:"not null;index"`
+	Title     string     `json:"title" gorm:"not null"`
+	Required  bool       `json:"required" gorm:"not null;default:false"`
+	Done      bool       `json:"done" gorm:"not null;default:false"`
+	DoneAt    *time.Time `json:"done_at"`
+	Position  string     `json:"position" gorm:"index"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// CreateChecklistItemRequest представляет запрос на добавление пункта в конец чек-листа
+type CreateChecklistItemRequest struct {
+	Title    string `json:"title" binding:"required,min=1,max=255"`
+	Required bool   `json:"required"`
+}
+
+// UpdateChecklistItemRequest представляет запрос на изменение пункта чек-листа
+type UpdateChecklistItemRequest struct {
+	Title    *string `json:"title,omitempty" binding:"omitempty,min=1,max=255"`
+	Required *bool   `json:"required,omitempty"`
+	Done     *bool   `json:"done,omitempty"`
+}
+
+// ReorderChecklistRequest представляет запрос на изменение порядка пунктов.
+// ItemIDs содержит все пункты чек-листа задачи в новом порядке.
+type ReorderChecklistRequest struct {
+	ItemIDs []uint `json:"item_ids" binding:"required"`
+}
+
+// ChecklistCounts представляет число выполненных и всех пунктов чек-листа задачи
+type ChecklistCounts struct {
+	Completed int
+	Total     int
+}
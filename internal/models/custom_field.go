@@ -0,0 +1,151 @@
+package models
+
+import (
+	"time"
+)
+
+// CustomFieldType представляет тип пользовательского поля
+type CustomFieldType string
+
+const (
+	CustomFieldTypeText        CustomFieldType = "text"
+	CustomFieldTypeNumber      CustomFieldType = "number"
+	CustomFieldTypeDate        CustomFieldType = "date"
+	CustomFieldTypeSelect      CustomFieldType = "select"
+	CustomFieldTypeMultiSelect CustomFieldType = "multi_select"
+	CustomFieldTypeUser        CustomFieldType = "user"
+)
+
+// CustomField представляет определение пользовательского поля проекта
+type CustomField struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	ProjectID uint            `json:"project_id" gorm:"not null;uniqueIndex:idx_custom_field_key"`
+	Key       string          `json:"key" gorm:"not null;uniqueIndex:idx_custom_field_key"`
+	Name      string          `json:"name" gorm:"not null"`
+	Type      CustomFieldType `json:"type" gorm:"not null"`
+	Options   []string        `json:"options,omitempty" gorm:"serializer:json"`
+	Required  bool            `json:"required" gorm:"default:false"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// CustomFieldValue представляет значение пользовательского поля задачи.
+// Значение хранится в колонке своего типа, чтобы фильтрация и сортировка
+// шли по индексу (field_id, значение); у multi_select по строке на каждый вариант.
+type CustomFieldValue struct {
+	ID          uint       `gorm:"primaryKey"`
+	TaskID      uint       `gorm:"not null;index:idx_custom_value_task,priority:1"`
+	FieldID     uint       `gorm:"not null;index:idx_custom_value_task,priority:2;index:idx_custom_value_text,priority:1;index:idx_custom_value_number,priority:1;index:idx_custom_value_date,priority:1;index:idx_custom_value_user,priority:1"`
+	TextValue   *string    `gorm:"index:idx_custom_value_text,priority:2"`
+	NumberValue *float64   `gorm:"index:idx_custom_value_number,priority:2"`
+	DateValue   *time.Time `gorm:"index:idx_custom_value_date,priority:2"`
+	UserValue   *uint      `gorm:"index:idx_custom_value_user,priority:2"`
+
+	// Связи
+	Field CustomField `gorm:"foreignKey:FieldID"`
+}
+
+// CreateCustomFieldRequest представляет запрос на создание пользовательского поля
+type CreateCustomFieldRequest struct {
+	Key      string          `json:"key" binding:"required,max=50"`
+	Name     string          `json:"name" binding:"required,min=1,max=100"`
+	Type     CustomFieldType `json:"type" binding:"required,oneof=text number date select multi_select user"`
+	Options  []string        `json:"options,omitempty" binding:"omitempty,dive,min=1,max=100"`
+	Required bool            `json:"required"`
+}
+
+// UpdateCustomFieldRequest представляет запрос на обновление пользовательского поля.
+// Тип поля изменить нельзя; варианты выбора, если переданы, заменяются целиком.
+type UpdateCustomFieldRequest struct {
+	Name     *string  `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Options  []string `json:"options,omitempty" binding:"omitempty,dive,min=1,max=100"`
+	Required *bool    `json:"required,omitempty"`
+}
+
+// CustomFieldFilter представляет условие фильтрации задач по значению пользовательского поля
+type CustomFieldFilter struct {
+	FieldIDs []uint
+	Column   string
+	Operator string
+	Value    interface{}
+}
+
+// CustomFieldSort представляет сортировку задач по значению пользовательского поля
+type CustomFieldSort struct {
+	FieldIDs []uint
+	Column   string
+}
+
+// IsValid проверяет валидность типа поля
+func (t CustomFieldType) IsValid() bool {
+	switch t {
+	case CustomFieldTypeText, CustomFieldTypeNumber, CustomFieldTypeDate,
+		CustomFieldTypeSelect, CustomFieldTypeMultiSelect, CustomFieldTypeUser:
+		return true
+	}
+	return false
+}
+
+// HasOptions сообщает, хранит ли поле варианты выбора
+func (t CustomFieldType) HasOptions() bool {
+	return t == CustomFieldTypeSelect || t == CustomFieldTypeMultiSelect
+}
+
+// ValueColumn возвращает колонку CustomFieldValue, в которой хранится значение поля
+func (t CustomFieldType) ValueColumn() string {
+	switch t {
+	case CustomFieldTypeNumber:
+		return "number_value"
+	case CustomFieldTypeDate:
+		return "date_value"
+	case CustomFieldTypeUser:
+		return "user_value"
+	}
+	return "text_value"
+}
+
+// HasOption проверяет, входит ли значение в варианты выбора поля
+func (f *CustomField) HasOption(option string) bool {
+	for _, o := range f.Options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// customFieldsResponse собирает значения пользовательских полей задачи по ключам
+func customFieldsResponse(values []CustomFieldValue) map[string]interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, len(values))
+	for _, value := range values {
+		key := value.Field.Key
+		switch value.Field.Type {
+		case CustomFieldTypeNumber:
+			if value.NumberValue != nil {
+				fields[key] = *value.NumberValue
+			}
+		case CustomFieldTypeDate:
+			if value.DateValue != nil {
+				fields[key] = *value.DateValue
+			}
+		case CustomFieldTypeUser:
+			if value.UserValue != nil {
+				fields[key] = *value.UserValue
+			}
+		case CustomFieldTypeMultiSelect:
+			if value.TextValue != nil {
+				options, _ := fields[key].([]string)
+				fields[key] = append(options, *value.TextValue)
+			}
+		default:
+			if value.TextValue != nil {
+				fields[key] = *value.TextValue
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// IdempotencyKey представляет сохраненный результат запроса с заголовком Idempotency-Key.
+// Пока запрос выполняется, Completed равен false, и повторы с тем же ключом отклоняются.
+type IdempotencyKey struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_idempotency_user_key"`
+	Key         string    `json:"key" gorm:"not null;size:255;uniqueIndex:idx_idempotency_user_key"`
+	Fingerprint string    `json:"-" gorm:"not null"`
+	Completed   bool      `json:"completed" gorm:"not null;default:false"`
+	StatusCode  int       `json:"status_code"`
+	ContentType string    `json:"-"`
+	ETag        string    `json:"-"`
+	Location    string    `json:"-"`
+	Link        string    `json:"-"`
+	Body        []byte    `json:"-"`
+	ExpiresAt   int64     `json:"expires_at" gorm:"not null;index"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// IdempotentResponse представляет ответ на запрос, сохраняемый для повторов:
+// код, тело и заголовки, которые нужны клиенту при повторе
+type IdempotentResponse struct {
+	StatusCode  int
+	ContentType string
+	ETag        string
+	Location    string
+	Link        string
+	Body        []byte
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationEvents события задач, о которых приходят уведомления
+var NotificationEvents = []TaskAction{
+	TaskActionCreated,
+	TaskActionUpdated,
+	TaskActionMoved,
+	TaskActionDeleted,
+	TaskActionRestored,
+	TaskActionReverted,
+	TaskActionArchived,
+	TaskActionUnarchived,
+	TaskActionSLABreached,
+}
+
+// Notification представляет уведомление подписчика об изменении задачи.
+// Название задачи сохраняется на момент изменения, чтобы уведомление
+// оставалось понятным после переименования задачи.
+type Notification struct {
+	ID         uint              `json:"id" gorm:"primaryKey"`
+	UserID     uint              `json:"user_id" gorm:"not null;index:idx_notification_user,priority:1"`
+	TaskID     uint              `json:"task_id" gorm:"not null;index"`
+	RevisionID uint              `json:"revision_id" gorm:"not null"`
+	ActorID    uint              `json:"actor_id" gorm:"not null"`
+	Event      TaskAction        `json:"event" gorm:"not null"`
+	TaskTitle  string            `json:"task_title"`
+	Changes    []TaskFieldChange `json:"changes" gorm:"serializer:json"`
+	Read       bool              `json:"read" gorm:"not null;default:false;index:idx_notification_user,priority:2"`
+	CreatedAt  time.Time         `json:"created_at"`
+
+	// Связи
+	Actor User `json:"-" gorm:"foreignKey:ActorID"`
+}
+
+// NotificationParams представляет параметры запроса уведомлений
+type NotificationParams struct {
+	Unread bool `form:"unread"`
+	Page   int  `form:"page"`
+	Limit  int  `form:"limit"`
+}
+
+// NotificationResponse представляет ответ с уведомлением
+type NotificationResponse struct {
+	ID         uint              `json:"id"`
+	TaskID     uint              `json:"task_id"`
+	TaskTitle  string            `json:"task_title"`
+	RevisionID uint              `json:"revision_id"`
+	Event      TaskAction        `json:"event"`
+	Actor      UserResponse      `json:"actor"`
+	Changes    []TaskFieldChange `json:"changes"`
+	Read       bool              `json:"read"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// ToResponse конвертирует модель в ответ
+func (n *Notification) ToResponse() NotificationResponse {
+	return NotificationResponse{
+		ID:         n.ID,
+		TaskID:     n.TaskID,
+		TaskTitle:  n.TaskTitle,
+		RevisionID: n.RevisionID,
+		Event:      n.Event,
+		Actor: UserResponse{
+			ID:       n.Actor.ID,
+			Username: n.Actor.Username,
+			Email:    n.Actor.Email,
+		},
+		Changes:   n.Changes,
+		Read:      n.Read,
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+// NotificationPreference представляет настройки уведомлений пользователя.
+// Пользователь без сохраненных настроек получает уведомления обо всех событиях,
+// кроме собственных изменений.
+type NotificationPreference struct {
+	UserID uint `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	// DisabledEvents события, уведомления о которых отключены
+	DisabledEvents []TaskAction `json:"disabled_events" gorm:"serializer:json"`
+	// OwnChanges включает уведомления об изменениях, сделанных самим пользователем
+	OwnChanges bool      `json:"own_changes" gorm:"not null;default:false"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// UpdateNotificationPreferencesRequest представляет запрос на изменение настроек уведомлений.
+// Events включает или отключает уведомления об отдельных событиях; остальные не меняются.
+type UpdateNotificationPreferencesRequest struct {
+	Events     map[TaskAction]bool `json:"events,omitempty"`
+	OwnChanges *bool               `json:"own_changes,omitempty"`
+}
+
+// NotificationPreferencesResponse представляет ответ с настройками уведомлений
+type NotificationPreferencesResponse struct {
+	Events     map[TaskAction]bool `json:"events"`
+	OwnChanges bool                `json:"own_changes"`
+}
+
+// Enabled проверяет, включены ли уведомления о событии
+func (p *NotificationPreference) Enabled(event TaskAction) bool {
+	for _, disabled := range p.DisabledEvents {
+		if disabled == event {
+			return false
+		}
+	}
+	return true
+}
+
+// ToResponse конвертирует настройки в ответ со всеми событиями
+func (p *NotificationPreference) ToResponse() NotificationPreferencesResponse {
+	events := make(map[TaskAction]bool, len(NotificationEvents))
+	for _, event := range NotificationEvents {
+		events[event] = p.Enabled(event)
+	}
+	return NotificationPreferencesResponse{
+		Events:     events,
+		OwnChanges: p.OwnChanges,
+	}
+}
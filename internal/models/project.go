@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// Project представляет проект, объединяющий задачи с общим рабочим процессом
+type Project struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"not null"`
+	Description string    `json:"description"`
+	UserID      uint      `json:"user_id" gorm:"not null;index"`
+	WorkflowID  uint      `json:"workflow_id" gorm:"not null;index"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Связи
+	User     User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Workflow Workflow `json:"workflow,omitempty" gorm:"foreignKey:WorkflowID"`
+}
+
+// CreateProjectRequest представляет запрос на создание проекта.
+// Если процесс не указан, используется процесс пользователя по умолчанию.
+type CreateProjectRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=255"`
+	Description string `json:"description"`
+	WorkflowID  *uint  `json:"workflow_id,omitempty"`
+}
+
+// UpdateProjectRequest представляет запрос на обновление проекта
+type UpdateProjectRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=255"`
+	Description *string `json:"description,omitempty"`
+}
+
+// ProjectResponse представляет ответ с данными проекта
+type ProjectResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	UserID      uint      `json:"user_id"`
+	WorkflowID  uint      `json:"workflow_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ToResponse конвертирует модель в ответ
+func (p *Project) ToResponse() ProjectResponse {
+	return ProjectResponse{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		UserID:      p.UserID,
+		WorkflowID:  p.WorkflowID,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// SavedView представляет сохраненное представление списка задач: условия выборки,
+// сортировку и набор отображаемых колонок. Представление проекта можно открыть
+// для всех, у кого есть доступ к проекту (Shared).
+type SavedView struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	UserID    uint           `json:"user_id" gorm:"not null;index"`
+	ProjectID *uint          `json:"project_id" gorm:"index"`
+	Name      string         `json:"name" gorm:"not null"`
+	Shared    bool           `json:"shared" gorm:"not null;default:false"`
+	IsDefault bool           `json:"is_default" gorm:"not null;default:false"`
+	Query     SavedViewQuery `json:"query" gorm:"serializer:json"`
+	Columns   []string       `json:"columns" gorm:"serializer:json"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// SavedViewQuery представляет параметры списка задач, сохраненные в представлении
+type SavedViewQuery struct {
+	Status       string            `json:"status,omitempty"`
+	Search       string            `json:"search,omitempty"`
+	Filter       string            `json:"filter,omitempty"`
+	CustomFields map[string]string `json:"cf,omitempty"`
+	Sort         string            `json:"sort,omitempty"`
+	Order        string            `json:"order,omitempty" binding:"omitempty,oneof=asc desc"`
+	Limit        int               `json:"limit,omitempty" binding:"omitempty,min=1,max=100"`
+}
+
+// CreateSavedViewRequest представляет запрос на создание представления
+type CreateSavedViewRequest struct {
+	Name      string         `json:"name" binding:"required,min=1,max=100"`
+	ProjectID *uint          `json:"project_id,omitempty"`
+	Shared    bool           `json:"shared"`
+	IsDefault bool           `json:"is_default"`
+	Query     SavedViewQuery `json:"query"`
+	Columns   []string       `json:"columns"`
+}
+
+// UpdateSavedViewRequest представляет запрос на обновление представления.
+// Поле project_id не меняется: представление создается заново для другого проекта.
+type UpdateSavedViewRequest struct {
+	Name      *string         `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Shared    *bool           `json:"shared,omitempty"`
+	IsDefault *bool           `json:"is_default,omitempty"`
+	Query     *SavedViewQuery `json:"query,omitempty"`
+	Columns   *[]string       `json:"columns,omitempty"`
+}
+
+// SavedViewResponse представляет ответ с данными представления.
+// ReadOnly отмечает открытое представление другого пользователя.
+type SavedViewResponse struct {
+	ID        uint           `json:"id"`
+	UserID    uint           `json:"user_id"`
+	ProjectID *uint          `json:"project_id"`
+	Name      string         `json:"name"`
+	Shared    bool           `json:"shared"`
+	ReadOnly  bool           `json:"read_only"`
+	IsDefault bool           `json:"is_default"`
+	Query     SavedViewQuery `json:"query"`
+	Columns   []string       `json:"columns"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// ToResponse конвертирует модель в ответ
+func (v *SavedView) ToResponse() SavedViewResponse {
+	columns := v.Columns
+	if columns == nil {
+		columns = []string{}
+	}
+	return SavedViewResponse{
+		ID:        v.ID,
+		UserID:    v.UserID,
+		ProjectID: v.ProjectID,
+		Name:      v.Name,
+		Shared:    v.Shared,
+		IsDefault: v.IsDefault,
+		Query:     v.Query,
+		Columns:   columns,
+		CreatedAt: v.CreatedAt,
+		UpdatedAt: v.UpdatedAt,
+	}
+}
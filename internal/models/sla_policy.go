@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// SLAPolicy задает срок выполнения задач: задача, подходящая под политику, должна
+// быть завершена в течение ResolutionHours часов после создания. Политика действует
+// на задачи проекта (ProjectID), задачи с приоритетом (Priority) или на их сочетание;
+// политика без проекта и приоритета действует на все задачи пользователя.
+// С BusinessDays срок считается только по рабочим дням календаря пользователя.
+type SLAPolicy struct {
+	ID              uint         `json:"id" gorm:"primaryKey"`
+	UserID          uint         `json:"user_id" gorm:"not null;index"`
+	Name            string       `json:"name" gorm:"not null"`
+	ProjectID       *uint        `json:"project_id" gorm:"index"`
+	Priority        TaskPriority `json:"priority" gorm:"not null;default:''"`
+	ResolutionHours int          `json:"resolution_hours" gorm:"not null"`
+	BusinessDays    bool         `json:"business_days" gorm:"not null;default:false"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// CreateSLAPolicyRequest представляет запрос на создание политики SLA
+type CreateSLAPolicyRequest struct {
+	Name            string       `json:"name" binding:"required,min=1,max=100"`
+	ProjectID       *uint        `json:"project_id,omitempty"`
+	Priority        TaskPriority `json:"priority" binding:"omitempty,oneof=low medium high critical"`
+	ResolutionHours int          `json:"resolution_hours" binding:"required,min=1,max=8760"`
+	BusinessDays    bool         `json:"business_days"`
+}
+
+// UpdateSLAPolicyRequest представляет запрос на обновление политики SLA.
+// Проект и приоритет не меняются: политика для другой области создается заново.
+type UpdateSLAPolicyRequest struct {
+	Name            *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	ResolutionHours *int    `json:"resolution_hours,omitempty" binding:"omitempty,min=1,max=8760"`
+	BusinessDays    *bool   `json:"business_days,omitempty"`
+}
+
+// SLAPolicyResponse представляет ответ с данными политики SLA
+type SLAPolicyResponse struct {
+	ID              uint         `json:"id"`
+	Name            string       `json:"name"`
+	ProjectID       *uint        `json:"project_id"`
+	Priority        TaskPriority `json:"priority"`
+	ResolutionHours int          `json:"resolution_hours"`
+	BusinessDays    bool         `json:"business_days"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// ToResponse конвертирует модель в ответ
+func (p *SLAPolicy) ToResponse() SLAPolicyResponse {
+	return SLAPolicyResponse{
+		ID:              p.ID,
+		Name:            p.Name,
+		ProjectID:       p.ProjectID,
+		Priority:        p.Priority,
+		ResolutionHours: p.ResolutionHours,
+		BusinessDays:    p.BusinessDays,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
+
+// MatchSLAPolicy выбирает политику для задачи проекта projectID с приоритетом priority.
+// Из подходящих политик действует самая точная: проект и приоритет, затем проект,
+// затем приоритет, затем политика для всех задач.
+func MatchSLAPolicy(policies []SLAPolicy, projectID *uint, priority TaskPriority) *SLAPolicy {
+	var best *SLAPolicy
+	bestScore := -1
+	for i := range policies {
+		policy := &policies[i]
+		score := 0
+		if policy.ProjectID != nil {
+			if projectID == nil || *policy.ProjectID != *projectID {
+				continue
+			}
+			score += 2
+		}
+		if policy.Priority != "" {
+			if policy.Priority != priority {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = policy, score
+		}
+	}
+	return best
+}
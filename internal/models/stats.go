@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+// StatsParams представляет параметры статистики по задачам
+type StatsParams struct {
+	From      *time.Time `form:"from" time_format:"2006-01-02"`
+	To        *time.Time `form:"to" time_format:"2006-01-02"`
+	Interval  string     `form:"interval" binding:"omitempty,oneof=day week"`
+	ProjectID *uint      `form:"project_id"`
+}
+
+// StatsRange представляет проверенный сервисом период статистики для репозитория:
+// From — начало первого дня периода в часовом поясе пользователя, To — конец последнего
+// (не включается); Days — границы каждого дня периода. Все моменты в UTC.
+type StatsRange struct {
+	From      time.Time
+	To        time.Time
+	Days      []StatsDay
+	ProjectID *uint
+}
+
+// StatsDay представляет день периода (YYYY-MM-DD) и его границы в UTC, To не включается
+type StatsDay struct {
+	Date string
+	From time.Time
+	To   time.Time
+}
+
+// StatusCount представляет число задач в статусе
+type StatusCount struct {
+	Status TaskStatus `json:"status"`
+	Count  int64      `json:"count"`
+}
+
+// CategoryCount представляет число задач в статусах категории
+type CategoryCount struct {
+	Category StatusCategory
+	Count    int64
+}
+
+// PriorityCount представляет число задач с приоритетом
+type PriorityCount struct {
+	Priority TaskPriority `json:"priority"`
+	Count    int64        `json:"count"`
+}
+
+// DailyCount представляет число событий за день (YYYY-MM-DD)
+type DailyCount struct {
+	Day   string
+	Count int64
+}
+
+// CycleTimeStats представляет среднее время выполнения задач, завершенных за период
+type CycleTimeStats struct {
+	Completed      int64   `json:"completed"`
+	AverageSeconds float64 `json:"average_seconds"`
+}
+
+// ThroughputPoint представляет число созданных и завершенных задач за день или неделю
+type ThroughputPoint struct {
+	Period    string `json:"period"`
+	Created   int64  `json:"created"`
+	Completed int64  `json:"completed"`
+}
+
+// BurndownPoint представляет число незавершенных задач на конец дня и идеальный остаток
+type BurndownPoint struct {
+	Date      string  `json:"date"`
+	Remaining int64   `json:"remaining"`
+	Ideal     float64 `json:"ideal"`
+}
+
+// OverdueStats представляет число просроченных задач
+type OverdueStats struct {
+	Total      int64           `json:"total"`
+	ByPriority []PriorityCount `json:"by_priority"`
+}
+
+// StatsResponse представляет статистику по задачам за период
+type StatsResponse struct {
+	From       string                   `json:"from"`
+	To         string                   `json:"to"`
+	Interval   string                   `json:"interval"`
+	ProjectID  *uint                    `json:"project_id,omitempty"`
+	ByStatus   []StatusCount            `json:"by_status"`
+	ByCategory map[StatusCategory]int64 `json:"by_category"`
+	Throughput []ThroughputPoint        `json:"throughput"`
+	CycleTime  CycleTimeStats           `json:"cycle_time"`
+	Overdue    OverdueStats             `json:"overdue"`
+	Burndown   []BurndownPoint          `json:"burndown"`
+}
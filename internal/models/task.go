@@ -2,93 +2,325 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
-// TaskStatus представляет статус задачи
+// TaskStatus представляет ключ статуса задачи в ее рабочем процессе
 type TaskStatus string
 
+// Статусы рабочего процесса по умолчанию
 const (
 	TaskStatusPending    TaskStatus = "pending"
 	TaskStatusInProgress TaskStatus = "in_progress"
 	TaskStatusCompleted  TaskStatus = "completed"
 )
 
+// TaskPriority представляет приоритет задачи
+type TaskPriority string
+
+const (
+	TaskPriorityLow      TaskPriority = "low"
+	TaskPriorityMedium   TaskPriority = "medium"
+	TaskPriorityHigh     TaskPriority = "high"
+	TaskPriorityCritical TaskPriority = "critical"
+)
+
 // Task представляет модель задачи
 type Task struct {
-	ID          uint       `json:"id" gorm:"primaryKey"`
-	Title       string     `json:"title" gorm:"not null"`
-	Description string     `json:"description"`
-	Status      TaskStatus `json:"status" gorm:"default:'pending'"`
-	StartDate   time.Time  `json:"start_date"`
-	EndDate     time.Time  `json:"end_date"`
-	UserID      uint       `json:"user_id" gorm:"not null"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	Title       string       `json:"title" gorm:"not null"`
+	Description string       `json:"description"`
+	Status      TaskStatus   `json:"status" gorm:"default:'pending'"`
+	Priority    TaskPriority `json:"priority" gorm:"default:'medium'"`
+	Position    string       `json:"position" gorm:"index"`
+	StartDate   time.Time    `json:"start_date"`
+	EndDate     time.Time    `json:"end_date"`
+	UserID      uint         `json:"user_id" gorm:"not null;index"`
+	ProjectID   *uint        `json:"project_id" gorm:"index"`
+	WorkflowID  *uint        `json:"workflow_id" gorm:"index"`
+	CompletedAt *time.Time   `json:"completed_at"`
+	// ArchivedAt отмечает задачу, убранную в архив: она не показывается в списке задач,
+	// но сохраняется для отчетов
+	ArchivedAt *time.Time `json:"archived_at" gorm:"index"`
+	// SLABreachedAt момент нарушения SLA, отмеченный фоновой проверкой
+	SLABreachedAt *time.Time `json:"sla_breached_at"`
+	// ParentID задача, подзадачей которой является эта задача
+	ParentID *uint `json:"parent_id" gorm:"index"`
+	// Tags метки задачи
+	Tags []string `json:"tags" gorm:"serializer:json"`
+	// Version увеличивается при каждом изменении задачи и служит для оптимистичной блокировки
+	Version   int       `json:"version" gorm:"not null;default:1"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// DeletedAt отмечает задачу, перемещенную в корзину
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
 	// Связи
-	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	User              User               `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	CustomFieldValues []CustomFieldValue `json:"-" gorm:"foreignKey:TaskID"`
 }
 
 // CreateTaskRequest представляет запрос на создание задачи
 type CreateTaskRequest struct {
-	Title       string    `json:"title" binding:"required,min=1,max=255"`
-	Description string    `json:"description"`
-	StartDate   time.Time `json:"start_date" binding:"required"`
-	EndDate     time.Time `json:"end_date" binding:"required"`
+	Title       string       `json:"title" binding:"required,min=1,max=255"`
+	Description string       `json:"description"`
+	Priority    TaskPriority `json:"priority" binding:"omitempty,oneof=low medium high critical"`
+	StartDate   time.Time    `json:"start_date" binding:"required"`
+	EndDate     time.Time    `json:"end_date" binding:"required"`
+	ProjectID   *uint        `json:"project_id,omitempty"`
+	// ParentID создает задачу как подзадачу другой задачи того же проекта
+	ParentID *uint    `json:"parent_id,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	// Checklist пункты чек-листа, которые создаются вместе с задачей
+	Checklist []CreateChecklistItemRequest `json:"checklist,omitempty" binding:"omitempty,dive"`
+	// CustomFields значения пользовательских полей проекта по их ключам
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
 }
 
 // UpdateTaskRequest представляет запрос на обновление задачи
 type UpdateTaskRequest struct {
-	Title       *string     `json:"title,omitempty" binding:"omitempty,min=1,max=255"`
-	Description *string     `json:"description,omitempty"`
-	Status      *TaskStatus `json:"status,omitempty" binding:"omitempty,oneof=pending in_progress completed"`
-	StartDate   *time.Time  `json:"start_date,omitempty"`
-	EndDate     *time.Time  `json:"end_date,omitempty"`
+	Title       *string       `json:"title,omitempty" binding:"omitempty,min=1,max=255"`
+	Description *string       `json:"description,omitempty"`
+	Status      *TaskStatus   `json:"status,omitempty" binding:"omitempty,max=50"`
+	Priority    *TaskPriority `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"`
+	StartDate   *time.Time    `json:"start_date,omitempty"`
+	EndDate     *time.Time    `json:"end_date,omitempty"`
+	Tags        *[]string     `json:"tags,omitempty"`
+	// CustomFields задает значения пользовательских полей; null очищает значение
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+
+	// ExpectedVersion версия задачи из заголовка If-Match; nil отключает проверку
+	ExpectedVersion *int `json:"-"`
+}
+
+// PatchType задает формат тела запроса PATCH
+type PatchType string
+
+const (
+	PatchTypeMerge PatchType = "application/merge-patch+json"
+	PatchTypeJSON  PatchType = "application/json-patch+json"
+)
+
+// TaskPatchDocument представляет изменяемые поля задачи, к которым применяется PATCH.
+// Поля-указатели позволяют отличить удаленное патчем поле от пустого значения.
+type TaskPatchDocument struct {
+	Title        *string                `json:"title"`
+	Description  *string                `json:"description"`
+	Status       *TaskStatus            `json:"status"`
+	Priority     *TaskPriority          `json:"priority"`
+	StartDate    *time.Time             `json:"start_date"`
+	EndDate      *time.Time             `json:"end_date"`
+	Tags         []string               `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
 }
 
 // TaskResponse представляет ответ с данными задачи
 type TaskResponse struct {
-	ID          uint       `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Status      TaskStatus `json:"status"`
-	StartDate   time.Time  `json:"start_date"`
-	EndDate     time.Time  `json:"end_date"`
-	UserID      uint       `json:"user_id"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          uint         `json:"id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Status      TaskStatus   `json:"status"`
+	Priority    TaskPriority `json:"priority"`
+	Position    string       `json:"position"`
+	StartDate   time.Time    `json:"start_date"`
+	EndDate     time.Time    `json:"end_date"`
+	UserID      uint         `json:"user_id"`
+	ProjectID   *uint        `json:"project_id"`
+	WorkflowID  *uint        `json:"workflow_id"`
+	ParentID    *uint        `json:"parent_id"`
+	Tags        []string     `json:"tags"`
+	CompletedAt *time.Time   `json:"completed_at"`
+	ArchivedAt  *time.Time   `json:"archived_at"`
+	Version     int          `json:"version"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	DeletedAt   *time.Time   `json:"deleted_at,omitempty"`
+
+	// IsOverdue задача не завершена, а день ее окончания в часовом поясе владельца прошел;
+	// OverdueBy — на сколько секунд она просрочена
+	IsOverdue bool  `json:"is_overdue"`
+	OverdueBy int64 `json:"overdue_by"`
+	// SLADueAt срок по действующей политике SLA, SLABreachedAt — момент ее нарушения
+	SLADueAt      *time.Time `json:"sla_due_at,omitempty"`
+	SLABreachedAt *time.Time `json:"sla_breached_at,omitempty"`
+
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+	TotalTime    int64                  `json:"total_time_seconds"`
+
+	// Число выполненных и всех пунктов чек-листа
+	ChecklistCompleted int `json:"checklist_completed"`
+	ChecklistTotal     int `json:"checklist_total"`
+}
+
+// MoveTaskRequest представляет запрос на перемещение задачи на доске.
+// Задача ставится сразу после AfterID и/или перед BeforeID в колонке Status;
+// если соседи не указаны, задача помещается в конец колонки.
+type MoveTaskRequest struct {
+	Status   TaskStatus `json:"status" binding:"required,max=50"`
+	AfterID  *uint      `json:"after_id,omitempty"`
+	BeforeID *uint      `json:"before_id,omitempty"`
+}
+
+// ArchiveCompletedRequest представляет запрос на архивирование задач,
+// завершенных больше OlderThanDays дней назад
+type ArchiveCompletedRequest struct {
+	OlderThanDays int   `json:"older_than_days" binding:"min=0,max=3650"`
+	ProjectID     *uint `json:"project_id,omitempty"`
+}
+
+// ArchiveResult представляет итог архивирования завершенных задач
+type ArchiveResult struct {
+	Archived int `json:"archived"`
+	// Skipped число задач, измененных во время архивирования; их поместит в архив следующий запуск
+	Skipped int `json:"skipped"`
+}
+
+// BoardColumn представляет колонку доски задач
+type BoardColumn struct {
+	Status   TaskStatus     `json:"status"`
+	Name     string         `json:"name"`
+	Category StatusCategory `json:"category"`
+	Tasks    []TaskResponse `json:"tasks"`
 }
 
 // TaskQueryParams представляет параметры запроса для получения задач
 type TaskQueryParams struct {
-	Status string `form:"status"`
-	Sort   string `form:"sort"`
-	Order  string `form:"order"`
-	Search string `form:"search"`
-	Page   int    `form:"page"`
-	Limit  int    `form:"limit"`
+	Status    string `form:"status"`
+	ProjectID *uint  `form:"project_id"`
+	Sort      string `form:"sort"`
+	Order     string `form:"order"`
+	Search    string `form:"search"`
+	Page      int    `form:"page"`
+	Limit     int    `form:"limit"`
+
+	// IncludeArchived добавляет в список задачи из архива, ArchivedOnly возвращает
+	// только их; по умолчанию задачи из архива не показываются
+	IncludeArchived bool `form:"include_archived"`
+	ArchivedOnly    bool `form:"archived_only"`
+	// Overdue оставляет только просроченные задачи
+	Overdue bool `form:"overdue"`
+
+	// OverdueBefore граница просрочки, подготовленная сервисом по часовому поясу пользователя:
+	// просрочены незавершенные задачи с end_date раньше нее
+	OverdueBefore *time.Time `form:"-"`
+
+	// Cursor курсор из next_cursor или prev_cursor предыдущего ответа; при нем page не используется
+	Cursor string `form:"cursor"`
+	// Count включает подсчет общего числа задач: по умолчанию включен при пагинации
+	// по страницам и выключен при пагинации по курсору
+	Count *bool `form:"count"`
+
+	// CustomFields фильтры по пользовательским полям из параметров cf[<ключ>]=<значение>
+	CustomFields map[string]string `form:"-"`
+
+	// Filter выражение фильтра, например status in (pending, in_progress) and end_date < now+7d
+	Filter string `form:"filter"`
+	// ViewFilter выражение фильтра сохраненного представления, объединяется с Filter условием И
+	ViewFilter string `form:"-"`
+
+	// Условия по пользовательским полям, подготовленные сервисом для репозитория
+	CustomFieldFilters []CustomFieldFilter `form:"-"`
+
+	// Условие SQL, скомпилированное сервисом из Filter, и его параметры
+	FilterCondition string        `form:"-"`
+	FilterArgs      []interface{} `form:"-"`
+
+	// Keyset-пагинация, подготовленная сервисом из Cursor: значения ключей сортировки
+	// граничной задачи и направление (CursorBackward — задачи перед граничной)
+	CursorValues   []interface{} `form:"-"`
+	CursorBackward bool          `form:"-"`
+	SkipCount      bool          `form:"-"`
+
+	// SortFields сортировка, разобранная сервисом из Sort (поля через запятую,
+	// направление задается как поле:asc или поле:desc) и Order (направление по умолчанию)
+	SortFields []TaskSortField `form:"-"`
+}
+
+// TaskPage представляет страницу списка задач
+type TaskPage struct {
+	Tasks []TaskResponse
+	// Total общее число задач; nil, если подсчет не запрашивался
+	Total *int64
+	// NextCursor и PrevCursor курсоры соседних страниц; пустые, если страницы нет
+	NextCursor string
+	PrevCursor string
+}
+
+// TaskSortField представляет одно поле сортировки задач
+type TaskSortField struct {
+	Field string
+	Desc  bool
+
+	// CustomField задается для сортировки по пользовательскому полю cf.<ключ>
+	CustomField *CustomFieldSort
 }
 
 // ToResponse конвертирует модель в ответ
 func (t *Task) ToResponse() TaskResponse {
-	return TaskResponse{
+	response := TaskResponse{
 		ID:          t.ID,
 		Title:       t.Title,
 		Description: t.Description,
 		Status:      t.Status,
+		Priority:    t.Priority,
+		Position:    t.Position,
 		StartDate:   t.StartDate,
 		EndDate:     t.EndDate,
 		UserID:      t.UserID,
+		ProjectID:   t.ProjectID,
+		WorkflowID:  t.WorkflowID,
+		ParentID:    t.ParentID,
+		Tags:        t.tags(),
+		CompletedAt: t.CompletedAt,
+		ArchivedAt:  t.ArchivedAt,
+		Version:     t.Version,
 		CreatedAt:   t.CreatedAt,
 		UpdatedAt:   t.UpdatedAt,
+
+		SLABreachedAt: t.SLABreachedAt,
+
+		CustomFields: customFieldsResponse(t.CustomFieldValues),
+	}
+	if t.DeletedAt.Valid {
+		deletedAt := t.DeletedAt.Time
+		response.DeletedAt = &deletedAt
+	}
+	return response
+}
+
+// PatchDocument возвращает документ задачи, к которому применяется PATCH
+func (t *Task) PatchDocument() TaskPatchDocument {
+	customFields := customFieldsResponse(t.CustomFieldValues)
+	if customFields == nil {
+		customFields = map[string]interface{}{}
+	}
+	return TaskPatchDocument{
+		Title:        &t.Title,
+		Description:  &t.Description,
+		Status:       &t.Status,
+		Priority:     &t.Priority,
+		StartDate:    &t.StartDate,
+		EndDate:      &t.EndDate,
+		Tags:         t.tags(),
+		CustomFields: customFields,
+	}
+}
+
+// tags возвращает метки задачи; у задачи без меток — пустой список
+func (t *Task) tags() []string {
+	if t.Tags == nil {
+		return []string{}
 	}
+	return t.Tags
 }
 
-// IsValidStatus проверяет валидность статуса
-func (t TaskStatus) IsValid() bool {
-	switch t {
-	case TaskStatusPending, TaskStatusInProgress, TaskStatusCompleted:
+// IsValid проверяет валидность приоритета
+func (p TaskPriority) IsValid() bool {
+	switch p {
+	case TaskPriorityLow, TaskPriorityMedium, TaskPriorityHigh, TaskPriorityCritical:
 		return true
 	}
 	return false
-} 
\ No newline at end of file
+}
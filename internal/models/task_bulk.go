@@ -0,0 +1,66 @@
+package models
+
+// BulkAction представляет операцию, применяемую к группе задач
+type BulkAction string
+
+const (
+	BulkActionUpdate BulkAction = "update"
+	BulkActionDelete BulkAction = "delete"
+	BulkActionMove   BulkAction = "move"
+)
+
+// BulkMode определяет поведение при ошибке в одной из задач
+type BulkMode string
+
+const (
+	// BulkModeAtomic применяет операцию ко всем задачам в одной транзакции: все или ничего
+	BulkModeAtomic BulkMode = "atomic"
+	// BulkModeBestEffort применяет операцию к каждой задаче отдельно и возвращает результат по каждой
+	BulkModeBestEffort BulkMode = "best_effort"
+)
+
+// BulkTaskFilter выбирает задачи для массовой операции по тем же условиям, что и GET /api/tasks
+type BulkTaskFilter struct {
+	Status       string            `json:"status"`
+	ProjectID    *uint             `json:"project_id"`
+	Search       string            `json:"search"`
+	CustomFields map[string]string `json:"cf"`
+	// Query выражение фильтра, как в параметре filter
+	Query string `json:"query"`
+	// Overdue оставляет только просроченные задачи
+	Overdue bool `json:"overdue"`
+}
+
+// BulkMoveRequest представляет перемещение задач в конец колонки
+type BulkMoveRequest struct {
+	Status TaskStatus `json:"status" binding:"required,max=50"`
+}
+
+// BulkTaskRequest представляет запрос на массовую операцию с задачами.
+// Задачи задаются списком IDs или фильтром Filter.
+type BulkTaskRequest struct {
+	Action BulkAction         `json:"action" binding:"required,oneof=update delete move"`
+	Mode   BulkMode           `json:"mode" binding:"omitempty,oneof=atomic best_effort"`
+	IDs    []uint             `json:"ids"`
+	Filter *BulkTaskFilter    `json:"filter"`
+	Update *UpdateTaskRequest `json:"update"`
+	Move   *BulkMoveRequest   `json:"move"`
+}
+
+// BulkTaskItemResult представляет результат операции над одной задачей
+type BulkTaskItemResult struct {
+	ID      uint          `json:"id"`
+	Success bool          `json:"success"`
+	Error   string        `json:"error,omitempty"`
+	Task    *TaskResponse `json:"task,omitempty"`
+}
+
+// BulkTaskResult представляет результат массовой операции
+type BulkTaskResult struct {
+	Action    BulkAction           `json:"action"`
+	Mode      BulkMode             `json:"mode"`
+	Total     int                  `json:"total"`
+	Succeeded int                  `json:"succeeded"`
+	Failed    int                  `json:"failed"`
+	Results   []BulkTaskItemResult `json:"results"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// TaskDependency представляет зависимость «окончание — начало»: задача TaskID
+// может начаться только после окончания задачи DependsOnID
+type TaskDependency struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"-" gorm:"not null;index"`
+	TaskID      uint      `json:"task_id" gorm:"not null;uniqueIndex:idx_task_dependency"`
+	DependsOnID uint      `json:"depends_on_id" gorm:"not null;uniqueIndex:idx_task_dependency;index"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CreateTaskDependencyRequest представляет запрос на добавление зависимости задачи
+type CreateTaskDependencyRequest struct {
+	DependsOnID uint `json:"depends_on_id" binding:"required"`
+}
+
+// TaskDependenciesResponse представляет зависимости задачи: задачи, от которых она
+// зависит, и задачи, которые зависят от нее
+type TaskDependenciesResponse struct {
+	DependsOn  []TaskDependency `json:"depends_on"`
+	Dependents []TaskDependency `json:"dependents"`
+}
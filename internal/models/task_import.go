@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+)
+
+// ImportFormat представляет формат файла выгрузки и загрузки задач
+type ImportFormat string
+
+const (
+	ImportFormatCSV    ImportFormat = "csv"
+	ImportFormatJSON   ImportFormat = "json"
+	ImportFormatNDJSON ImportFormat = "ndjson"
+)
+
+// TaskImportParams представляет параметры загрузки задач из полей формы или строки запроса
+type TaskImportParams struct {
+	Format ImportFormat `form:"format" binding:"omitempty,oneof=csv json ndjson"`
+	// Mapping JSON-объект соответствия колонок файла полям задачи, например {"Срок": "end_date"}
+	Mapping   string `form:"mapping"`
+	DryRun    bool   `form:"dry_run"`
+	ProjectID *uint  `form:"project_id"`
+}
+
+// TaskImportRequest представляет разобранный запрос на загрузку задач
+type TaskImportRequest struct {
+	Format  ImportFormat
+	Mapping map[string]string
+	// DryRun только проверяет записи, не создавая задачи
+	DryRun bool
+	// ProjectID проект задач, для которых он не указан в файле
+	ProjectID *uint
+}
+
+// ImportRecord представляет запись файла после применения соответствия колонок:
+// значения по именам полей задачи (title, end_date, cf.<ключ> и т.д.)
+type ImportRecord struct {
+	// Row номер записи в файле, начиная с 1 (строка заголовка CSV не считается)
+	Row    int
+	Values map[string]interface{}
+	// Error ошибка разбора записи, например неверное число колонок CSV
+	Error string
+}
+
+// TaskImportItem представляет проверенную запись: запрос на создание задачи и ее статус
+type TaskImportItem struct {
+	Row     int
+	Request CreateTaskRequest
+	Status  TaskStatus
+}
+
+// ImportRowError представляет ошибку в записи файла
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportReport представляет результат проверки и загрузки задач. Если хотя бы одна
+// запись содержит ошибку, задачи не создаются.
+type ImportReport struct {
+	DryRun  bool `json:"dry_run"`
+	Total   int  `json:"total"`
+	Valid   int  `json:"valid"`
+	Invalid int  `json:"invalid"`
+	Created int  `json:"created"`
+	// Errors первые ошибки по записям; ErrorsTotal — число всех ошибок
+	Errors      []ImportRowError `json:"errors"`
+	ErrorsTotal int              `json:"errors_total"`
+	// IgnoredColumns колонки файла, не соответствующие полям задачи
+	IgnoredColumns []string `json:"ignored_columns"`
+}
+
+// ImportJobStatus представляет состояние фоновой загрузки задач
+type ImportJobStatus string
+
+const (
+	ImportJobPending    ImportJobStatus = "pending"
+	ImportJobValidating ImportJobStatus = "validating"
+	ImportJobImporting  ImportJobStatus = "importing"
+	ImportJobCompleted  ImportJobStatus = "completed"
+	ImportJobFailed     ImportJobStatus = "failed"
+)
+
+// IsFinished проверяет, что загрузка завершилась
+func (s ImportJobStatus) IsFinished() bool {
+	return s == ImportJobCompleted || s == ImportJobFailed
+}
+
+// ImportJob представляет фоновую загрузку большого файла задач.
+// Processed — число записей, обработанных на текущем этапе (проверка или создание).
+type ImportJob struct {
+	ID         uint            `json:"id" gorm:"primaryKey"`
+	UserID     uint            `json:"user_id" gorm:"not null;index"`
+	Format     ImportFormat    `json:"format"`
+	DryRun     bool            `json:"dry_run"`
+	Status     ImportJobStatus `json:"status" gorm:"not null;index"`
+	Total      int             `json:"total"`
+	Processed  int             `json:"processed"`
+	Report     *ImportReport   `json:"report,omitempty" gorm:"serializer:json"`
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	FinishedAt *time.Time      `json:"finished_at"`
+}
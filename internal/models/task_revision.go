@@ -0,0 +1,195 @@
+package models
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// TaskAction представляет тип изменения задачи в истории
+type TaskAction string
+
+const (
+	TaskActionCreated    TaskAction = "created"
+	TaskActionUpdated    TaskAction = "updated"
+	TaskActionMoved      TaskAction = "moved"
+	TaskActionDeleted    TaskAction = "deleted"
+	TaskActionRestored   TaskAction = "restored"
+	TaskActionReverted   TaskAction = "reverted"
+	TaskActionArchived   TaskAction = "archived"
+	TaskActionUnarchived TaskAction = "unarchived"
+	// TaskActionSLABreached отмечается фоновой проверкой SLA, а не пользователем
+	TaskActionSLABreached TaskAction = "sla_breached"
+)
+
+// TaskRevision представляет запись истории изменений задачи.
+// Snapshot хранит состояние задачи после изменения, Changes — отличия от предыдущего состояния.
+type TaskRevision struct {
+	ID           uint              `json:"id" gorm:"primaryKey"`
+	TaskID       uint              `json:"task_id" gorm:"not null;index"`
+	UserID       uint              `json:"user_id" gorm:"not null"`
+	Action       TaskAction        `json:"action" gorm:"not null"`
+	Changes      []TaskFieldChange `json:"changes" gorm:"serializer:json"`
+	Snapshot     TaskSnapshot      `json:"snapshot" gorm:"serializer:json"`
+	RevertedFrom *uint             `json:"reverted_from,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+
+	// Связи
+	User User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// TaskFieldChange представляет изменение одного поля задачи
+type TaskFieldChange struct {
+	Field    string      `json:"field"`
+	OldValue interface{} `json:"old"`
+	NewValue interface{} `json:"new"`
+}
+
+// TaskSnapshot представляет состояние изменяемых полей задачи
+type TaskSnapshot struct {
+	Title         string                 `json:"title"`
+	Description   string                 `json:"description"`
+	Status        TaskStatus             `json:"status"`
+	Priority      TaskPriority           `json:"priority"`
+	StartDate     time.Time              `json:"start_date"`
+	EndDate       time.Time              `json:"end_date"`
+	Tags          []string               `json:"tags"`
+	DeletedAt     *time.Time             `json:"deleted_at,omitempty"`
+	ArchivedAt    *time.Time             `json:"archived_at,omitempty"`
+	SLABreachedAt *time.Time             `json:"sla_breached_at,omitempty"`
+	CustomFields  map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+// TaskHistoryParams представляет параметры запроса истории задачи
+type TaskHistoryParams struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+// TaskRevisionResponse представляет ответ с записью истории задачи
+type TaskRevisionResponse struct {
+	ID           uint              `json:"id"`
+	TaskID       uint              `json:"task_id"`
+	Action       TaskAction        `json:"action"`
+	Actor        UserResponse      `json:"actor"`
+	Changes      []TaskFieldChange `json:"changes"`
+	Snapshot     TaskSnapshot      `json:"snapshot"`
+	RevertedFrom *uint             `json:"reverted_from,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// Snapshot возвращает текущее состояние изменяемых полей задачи
+func (t *Task) Snapshot() TaskSnapshot {
+	snapshot := TaskSnapshot{
+		Title:         t.Title,
+		Description:   t.Description,
+		Status:        t.Status,
+		Priority:      t.Priority,
+		StartDate:     t.StartDate,
+		EndDate:       t.EndDate,
+		Tags:          t.tags(),
+		ArchivedAt:    t.ArchivedAt,
+		SLABreachedAt: t.SLABreachedAt,
+		CustomFields:  customFieldsResponse(t.CustomFieldValues),
+	}
+	if t.DeletedAt.Valid {
+		deletedAt := t.DeletedAt.Time
+		snapshot.DeletedAt = &deletedAt
+	}
+	return snapshot
+}
+
+// Diff возвращает изменения полей относительно предыдущего состояния.
+// При previous == nil (создание задачи) все поля считаются новыми.
+func (s TaskSnapshot) Diff(previous *TaskSnapshot) []TaskFieldChange {
+	var old TaskSnapshot
+	if previous != nil {
+		old = *previous
+	}
+
+	changes := []TaskFieldChange{}
+	add := func(field string, oldValue, newValue interface{}) {
+		if previous != nil && jsonEqual(oldValue, newValue) {
+			return
+		}
+		if previous == nil {
+			oldValue = nil
+		}
+		changes = append(changes, TaskFieldChange{Field: field, OldValue: oldValue, NewValue: newValue})
+	}
+
+	add("title", old.Title, s.Title)
+	add("description", old.Description, s.Description)
+	add("status", old.Status, s.Status)
+	add("priority", old.Priority, s.Priority)
+	add("start_date", old.StartDate, s.StartDate)
+	add("end_date", old.EndDate, s.EndDate)
+	// Снимки, записанные до появления меток, не содержат их
+	oldTags := old.Tags
+	if oldTags == nil {
+		oldTags = []string{}
+	}
+	add("tags", oldTags, s.Tags)
+	if previous != nil {
+		add("deleted_at", old.DeletedAt, s.DeletedAt)
+		add("archived_at", old.ArchivedAt, s.ArchivedAt)
+		add("sla_breached_at", old.SLABreachedAt, s.SLABreachedAt)
+	}
+
+	// Пользовательские поля сравниваются по ключам в стабильном порядке
+	keys := make([]string, 0, len(old.CustomFields)+len(s.CustomFields))
+	seen := make(map[string]bool)
+	for _, fields := range []map[string]interface{}{old.CustomFields, s.CustomFields} {
+		for key := range fields {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		oldValue, newValue := old.CustomFields[key], s.CustomFields[key]
+		if previous == nil && newValue == nil {
+			continue
+		}
+		add("custom_fields."+key, oldValue, newValue)
+	}
+
+	return changes
+}
+
+// IsSystem сообщает, что изменение выполнено сервером, а не пользователем:
+// о таких изменениях владелец задачи уведомляется и без настройки own_changes
+func (a TaskAction) IsSystem() bool {
+	return a == TaskActionSLABreached
+}
+
+// ToResponse преобразует TaskRevision в TaskRevisionResponse
+func (r *TaskRevision) ToResponse() TaskRevisionResponse {
+	return TaskRevisionResponse{
+		ID:     r.ID,
+		TaskID: r.TaskID,
+		Action: r.Action,
+		Actor: UserResponse{
+			ID:       r.User.ID,
+			Username: r.User.Username,
+			Email:    r.User.Email,
+		},
+		Changes:      r.Changes,
+		Snapshot:     r.Snapshot,
+		RevertedFrom: r.RevertedFrom,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+// jsonEqual сравнивает значения по их JSON-представлению: так значения из
+// базы и значения, прочитанные из сохраненного снимка, сравниваются одинаково
+func jsonEqual(a, b interface{}) bool {
+	left, errLeft := json.Marshal(a)
+	right, errRight := json.Marshal(b)
+	if errLeft != nil || errRight != nil {
+		return false
+	}
+	return string(left) == string(right)
+}
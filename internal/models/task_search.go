@@ -0,0 +1,36 @@
+package models
+
+// Маркеры, которыми выделяются найденные слова в подсветке результатов поиска
+const (
+	SearchHighlightStart = "<mark>"
+	SearchHighlightEnd   = "</mark>"
+)
+
+// TaskSearchParams представляет параметры полнотекстового поиска задач
+type TaskSearchParams struct {
+	Query     string `form:"q" binding:"required,max=500"`
+	ProjectID *uint  `form:"project_id"`
+	Page      int    `form:"page"`
+	Limit     int    `form:"limit"`
+}
+
+// TaskSearchHit представляет найденную задачу с оценкой релевантности и подсветкой
+type TaskSearchHit struct {
+	TaskID      uint
+	Score       float64
+	Title       string
+	Description string
+}
+
+// TaskSearchHighlights представляет фрагменты задачи с выделенными совпадениями
+type TaskSearchHighlights struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// TaskSearchResult представляет результат полнотекстового поиска
+type TaskSearchResult struct {
+	Task       TaskResponse         `json:"task"`
+	Score      float64              `json:"score"`
+	Highlights TaskSearchHighlights `json:"highlights"`
+}
@@ -0,0 +1,127 @@
+package models
+
+import "time"
+
+// TaskTemplate представляет шаблон задачи с подзадачами, по которому
+// задачи создаются повторно. Даты задач задаются смещением в днях от даты
+// привязки, указанной при создании задач, а в названиях, описаниях, метках
+// и пунктах чек-листа можно использовать переменные вида {{имя}}.
+type TaskTemplate struct {
+	ID              uint                  `json:"id" gorm:"primaryKey"`
+	UserID          uint                  `json:"user_id" gorm:"not null;index"`
+	ProjectID       *uint                 `json:"project_id" gorm:"index"`
+	Name            string                `json:"name" gorm:"not null"`
+	Title           string                `json:"title" gorm:"not null"`
+	Description     string                `json:"description"`
+	Priority        TaskPriority          `json:"priority"`
+	StartOffsetDays int                   `json:"start_offset_days"`
+	EndOffsetDays   int                   `json:"end_offset_days"`
+	Tags            []string              `json:"tags" gorm:"serializer:json"`
+	Checklist       []string              `json:"checklist" gorm:"serializer:json"`
+	Subtasks        []TaskTemplateSubtask `json:"subtasks" gorm:"serializer:json"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+}
+
+// TaskTemplateSubtask представляет подзадачу шаблона
+type TaskTemplateSubtask struct {
+	Title           string       `json:"title" binding:"required,min=1,max=255"`
+	Description     string       `json:"description,omitempty"`
+	Priority        TaskPriority `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"`
+	StartOffsetDays int          `json:"start_offset_days"`
+	EndOffsetDays   int          `json:"end_offset_days"`
+	Tags            []string     `json:"tags,omitempty"`
+	Checklist       []string     `json:"checklist,omitempty"`
+}
+
+// CreateTaskTemplateRequest представляет запрос на создание шаблона
+type CreateTaskTemplateRequest struct {
+	Name            string                `json:"name" binding:"required,min=1,max=100"`
+	ProjectID       *uint                 `json:"project_id,omitempty"`
+	Title           string                `json:"title" binding:"required,min=1,max=255"`
+	Description     string                `json:"description"`
+	Priority        TaskPriority          `json:"priority" binding:"omitempty,oneof=low medium high critical"`
+	StartOffsetDays int                   `json:"start_offset_days"`
+	EndOffsetDays   int                   `json:"end_offset_days"`
+	Tags            []string              `json:"tags"`
+	Checklist       []string              `json:"checklist"`
+	Subtasks        []TaskTemplateSubtask `json:"subtasks" binding:"omitempty,dive"`
+}
+
+// UpdateTaskTemplateRequest представляет запрос на обновление шаблона.
+// Поле project_id не меняется: шаблон создается заново для другого проекта.
+type UpdateTaskTemplateRequest struct {
+	Name            *string                `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Title           *string                `json:"title,omitempty" binding:"omitempty,min=1,max=255"`
+	Description     *string                `json:"description,omitempty"`
+	Priority        *TaskPriority          `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"`
+	StartOffsetDays *int                   `json:"start_offset_days,omitempty"`
+	EndOffsetDays   *int                   `json:"end_offset_days,omitempty"`
+	Tags            *[]string              `json:"tags,omitempty"`
+	Checklist       *[]string              `json:"checklist,omitempty"`
+	Subtasks        *[]TaskTemplateSubtask `json:"subtasks,omitempty" binding:"omitempty,dive"`
+}
+
+// InstantiateTaskTemplateRequest представляет запрос на создание задач по шаблону.
+// AnchorDate задается в формате YYYY-MM-DD или RFC 3339; ProjectID заменяет проект шаблона.
+type InstantiateTaskTemplateRequest struct {
+	AnchorDate string            `json:"anchor_date" binding:"required"`
+	Variables  map[string]string `json:"variables"`
+	ProjectID  *uint             `json:"project_id,omitempty"`
+}
+
+// TaskTemplateResponse представляет ответ с данными шаблона
+type TaskTemplateResponse struct {
+	ID              uint                  `json:"id"`
+	UserID          uint                  `json:"user_id"`
+	ProjectID       *uint                 `json:"project_id"`
+	Name            string                `json:"name"`
+	Title           string                `json:"title"`
+	Description     string                `json:"description"`
+	Priority        TaskPriority          `json:"priority"`
+	StartOffsetDays int                   `json:"start_offset_days"`
+	EndOffsetDays   int                   `json:"end_offset_days"`
+	Tags            []string              `json:"tags"`
+	Checklist       []string              `json:"checklist"`
+	Subtasks        []TaskTemplateSubtask `json:"subtasks"`
+	// Variables переменные, которые нужно передать при создании задач по шаблону
+	Variables []string  `json:"variables"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaskTemplateInstance представляет задачи, созданные по шаблону
+type TaskTemplateInstance struct {
+	Task     TaskResponse   `json:"task"`
+	Subtasks []TaskResponse `json:"subtasks"`
+}
+
+// ToResponse конвертирует модель в ответ
+func (t *TaskTemplate) ToResponse() TaskTemplateResponse {
+	response := TaskTemplateResponse{
+		ID:              t.ID,
+		UserID:          t.UserID,
+		ProjectID:       t.ProjectID,
+		Name:            t.Name,
+		Title:           t.Title,
+		Description:     t.Description,
+		Priority:        t.Priority,
+		StartOffsetDays: t.StartOffsetDays,
+		EndOffsetDays:   t.EndOffsetDays,
+		Tags:            t.Tags,
+		Checklist:       t.Checklist,
+		Subtasks:        t.Subtasks,
+		CreatedAt:       t.CreatedAt,
+		UpdatedAt:       t.UpdatedAt,
+	}
+	if response.Tags == nil {
+		response.Tags = []string{}
+	}
+	if response.Checklist == nil {
+		response.Checklist = []string{}
+	}
+	if response.Subtasks == nil {
+		response.Subtasks = []TaskTemplateSubtask{}
+	}
+	return response
+}
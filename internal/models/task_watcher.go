@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+// WatchReason представляет причину, по которой пользователь следит за задачей
+type WatchReason string
+
+const (
+	WatchReasonOwner     WatchReason = "owner"
+	WatchReasonAssignee  WatchReason = "assignee"
+	WatchReasonMentioned WatchReason = "mentioned"
+	WatchReasonManual    WatchReason = "manual"
+)
+
+// TaskWatcher представляет подписку пользователя на изменения задачи.
+// Отписка сохраняет запись с Watching = false, чтобы пользователь, отказавшийся
+// от подписки, не подписывался повторно автоматически.
+type TaskWatcher struct {
+	ID        uint        `json:"id" gorm:"primaryKey"`
+	TaskID    uint        `json:"task_id" gorm:"not null;uniqueIndex:idx_task_watcher"`
+	UserID    uint        `json:"user_id" gorm:"not null;uniqueIndex:idx_task_watcher;index"`
+	Reason    WatchReason `json:"reason" gorm:"not null"`
+	Watching  bool        `json:"watching" gorm:"not null"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+
+	// Связи
+	User User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// TaskWatcherResponse представляет ответ с подписчиком задачи
+type TaskWatcherResponse struct {
+	User      UserResponse `json:"user"`
+	Reason    WatchReason  `json:"reason"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// ToResponse конвертирует модель в ответ
+func (w *TaskWatcher) ToResponse() TaskWatcherResponse {
+	return TaskWatcherResponse{
+		User: UserResponse{
+			ID:       w.User.ID,
+			Username: w.User.Username,
+			Email:    w.User.Email,
+		},
+		Reason:    w.Reason,
+		CreatedAt: w.CreatedAt,
+	}
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+)
+
+// TimeEntry представляет запись учета времени по задаче.
+// Запись без EndedAt — запущенный таймер; у пользователя он может быть только один.
+type TimeEntry struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	TaskID    uint       `json:"task_id" gorm:"not null;index"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	StartedAt time.Time  `json:"started_at" gorm:"not null;index"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Duration  int64      `json:"duration_seconds" gorm:"column:duration_seconds;not null;default:0"`
+	Note      string     `json:"note"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// StartTimerRequest представляет запрос на запуск таймера
+type StartTimerRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+// CreateTimeEntryRequest представляет запрос на ручное добавление времени
+type CreateTimeEntryRequest struct {
+	StartedAt time.Time `json:"started_at" binding:"required"`
+	EndedAt   time.Time `json:"ended_at" binding:"required"`
+	Note      string    `json:"note" binding:"max=500"`
+}
+
+// UpdateTimeEntryRequest представляет запрос на изменение записи времени
+type UpdateTimeEntryRequest struct {
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Note      *string    `json:"note,omitempty" binding:"omitempty,max=500"`
+}
+
+// TimeEntryResponse представляет ответ с записью времени
+type TimeEntryResponse struct {
+	ID        uint       `json:"id"`
+	TaskID    uint       `json:"task_id"`
+	UserID    uint       `json:"user_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Duration  int64      `json:"duration_seconds"`
+	Running   bool       `json:"running"`
+	Note      string     `json:"note"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TimeReportParams представляет параметры отчета по времени
+type TimeReportParams struct {
+	From      *time.Time `form:"from" time_format:"2006-01-02"`
+	To        *time.Time `form:"to" time_format:"2006-01-02"`
+	GroupBy   string     `form:"group_by"`
+	ProjectID *uint      `form:"project_id"`
+	TaskID    *uint      `form:"task_id"`
+	Format    string     `form:"format"`
+}
+
+// TimeReportRow представляет строку отчета по времени.
+// Поля группировки, не участвующие в отчете, остаются пустыми.
+type TimeReportRow struct {
+	TaskID    *uint  `json:"task_id,omitempty"`
+	TaskTitle string `json:"task_title,omitempty"`
+	ProjectID *uint  `json:"project_id,omitempty"`
+	UserID    *uint  `json:"user_id,omitempty"`
+	Entries   int64  `json:"entries"`
+	Duration  int64  `json:"duration_seconds"`
+}
+
+// ToResponse конвертирует модель в ответ; у запущенного таймера длительность считается до now
+func (e *TimeEntry) ToResponse(now time.Time) TimeEntryResponse {
+	duration := e.Duration
+	if e.EndedAt == nil {
+		duration = int64(now.Sub(e.StartedAt).Seconds())
+	}
+
+	return TimeEntryResponse{
+		ID:        e.ID,
+		TaskID:    e.TaskID,
+		UserID:    e.UserID,
+		StartedAt: e.StartedAt,
+		EndedAt:   e.EndedAt,
+		Duration:  duration,
+		Running:   e.EndedAt == nil,
+		Note:      e.Note,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
@@ -0,0 +1,106 @@
+package models
+
+import "time"
+
+// TimelineParams представляет параметры временной шкалы задач
+type TimelineParams struct {
+	From      *time.Time `form:"from" time_format:"2006-01-02"`
+	To        *time.Time `form:"to" time_format:"2006-01-02"`
+	ProjectID *uint      `form:"project_id"`
+}
+
+// TimelineFilter представляет условия выбора задач шкалы для репозитория:
+// задачи, пересекающие период с From по To включительно (даты в UTC)
+type TimelineFilter struct {
+	From      time.Time
+	To        time.Time
+	ProjectID *uint
+}
+
+// ConflictType представляет вид конфликта в расписании
+type ConflictType string
+
+const (
+	// ConflictTypeOverlap у исполнителя пересекаются незавершенные задачи
+	ConflictTypeOverlap ConflictType = "overlap"
+	// ConflictTypeDependency задача начинается раньше, чем заканчивается задача, от которой она зависит
+	ConflictTypeDependency ConflictType = "dependency"
+	// ConflictTypeNonWorkingDay задача начинается или заканчивается в нерабочий день
+	ConflictTypeNonWorkingDay ConflictType = "non_working_day"
+)
+
+// TimelineTask представляет задачу на временной шкале
+type TimelineTask struct {
+	ID        uint           `json:"id"`
+	Title     string         `json:"title"`
+	Status    TaskStatus     `json:"status"`
+	Category  StatusCategory `json:"category"`
+	Priority  TaskPriority   `json:"priority"`
+	StartDate time.Time      `json:"start_date"`
+	EndDate   time.Time      `json:"end_date"`
+	ProjectID *uint          `json:"project_id"`
+	ParentID  *uint          `json:"parent_id"`
+	Version   int            `json:"version"`
+	// Assignees назначенные пользователи (значения полей типа user), а если их нет — владелец
+	Assignees []uint `json:"assignees"`
+	// WorkingDays число рабочих дней с даты начала по дату окончания
+	WorkingDays int `json:"working_days"`
+	// DependsOn задачи, от которых зависит задача, в том числе вне периода
+	DependsOn []uint `json:"depends_on"`
+}
+
+// ScheduleConflict представляет конфликт в расписании. Для пересечения задач
+// UserID — исполнитель, а From и To — общий интервал задач; для зависимости
+// TaskIDs содержит сначала предшествующую задачу, затем зависимую.
+type ScheduleConflict struct {
+	Type    ConflictType `json:"type"`
+	TaskIDs []uint       `json:"task_ids"`
+	UserID  *uint        `json:"user_id,omitempty"`
+	From    *time.Time   `json:"from,omitempty"`
+	To      *time.Time   `json:"to,omitempty"`
+	// Date нерабочий день в формате YYYY-MM-DD
+	Date string `json:"date,omitempty"`
+}
+
+// TimelineResponse представляет временную шкалу задач за период
+type TimelineResponse struct {
+	From         string             `json:"from"`
+	To           string             `json:"to"`
+	ProjectID    *uint              `json:"project_id,omitempty"`
+	Tasks        []TimelineTask     `json:"tasks"`
+	Dependencies []TaskDependency   `json:"dependencies"`
+	Conflicts    []ScheduleConflict `json:"conflicts"`
+}
+
+// ShiftTaskRequest представляет запрос на перенос задачи. Задается либо сдвиг Days,
+// либо новая дата начала StartDate; длительность задачи сохраняется. С WorkingDays
+// (по умолчанию) сдвиг и длительность считаются в рабочих днях.
+type ShiftTaskRequest struct {
+	Days        *int       `json:"days,omitempty"`
+	StartDate   *time.Time `json:"start_date,omitempty"`
+	WorkingDays *bool      `json:"working_days,omitempty"`
+	// DryRun рассчитывает изменения без их сохранения
+	DryRun bool `json:"dry_run"`
+
+	// ExpectedVersion версия задачи из заголовка If-Match; nil отключает проверку
+	ExpectedVersion *int `json:"-"`
+}
+
+// ScheduleChange представляет перенос одной задачи
+type ScheduleChange struct {
+	TaskID       uint      `json:"task_id"`
+	Title        string    `json:"title"`
+	OldStartDate time.Time `json:"old_start_date"`
+	OldEndDate   time.Time `json:"old_end_date"`
+	StartDate    time.Time `json:"start_date"`
+	EndDate      time.Time `json:"end_date"`
+	// Version версия задачи после переноса; при пробном расчете — текущая версия
+	Version int `json:"version"`
+}
+
+// ShiftTaskResult представляет результат переноса: сама задача идет первой,
+// за ней зависимые задачи, перенесенные вперед
+type ShiftTaskResult struct {
+	DryRun  bool             `json:"dry_run"`
+	Changes []ScheduleChange `json:"changes"`
+}
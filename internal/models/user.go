@@ -2,6 +2,9 @@ package models
 
 import (
 	"time"
+	// Встроенная база часовых поясов: часовой пояс пользователя определяется
+	// и там, где в системе нет zoneinfo
+	_ "time/tzdata"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -13,6 +16,8 @@ type User struct {
 	Username  string    `json:"username" gorm:"unique;not null"`
 	Email     string    `json:"email" gorm:"unique;not null"`
 	Password  string    `json:"-" gorm:"not null"`
+	// Timezone часовой пояс пользователя (IANA, например Europe/Moscow); пустой — UTC
+	Timezone  string    `json:"timezone" gorm:"not null;default:''"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	
@@ -40,6 +45,19 @@ type UserResponse struct {
 	Email    string `json:"email"`
 }
 
+// UserProfileResponse представляет профиль текущего пользователя
+type UserProfileResponse struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Timezone string `json:"timezone"`
+}
+
+// UpdateProfileRequest представляет запрос на изменение профиля
+type UpdateProfileRequest struct {
+	Timezone *string `json:"timezone" binding:"omitempty,max=64"`
+}
+
 // BeforeCreate хук для хеширования пароля перед созданием
 func (u *User) BeforeCreate(tx *gorm.DB) error {
 	hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
@@ -56,6 +74,28 @@ func (u *User) CheckPassword(password string) bool {
 	return err == nil
 }
 
+// Location возвращает часовой пояс пользователя; неизвестный или пустой пояс считается UTC
+func (u *User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// ToProfileResponse конвертирует модель в профиль пользователя
+func (u *User) ToProfileResponse() UserProfileResponse {
+	return UserProfileResponse{
+		ID:       u.ID,
+		Username: u.Username,
+		Email:    u.Email,
+		Timezone: u.Timezone,
+	}
+}
+
 // ToResponse конвертирует модель в ответ
 func (u *User) ToResponse() UserResponse {
 	return UserResponse{
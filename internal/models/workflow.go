@@ -0,0 +1,182 @@
+package models
+
+import (
+	"time"
+)
+
+// StatusCategory представляет категорию статуса рабочего процесса
+type StatusCategory string
+
+const (
+	StatusCategoryTodo  StatusCategory = "todo"
+	StatusCategoryDoing StatusCategory = "doing"
+	StatusCategoryDone  StatusCategory = "done"
+)
+
+// Workflow представляет пользовательский рабочий процесс: набор статусов и переходов
+type Workflow struct {
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	Name      string `json:"name" gorm:"not null"`
+	UserID    uint   `json:"user_id" gorm:"not null;index"`
+	IsDefault bool   `json:"is_default" gorm:"default:false"`
+	// RequireChecklist запрещает переводить задачу в статус категории done,
+	// пока в ее чек-листе есть невыполненные обязательные пункты
+	RequireChecklist bool      `json:"require_checklist" gorm:"not null;default:false"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+
+	// Связи
+	Statuses []WorkflowStatus `json:"statuses" gorm:"foreignKey:WorkflowID;constraint:OnDelete:CASCADE"`
+	// Transitions разрешенные переходы; пустой список разрешает переход между любыми статусами
+	Transitions []WorkflowTransition `json:"transitions" gorm:"foreignKey:WorkflowID;constraint:OnDelete:CASCADE"`
+}
+
+// WorkflowStatus представляет статус внутри рабочего процесса
+type WorkflowStatus struct {
+	ID         uint           `json:"-" gorm:"primaryKey"`
+	WorkflowID uint           `json:"-" gorm:"not null;uniqueIndex:idx_workflow_status_key"`
+	Key        TaskStatus     `json:"key" gorm:"not null;uniqueIndex:idx_workflow_status_key"`
+	Name       string         `json:"name" gorm:"not null"`
+	Category   StatusCategory `json:"category" gorm:"not null"`
+	Position   int            `json:"position"`
+}
+
+// WorkflowTransition представляет разрешенный переход между статусами
+type WorkflowTransition struct {
+	ID         uint       `json:"-" gorm:"primaryKey"`
+	WorkflowID uint       `json:"-" gorm:"not null;index"`
+	FromStatus TaskStatus `json:"from" gorm:"not null"`
+	ToStatus   TaskStatus `json:"to" gorm:"not null"`
+}
+
+// WorkflowStatusRequest представляет статус в запросе на создание или изменение процесса
+type WorkflowStatusRequest struct {
+	Key      TaskStatus     `json:"key" binding:"required,max=50"`
+	Name     string         `json:"name" binding:"required,min=1,max=100"`
+	Category StatusCategory `json:"category" binding:"required,oneof=todo doing done"`
+}
+
+// WorkflowTransitionRequest представляет переход в запросе на создание или изменение процесса
+type WorkflowTransitionRequest struct {
+	From TaskStatus `json:"from" binding:"required"`
+	To   TaskStatus `json:"to" binding:"required"`
+}
+
+// CreateWorkflowRequest представляет запрос на создание рабочего процесса.
+// Если переходы не указаны, разрешены любые переходы между статусами.
+type CreateWorkflowRequest struct {
+	Name        string                      `json:"name" binding:"required,min=1,max=100"`
+	Statuses    []WorkflowStatusRequest     `json:"statuses" binding:"required,min=1,dive"`
+	Transitions []WorkflowTransitionRequest `json:"transitions" binding:"omitempty,dive"`
+	IsDefault   bool                        `json:"is_default"`
+
+	RequireChecklist bool `json:"require_checklist"`
+}
+
+// UpdateWorkflowRequest представляет запрос на обновление рабочего процесса.
+// Статусы и переходы, если переданы, заменяются целиком; пустой список переходов
+// снимает ограничения и разрешает любые переходы.
+type UpdateWorkflowRequest struct {
+	Name        *string                     `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Statuses    []WorkflowStatusRequest     `json:"statuses,omitempty" binding:"omitempty,min=1,dive"`
+	Transitions []WorkflowTransitionRequest `json:"transitions,omitempty" binding:"omitempty,dive"`
+	IsDefault   *bool                       `json:"is_default,omitempty"`
+
+	RequireChecklist *bool `json:"require_checklist,omitempty"`
+}
+
+// DefaultWorkflow возвращает процесс по умолчанию с исходными тремя статусами
+func DefaultWorkflow(userID uint) *Workflow {
+	return &Workflow{
+		Name:      "Default",
+		UserID:    userID,
+		IsDefault: true,
+		Statuses: []WorkflowStatus{
+			{Key: TaskStatusPending, Name: "Pending", Category: StatusCategoryTodo, Position: 0},
+			{Key: TaskStatusInProgress, Name: "In progress", Category: StatusCategoryDoing, Position: 1},
+			{Key: TaskStatusCompleted, Name: "Completed", Category: StatusCategoryDone, Position: 2},
+		},
+	}
+}
+
+// IsValid проверяет валидность категории
+func (c StatusCategory) IsValid() bool {
+	switch c {
+	case StatusCategoryTodo, StatusCategoryDoing, StatusCategoryDone:
+		return true
+	}
+	return false
+}
+
+// FindStatus ищет статус процесса по ключу
+func (w *Workflow) FindStatus(key TaskStatus) (*WorkflowStatus, bool) {
+	for i := range w.Statuses {
+		if w.Statuses[i].Key == key {
+			return &w.Statuses[i], true
+		}
+	}
+	return nil, false
+}
+
+// InitialStatus возвращает статус для новых задач: первый статус категории todo,
+// а при его отсутствии — первый статус процесса
+func (w *Workflow) InitialStatus() TaskStatus {
+	for _, status := range w.Statuses {
+		if status.Category == StatusCategoryTodo {
+			return status.Key
+		}
+	}
+	if len(w.Statuses) > 0 {
+		return w.Statuses[0].Key
+	}
+	return TaskStatusPending
+}
+
+// CanTransition проверяет, разрешен ли переход между статусами. Процесс без переходов
+// не ограничивает смену статуса, а переход в тот же статус разрешен всегда.
+func (w *Workflow) CanTransition(from, to TaskStatus) bool {
+	if from == to || len(w.Transitions) == 0 {
+		return true
+	}
+	for _, transition := range w.Transitions {
+		if transition.FromStatus == from && transition.ToStatus == to {
+			return true
+		}
+	}
+	return false
+}
+
+// DoneStatuses возвращает ключи статусов категории done
+func (w *Workflow) DoneStatuses() []TaskStatus {
+	var keys []TaskStatus
+	for _, status := range w.Statuses {
+		if status.Category == StatusCategoryDone {
+			keys = append(keys, status.Key)
+		}
+	}
+	return keys
+}
+
+// StatusForCategory подбирает статус категории category для задачи в статусе from.
+// Если текущий статус уже в этой категории, он и возвращается; иначе выбирается
+// первый статус категории, в который разрешен переход, а при его отсутствии —
+// первый статус категории. Пустой результат означает, что статусов категории нет.
+func (w *Workflow) StatusForCategory(from TaskStatus, category StatusCategory) TaskStatus {
+	if current, ok := w.FindStatus(from); ok && current.Category == category {
+		return from
+	}
+
+	var fallback TaskStatus
+	for _, status := range w.Statuses {
+		if status.Category != category {
+			continue
+		}
+		if w.CanTransition(from, status.Key) {
+			return status.Key
+		}
+		if fallback == "" {
+			fallback = status.Key
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// WorkingCalendar задает рабочую неделю пользователя. Пока пользователь ее не изменил,
+// рабочими считаются дни с понедельника по пятницу.
+type WorkingCalendar struct {
+	ID     uint `json:"-" gorm:"primaryKey"`
+	UserID uint `json:"-" gorm:"not null;uniqueIndex"`
+	// Weekdays рабочие дни недели по ISO 8601: 1 — понедельник, 7 — воскресенье
+	Weekdays  []int     `json:"weekdays" gorm:"serializer:json"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// Holiday представляет нерабочий день пользователя. Imported отмечает дни,
+// загруженные из файла iCalendar: повторная загрузка с replace заменяет только их.
+type Holiday struct {
+	ID     uint `json:"-" gorm:"primaryKey"`
+	UserID uint `json:"-" gorm:"not null;uniqueIndex:idx_holiday_date"`
+	// Date дата в формате YYYY-MM-DD
+	Date      string    `json:"date" gorm:"not null;uniqueIndex:idx_holiday_date"`
+	Name      string    `json:"name"`
+	Imported  bool      `json:"imported" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WorkingCalendarParams представляет параметры получения календаря рабочих дней
+type WorkingCalendarParams struct {
+	// Year оставляет нерабочие дни одного года
+	Year int `form:"year" binding:"omitempty,min=1970,max=9999"`
+}
+
+// WorkingCalendarResponse представляет рабочую неделю и нерабочие дни пользователя
+type WorkingCalendarResponse struct {
+	Weekdays []int     `json:"weekdays"`
+	Holidays []Holiday `json:"holidays"`
+}
+
+// UpdateWorkingCalendarRequest представляет запрос на изменение рабочей недели
+type UpdateWorkingCalendarRequest struct {
+	Weekdays []int `json:"weekdays" binding:"required,min=1,max=7,dive,min=1,max=7"`
+}
+
+// CreateHolidayRequest представляет запрос на добавление нерабочего дня
+type CreateHolidayRequest struct {
+	Date string `json:"date" binding:"required"`
+	Name string `json:"name" binding:"max=255"`
+}
+
+// HolidayImportParams представляет параметры загрузки нерабочих дней из iCalendar
+type HolidayImportParams struct {
+	// Replace удаляет нерабочие дни, загруженные раньше; добавленные вручную сохраняются
+	Replace bool `form:"replace"`
+}
+
+// HolidayImportResult представляет итог загрузки нерабочих дней
+type HolidayImportResult struct {
+	// Imported число добавленных дней; дни, которые уже есть в календаре, не меняются
+	Imported int `json:"imported"`
+	// Skipped число событий, которые не удалось разобрать или чье повторение не поддерживается
+	Skipped int `json:"skipped"`
+}
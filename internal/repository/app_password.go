@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"time"
+
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AppPasswordRepository интерфейс для работы с паролями приложений
+type AppPasswordRepository interface {
+	Create(password *models.AppPassword) error
+	GetByUserID(userID uint) ([]models.AppPassword, error)
+	GetByTokenHash(tokenHash string) (*models.AppPassword, error)
+	TouchLastUsed(id uint, at time.Time) error
+	Delete(userID, id uint) (int64, error)
+}
+
+// appPasswordRepository реализация репозитория паролей приложений
+type appPasswordRepository struct {
+	db *gorm.DB
+}
+
+// NewAppPasswordRepository создает новый репозиторий паролей приложений
+func NewAppPasswordRepository(db *gorm.DB) AppPasswordRepository {
+	return &appPasswordRepository{
+		db: db,
+	}
+}
+
+// Create создает пароль приложения
+func (r *appPasswordRepository) Create(password *models.AppPassword) error {
+	return r.db.Create(password).Error
+}
+
+// GetByUserID получает пароли приложений пользователя в порядке создания
+func (r *appPasswordRepository) GetByUserID(userID uint) ([]models.AppPassword, error) {
+	var passwords []models.AppPassword
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&passwords).Error
+	return passwords, err
+}
+
+// GetByTokenHash получает пароль приложения по хешу токена
+func (r *appPasswordRepository) GetByTokenHash(tokenHash string) (*models.AppPassword, error) {
+	var password models.AppPassword
+	err := r.db.Where("token_hash = ?", tokenHash).First(&password).Error
+	if err != nil {
+		return nil, err
+	}
+	return &password, nil
+}
+
+// TouchLastUsed отмечает время последнего использования пароля
+func (r *appPasswordRepository) TouchLastUsed(id uint, at time.Time) error {
+	return r.db.Model(&models.AppPassword{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
+
+// Delete удаляет пароль приложения пользователя
+func (r *appPasswordRepository) Delete(userID, id uint) (int64, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.AppPassword{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CalendarFeedRepository интерфейс для работы с подписками на календарь задач
+type CalendarFeedRepository interface {
+	GetByUserID(userID uint) (*models.CalendarFeed, error)
+	GetByTokenHash(tokenHash string) (*models.CalendarFeed, error)
+	Save(feed *models.CalendarFeed) error
+	DeleteByUserID(userID uint) (int64, error)
+}
+
+// calendarFeedRepository реализация репозитория подписок на календарь
+type calendarFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarFeedRepository создает новый репозиторий подписок на календарь
+func NewCalendarFeedRepository(db *gorm.DB) CalendarFeedRepository {
+	return &calendarFeedRepository{
+		db: db,
+	}
+}
+
+// GetByUserID получает подписку пользователя
+func (r *calendarFeedRepository) GetByUserID(userID uint) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := r.db.Where("user_id = ?", userID).First(&feed).Error
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// GetByTokenHash получает подписку по хешу токена
+func (r *calendarFeedRepository) GetByTokenHash(tokenHash string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := r.db.Where("token_hash = ?", tokenHash).First(&feed).Error
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// Save создает подписку пользователя или заменяет ее токен
+func (r *calendarFeedRepository) Save(feed *models.CalendarFeed) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_hash", "created_at", "updated_at"}),
+	}).Create(feed).Error
+}
+
+// DeleteByUserID удаляет подписку пользователя
+func (r *calendarFeedRepository) DeleteByUserID(userID uint) (int64, error) {
+	result := r.db.Where("user_id = ?", userID).Delete(&models.CalendarFeed{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CalendarObjectRepository интерфейс для работы со связями задач и ресурсов CalDAV
+type CalendarObjectRepository interface {
+	WithTx(tx *gorm.DB) CalendarObjectRepository
+	GetByUserID(userID uint) ([]models.CalendarObject, error)
+	GetByName(userID uint, name string) (*models.CalendarObject, error)
+	Save(object *models.CalendarObject) error
+	DeleteByTaskID(taskID uint) error
+}
+
+// calendarObjectRepository реализация репозитория ресурсов CalDAV
+type calendarObjectRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarObjectRepository создает новый репозиторий ресурсов CalDAV
+func NewCalendarObjectRepository(db *gorm.DB) CalendarObjectRepository {
+	return &calendarObjectRepository{
+		db: db,
+	}
+}
+
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *calendarObjectRepository) WithTx(tx *gorm.DB) CalendarObjectRepository {
+	return &calendarObjectRepository{
+		db: tx,
+	}
+}
+
+// GetByUserID получает все связи ресурсов пользователя
+func (r *calendarObjectRepository) GetByUserID(userID uint) ([]models.CalendarObject, error) {
+	var objects []models.CalendarObject
+	err := r.db.Where("user_id = ?", userID).Find(&objects).Error
+	return objects, err
+}
+
+// GetByName получает связь по имени ресурса
+func (r *calendarObjectRepository) GetByName(userID uint, name string) (*models.CalendarObject, error) {
+	var object models.CalendarObject
+	err := r.db.Where("user_id = ? AND name = ?", userID, name).First(&object).Error
+	if err != nil {
+		return nil, err
+	}
+	return &object, nil
+}
+
+// Save создает связь или переносит имя ресурса на другую задачу
+func (r *calendarObjectRepository) Save(object *models.CalendarObject) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"task_id", "uid", "created_at"}),
+	}).Create(object).Error
+}
+
+// DeleteByTaskID удаляет связи задачи
+func (r *calendarObjectRepository) DeleteByTaskID(taskID uint) error {
+	return r.db.Where("task_id = ?", taskID).Delete(&models.CalendarObject{}).Error
+}
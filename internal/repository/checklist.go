@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ChecklistRepository интерфейс для работы с пунктами чек-листов задач
+type ChecklistRepository interface {
+	WithTx(tx *gorm.DB) ChecklistRepository
+	Create(item *models.ChecklistItem) error
+	GetByID(id uint) (*models.ChecklistItem, error)
+	GetByTaskID(taskID uint) ([]models.ChecklistItem, error)
+	GetLastPosition(taskID uint) (string, error)
+	Update(item *models.ChecklistItem) error
+	UpdatePosition(id uint, position string) error
+	Delete(id uint) error
+	DeleteByTaskID(taskID uint) error
+	CountOpenRequired(taskID uint) (int64, error)
+	GetCounts(taskIDs []uint) (map[uint]models.ChecklistCounts, error)
+}
+
+// checklistRepository реализация репозитория чек-листов
+type checklistRepository struct {
+	db *gorm.DB
+}
+
+// NewChecklistRepository создает новый репозиторий чек-листов
+func NewChecklistRepository(db *gorm.DB) ChecklistRepository {
+	return &checklistRepository{
+		db: db,
+	}
+}
+
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *checklistRepository) WithTx(tx *gorm.DB) ChecklistRepository {
+	return &checklistRepository{
+		db: tx,
+	}
+}
+
+// Create создает пункт чек-листа
+func (r *checklistRepository) Create(item *models.ChecklistItem) error {
+	return r.db.Create(item).Error
+}
+
+// GetByID получает пункт чек-листа по ID
+func (r *checklistRepository) GetByID(id uint) (*models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	err := r.db.First(&item, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetByTaskID получает пункты чек-листа задачи по порядку
+func (r *checklistRepository) GetByTaskID(taskID uint) ([]models.ChecklistItem, error) {
+	var items []models.ChecklistItem
+	err := r.db.Where("task_id = ?", taskID).Order("position ASC, id ASC").Find(&items).Error
+	return items, err
+}
+
+// GetLastPosition возвращает позицию последнего пункта чек-листа или пустую строку
+func (r *checklistRepository) GetLastPosition(taskID uint) (string, error) {
+	var position string
+	err := r.db.Model(&models.ChecklistItem{}).
+		Where("task_id = ?", taskID).
+		Select("COALESCE(MAX(position), '')").
+		Scan(&position).Error
+	return position, err
+}
+
+// Update обновляет пункт чек-листа
+func (r *checklistRepository) Update(item *models.ChecklistItem) error {
+	return r.db.Save(item).Error
+}
+
+// UpdatePosition меняет позицию пункта чек-листа
+func (r *checklistRepository) UpdatePosition(id uint, position string) error {
+	return r.db.Model(&models.ChecklistItem{}).Where("id = ?", id).UpdateColumn("position", position).Error
+}
+
+// Delete удаляет пункт чек-листа
+func (r *checklistRepository) Delete(id uint) error {
+	return r.db.Delete(&models.ChecklistItem{}, id).Error
+}
+
+// DeleteByTaskID удаляет все пункты чек-листа задачи
+func (r *checklistRepository) DeleteByTaskID(taskID uint) error {
+	return r.db.Where("task_id = ?", taskID).Delete(&models.ChecklistItem{}).Error
+}
+
+// CountOpenRequired считает невыполненные обязательные пункты чек-листа задачи
+func (r *checklistRepository) CountOpenRequired(taskID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ChecklistItem{}).
+		Where("task_id = ? AND required = ? AND done = ?", taskID, true, false).
+		Count(&count).Error
+	return count, err
+}
+
+// GetCounts считает выполненные и все пункты чек-листов задач
+func (r *checklistRepository) GetCounts(taskIDs []uint) (map[uint]models.ChecklistCounts, error) {
+	counts := make(map[uint]models.ChecklistCounts, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		TaskID    uint
+		Completed int
+		Total     int
+	}
+	err := r.db.Model(&models.ChecklistItem{}).
+		Select("task_id, SUM(CASE WHEN done THEN 1 ELSE 0 END) AS completed, COUNT(*) AS total").
+		Where("task_id IN ?", taskIDs).
+		Group("task_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.TaskID] = models.ChecklistCounts{Completed: row.Completed, Total: row.Total}
+	}
+
+	return counts, nil
+}
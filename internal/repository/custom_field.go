@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CustomFieldRepository интерфейс для работы с пользовательскими полями
+type CustomFieldRepository interface {
+	WithTx(tx *gorm.DB) CustomFieldRepository
+	Create(field *models.CustomField) error
+	GetByID(id uint) (*models.CustomField, error)
+	GetByProjectID(projectID uint) ([]models.CustomField, error)
+	GetByKey(userID uint, key string, projectID *uint) ([]models.CustomField, error)
+	GetKeys(userID uint, projectID *uint) ([]string, error)
+	Update(field *models.CustomField) error
+	Delete(id uint) error
+	CountValuesWithOptions(fieldID uint, options []string) (int64, error)
+	ReplaceValues(taskID uint, fieldIDs []uint, values []models.CustomFieldValue) error
+	DeleteValuesByTaskID(taskID uint) error
+}
+
+// customFieldRepository реализация репозитория пользовательских полей
+type customFieldRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomFieldRepository создает новый репозиторий пользовательских полей
+func NewCustomFieldRepository(db *gorm.DB) CustomFieldRepository {
+	return &customFieldRepository{
+		db: db,
+	}
+}
+
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *customFieldRepository) WithTx(tx *gorm.DB) CustomFieldRepository {
+	return &customFieldRepository{
+		db: tx,
+	}
+}
+
+// Create создает новое пользовательское поле
+func (r *customFieldRepository) Create(field *models.CustomField) error {
+	return r.db.Create(field).Error
+}
+
+// GetByID получает пользовательское поле по ID
+func (r *customFieldRepository) GetByID(id uint) (*models.CustomField, error) {
+	var field models.CustomField
+	err := r.db.First(&field, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+// GetByProjectID получает пользовательские поля проекта
+func (r *customFieldRepository) GetByProjectID(projectID uint) ([]models.CustomField, error) {
+	var fields []models.CustomField
+	err := r.db.Where("project_id = ?", projectID).Order("id ASC").Find(&fields).Error
+	return fields, err
+}
+
+// GetByKey получает поля с ключом key в проектах пользователя (или в одном проекте)
+func (r *customFieldRepository) GetByKey(userID uint, key string, projectID *uint) ([]models.CustomField, error) {
+	var fields []models.CustomField
+	query := r.db.Joins("JOIN projects ON projects.id = custom_fields.project_id").
+		Where("projects.user_id = ? AND custom_fields.key = ?", userID, key)
+	if projectID != nil {
+		query = query.Where("custom_fields.project_id = ?", *projectID)
+	}
+	err := query.Find(&fields).Error
+	return fields, err
+}
+
+// GetKeys получает ключи полей в проектах пользователя (или в одном проекте) по алфавиту
+func (r *customFieldRepository) GetKeys(userID uint, projectID *uint) ([]string, error) {
+	var keys []string
+	query := r.db.Model(&models.CustomField{}).
+		Joins("JOIN projects ON projects.id = custom_fields.project_id").
+		Where("projects.user_id = ?", userID)
+	if projectID != nil {
+		query = query.Where("custom_fields.project_id = ?", *projectID)
+	}
+	err := query.Distinct().Order("custom_fields.key ASC").Pluck("custom_fields.key", &keys).Error
+	return keys, err
+}
+
+// Update обновляет пользовательское поле
+func (r *customFieldRepository) Update(field *models.CustomField) error {
+	return r.db.Save(field).Error
+}
+
+// Delete удаляет пользовательское поле вместе со значениями
+func (r *customFieldRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("field_id = ?", id).Delete(&models.CustomFieldValue{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.CustomField{}, id).Error
+	})
+}
+
+// CountValuesWithOptions считает значения поля, использующие указанные варианты выбора
+func (r *customFieldRepository) CountValuesWithOptions(fieldID uint, options []string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.CustomFieldValue{}).
+		Where("field_id = ? AND text_value IN ?", fieldID, options).
+		Count(&count).Error
+	return count, err
+}
+
+// ReplaceValues заменяет значения указанных полей задачи
+func (r *customFieldRepository) ReplaceValues(taskID uint, fieldIDs []uint, values []models.CustomFieldValue) error {
+	if len(fieldIDs) > 0 {
+		err := r.db.Where("task_id = ? AND field_id IN ?", taskID, fieldIDs).
+			Delete(&models.CustomFieldValue{}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	for i := range values {
+		values[i].TaskID = taskID
+	}
+	return r.db.Omit("Field").Create(&values).Error
+}
+
+// DeleteValuesByTaskID удаляет все значения пользовательских полей задачи
+func (r *customFieldRepository) DeleteValuesByTaskID(taskID uint) error {
+	return r.db.Where("task_id = ?", taskID).Delete(&models.CustomFieldValue{}).Error
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// IdempotencyKeyRepository интерфейс для работы с ключами идемпотентности
+type IdempotencyKeyRepository interface {
+	Create(record *models.IdempotencyKey) error
+	GetByKey(userID uint, key string) (*models.IdempotencyKey, error)
+	Update(record *models.IdempotencyKey) error
+	Delete(id uint) error
+	DeleteExpired(now int64) (int64, error)
+}
+
+// idempotencyKeyRepository реализация репозитория ключей идемпотентности
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyKeyRepository создает новый репозиторий ключей идемпотентности
+func NewIdempotencyKeyRepository(db *gorm.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{
+		db: db,
+	}
+}
+
+// Create сохраняет новый ключ; повторный ключ пользователя нарушает уникальный индекс
+func (r *idempotencyKeyRepository) Create(record *models.IdempotencyKey) error {
+	return r.db.Create(record).Error
+}
+
+// GetByKey получает ключ пользователя
+func (r *idempotencyKeyRepository) GetByKey(userID uint, key string) (*models.IdempotencyKey, error) {
+	var record models.IdempotencyKey
+	err := r.db.Where("user_id = ? AND key = ?", userID, key).First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Update сохраняет результат запроса
+func (r *idempotencyKeyRepository) Update(record *models.IdempotencyKey) error {
+	return r.db.Save(record).Error
+}
+
+// Delete удаляет ключ
+func (r *idempotencyKeyRepository) Delete(id uint) error {
+	return r.db.Delete(&models.IdempotencyKey{}, id).Error
+}
+
+// DeleteExpired удаляет ключи, срок хранения которых истек к моменту now (Unix-время)
+func (r *idempotencyKeyRepository) DeleteExpired(now int64) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"time"
+
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ImportJobRepository интерфейс для работы с фоновыми загрузками задач
+type ImportJobRepository interface {
+	Create(job *models.ImportJob) error
+	GetByID(id uint) (*models.ImportJob, error)
+	Update(job *models.ImportJob) error
+	FailUnfinished(message string) (int64, error)
+}
+
+// importJobRepository реализация репозитория фоновых загрузок
+type importJobRepository struct {
+	db *gorm.DB
+}
+
+// NewImportJobRepository создает новый репозиторий фоновых загрузок
+func NewImportJobRepository(db *gorm.DB) ImportJobRepository {
+	return &importJobRepository{
+		db: db,
+	}
+}
+
+// Create создает фоновую загрузку
+func (r *importJobRepository) Create(job *models.ImportJob) error {
+	return r.db.Create(job).Error
+}
+
+// GetByID получает фоновую загрузку по ID
+func (r *importJobRepository) GetByID(id uint) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.db.First(&job, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Update сохраняет состояние фоновой загрузки
+func (r *importJobRepository) Update(job *models.ImportJob) error {
+	return r.db.Save(job).Error
+}
+
+// FailUnfinished отмечает незавершенные загрузки как неудавшиеся
+func (r *importJobRepository) FailUnfinished(message string) (int64, error) {
+	result := r.db.Model(&models.ImportJob{}).
+		Where("status IN ?", []models.ImportJobStatus{models.ImportJobPending, models.ImportJobValidating, models.ImportJobImporting}).
+		Updates(map[string]interface{}{
+			"status":      models.ImportJobFailed,
+			"error":       message,
+			"finished_at": time.Now().UTC(),
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NotificationRepository интерфейс для работы с уведомлениями и их настройками
+type NotificationRepository interface {
+	WithTx(tx *gorm.DB) NotificationRepository
+	CreateBatch(notifications []models.Notification) error
+	GetByID(id uint) (*models.Notification, error)
+	GetByUserID(userID uint, params models.NotificationParams) ([]models.Notification, int64, error)
+	CountUnread(userID uint) (int64, error)
+	MarkRead(id uint) error
+	MarkAllRead(userID uint) (int64, error)
+	DeleteByTaskID(taskID uint) error
+	GetPreferences(userIDs []uint) (map[uint]models.NotificationPreference, error)
+	SavePreferences(preference *models.NotificationPreference) error
+}
+
+// notificationRepository реализация репозитория уведомлений
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository создает новый репозиторий уведомлений
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *notificationRepository) WithTx(tx *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: tx,
+	}
+}
+
+// CreateBatch создает уведомления
+func (r *notificationRepository) CreateBatch(notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.Omit("Actor").Create(&notifications).Error
+}
+
+// GetByID получает уведомление по ID
+func (r *notificationRepository) GetByID(id uint) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.db.First(&notification, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// GetByUserID получает уведомления пользователя, начиная с последних
+func (r *notificationRepository) GetByUserID(userID uint, params models.NotificationParams) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var total int64
+
+	query := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if params.Unread {
+		query = query.Where("read = ?", false)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if params.Page > 0 && params.Limit > 0 {
+		offset := (params.Page - 1) * params.Limit
+		query = query.Offset(offset).Limit(params.Limit)
+	}
+
+	err := query.Preload("Actor").Order("id DESC").Find(&notifications).Error
+	return notifications, total, err
+}
+
+// CountUnread считает непрочитанные уведомления пользователя
+func (r *notificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read = ?", userID, false).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead отмечает уведомление прочитанным
+func (r *notificationRepository) MarkRead(id uint) error {
+	return r.db.Model(&models.Notification{}).Where("id = ?", id).UpdateColumn("read", true).Error
+}
+
+// MarkAllRead отмечает прочитанными все уведомления пользователя и возвращает их число
+func (r *notificationRepository) MarkAllRead(userID uint) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read = ?", userID, false).
+		UpdateColumn("read", true)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteByTaskID удаляет уведомления об изменениях задачи
+func (r *notificationRepository) DeleteByTaskID(taskID uint) error {
+	return r.db.Where("task_id = ?", taskID).Delete(&models.Notification{}).Error
+}
+
+// GetPreferences получает сохраненные настройки уведомлений пользователей
+func (r *notificationRepository) GetPreferences(userIDs []uint) (map[uint]models.NotificationPreference, error) {
+	preferences := make(map[uint]models.NotificationPreference, len(userIDs))
+	if len(userIDs) == 0 {
+		return preferences, nil
+	}
+
+	var rows []models.NotificationPreference
+	if err := r.db.Where("user_id IN ?", userIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		preferences[row.UserID] = row
+	}
+	return preferences, nil
+}
+
+// SavePreferences сохраняет настройки уведомлений пользователя
+func (r *notificationRepository) SavePreferences(preference *models.NotificationPreference) error {
+	return r.db.Save(preference).Error
+}
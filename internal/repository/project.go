@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ProjectRepository интерфейс для работы с проектами
+type ProjectRepository interface {
+	WithTx(tx *gorm.DB) ProjectRepository
+	Create(project *models.Project) error
+	GetByID(id uint) (*models.Project, error)
+	GetByUserID(userID uint) ([]models.Project, error)
+	Update(project *models.Project) error
+	Delete(id uint) error
+	CountTasks(id uint) (int64, error)
+	HasAccess(id, userID uint) (bool, error)
+}
+
+// accessibleProjects подзапрос ID проектов, доступных пользователю: его собственных
+// и проектов, на задачи которых он подписан (как назначенный или упомянутый пользователь)
+func accessibleProjects(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Raw(
+		"SELECT id FROM projects WHERE user_id = ? "+
+			"UNION SELECT tasks.project_id FROM task_watchers JOIN tasks ON tasks.id = task_watchers.task_id "+
+			"WHERE task_watchers.user_id = ? AND tasks.project_id IS NOT NULL AND tasks.deleted_at IS NULL",
+		userID, userID,
+	)
+}
+
+// projectRepository реализация репозитория проектов
+type projectRepository struct {
+	db *gorm.DB
+}
+
+// NewProjectRepository создает новый репозиторий проектов
+func NewProjectRepository(db *gorm.DB) ProjectRepository {
+	return &projectRepository{
+		db: db,
+	}
+}
+
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *projectRepository) WithTx(tx *gorm.DB) ProjectRepository {
+	return &projectRepository{
+		db: tx,
+	}
+}
+
+// Create создает новый проект
+func (r *projectRepository) Create(project *models.Project) error {
+	return r.db.Omit("Workflow", "User").Create(project).Error
+}
+
+// GetByID получает проект по ID
+func (r *projectRepository) GetByID(id uint) (*models.Project, error) {
+	var project models.Project
+	err := r.db.First(&project, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// GetByUserID получает проекты пользователя
+func (r *projectRepository) GetByUserID(userID uint) ([]models.Project, error) {
+	var projects []models.Project
+	err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&projects).Error
+	return projects, err
+}
+
+// Update обновляет проект
+func (r *projectRepository) Update(project *models.Project) error {
+	return r.db.Omit("Workflow", "User").Save(project).Error
+}
+
+// Delete удаляет проект вместе с его сохраненными представлениями, шаблонами задач
+// и политиками SLA
+func (r *projectRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ?", id).Delete(&models.SavedView{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", id).Delete(&models.TaskTemplate{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", id).Delete(&models.SLAPolicy{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Project{}, id).Error
+	})
+}
+
+// CountTasks считает задачи проекта, включая задачи в корзине
+func (r *projectRepository) CountTasks(id uint) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Task{}).Where("project_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// HasAccess проверяет, что проект доступен пользователю
+func (r *projectRepository) HasAccess(id, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Project{}).
+		Where("id = ? AND id IN (?)", id, accessibleProjects(r.db, userID)).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SavedViewRepository интерфейс для работы с сохраненными представлениями
+type SavedViewRepository interface {
+	WithTx(tx *gorm.DB) SavedViewRepository
+	Create(view *models.SavedView) error
+	GetByID(id uint) (*models.SavedView, error)
+	GetVisible(userID uint, projectID *uint) ([]models.SavedView, error)
+	GetDefault(userID uint) (*models.SavedView, error)
+	Update(view *models.SavedView) error
+	Delete(id uint) error
+	ClearDefault(userID uint) error
+}
+
+// savedViewRepository реализация репозитория представлений
+type savedViewRepository struct {
+	db *gorm.DB
+}
+
+// NewSavedViewRepository создает новый репозиторий представлений
+func NewSavedViewRepository(db *gorm.DB) SavedViewRepository {
+	return &savedViewRepository{
+		db: db,
+	}
+}
+
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *savedViewRepository) WithTx(tx *gorm.DB) SavedViewRepository {
+	return &savedViewRepository{
+		db: tx,
+	}
+}
+
+// Create создает представление
+func (r *savedViewRepository) Create(view *models.SavedView) error {
+	return r.db.Create(view).Error
+}
+
+// GetByID получает представление по ID
+func (r *savedViewRepository) GetByID(id uint) (*models.SavedView, error) {
+	var view models.SavedView
+	err := r.db.First(&view, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// GetVisible получает представления пользователя и открытые представления доступных ему проектов
+func (r *savedViewRepository) GetVisible(userID uint, projectID *uint) ([]models.SavedView, error) {
+	var views []models.SavedView
+
+	query := r.db.Where(
+		"user_id = ? OR (shared = ? AND project_id IN (?))",
+		userID, true, accessibleProjects(r.db, userID),
+	)
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	}
+
+	err := query.Order("name ASC, id ASC").Find(&views).Error
+	return views, err
+}
+
+// GetDefault получает представление пользователя по умолчанию
+func (r *savedViewRepository) GetDefault(userID uint) (*models.SavedView, error) {
+	var view models.SavedView
+	err := r.db.Where("user_id = ? AND is_default = ?", userID, true).First(&view).Error
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// Update обновляет представление
+func (r *savedViewRepository) Update(view *models.SavedView) error {
+	return r.db.Save(view).Error
+}
+
+// Delete удаляет представление
+func (r *savedViewRepository) Delete(id uint) error {
+	return r.db.Delete(&models.SavedView{}, id).Error
+}
+
+// ClearDefault снимает отметку "по умолчанию" со всех представлений пользователя
+func (r *savedViewRepository) ClearDefault(userID uint) error {
+	return r.db.Model(&models.SavedView{}).
+		Where("user_id = ? AND is_default = ?", userID, true).
+		UpdateColumn("is_default", false).Error
+}
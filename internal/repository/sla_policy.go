@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SLAPolicyRepository интерфейс для работы с политиками SLA
+type SLAPolicyRepository interface {
+	WithTx(tx *gorm.DB) SLAPolicyRepository
+	Create(policy *models.SLAPolicy) error
+	GetByID(id uint) (*models.SLAPolicy, error)
+	GetByUserID(userID uint) ([]models.SLAPolicy, error)
+	Update(policy *models.SLAPolicy) error
+	Delete(id uint) error
+}
+
+// slaPolicyRepository реализация репозитория политик SLA
+type slaPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewSLAPolicyRepository создает новый репозиторий политик SLA
+func NewSLAPolicyRepository(db *gorm.DB) SLAPolicyRepository {
+	return &slaPolicyRepository{
+		db: db,
+	}
+}
+
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *slaPolicyRepository) WithTx(tx *gorm.DB) SLAPolicyRepository {
+	return &slaPolicyRepository{
+		db: tx,
+	}
+}
+
+// Create создает политику SLA
+func (r *slaPolicyRepository) Create(policy *models.SLAPolicy) error {
+	return r.db.Create(policy).Error
+}
+
+// GetByID получает политику SLA по ID
+func (r *slaPolicyRepository) GetByID(id uint) (*models.SLAPolicy, error) {
+	var policy models.SLAPolicy
+	err := r.db.First(&policy, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// GetByUserID получает политики SLA пользователя
+func (r *slaPolicyRepository) GetByUserID(userID uint) ([]models.SLAPolicy, error) {
+	var policies []models.SLAPolicy
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&policies).Error
+	return policies, err
+}
+
+// Update обновляет политику SLA
+func (r *slaPolicyRepository) Update(policy *models.SLAPolicy) error {
+	return r.db.Save(policy).Error
+}
+
+// Delete удаляет политику SLA
+func (r *slaPolicyRepository) Delete(id uint) error {
+	return r.db.Delete(&models.SLAPolicy{}, id).Error
+}
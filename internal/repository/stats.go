@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// StatsRepository интерфейс для агрегатов статистики по задачам.
+// Все запросы считаются в SQL и учитывают задачи из архива, но не из корзины.
+type StatsRepository interface {
+	CountByStatus(userID uint, projectID *uint) ([]models.StatusCount, error)
+	CountByCategory(userID uint, projectID *uint) ([]models.CategoryCount, error)
+	CountOverdue(userID uint, projectID *uint, before time.Time) ([]models.PriorityCount, error)
+	CountOpenBefore(userID uint, statsRange models.StatsRange) (int64, error)
+	DailyCreated(userID uint, statsRange models.StatsRange) ([]models.DailyCount, error)
+	DailyCompleted(userID uint, statsRange models.StatsRange) ([]models.DailyCount, error)
+	CycleTime(userID uint, statsRange models.StatsRange) (*models.CycleTimeStats, error)
+}
+
+// statsRepository реализация репозитория статистики
+type statsRepository struct {
+	db *gorm.DB
+}
+
+// NewStatsRepository создает новый репозиторий статистики
+func NewStatsRepository(db *gorm.DB) StatsRepository {
+	return &statsRepository{
+		db: db,
+	}
+}
+
+// tasks возвращает запрос к задачам пользователя, при необходимости в проекте
+func (r *statsRepository) tasks(userID uint, projectID *uint) *gorm.DB {
+	query := r.db.Model(&models.Task{}).Where("tasks.user_id = ?", userID)
+	if projectID != nil {
+		query = query.Where("tasks.project_id = ?", *projectID)
+	}
+	return query
+}
+
+// CountByStatus считает задачи по статусам
+func (r *statsRepository) CountByStatus(userID uint, projectID *uint) ([]models.StatusCount, error) {
+	counts := []models.StatusCount{}
+	err := r.tasks(userID, projectID).
+		Select("tasks.status AS status, COUNT(*) AS count").
+		Group("tasks.status").
+		Order("tasks.status ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// CountByCategory считает задачи по категориям их статусов. Задачи без процесса
+// следуют процессу пользователя по умолчанию; статусы, которых нет в процессе,
+// попадают в категорию с пустым именем.
+func (r *statsRepository) CountByCategory(userID uint, projectID *uint) ([]models.CategoryCount, error) {
+	counts := []models.CategoryCount{}
+	err := r.tasks(userID, projectID).
+		Select("COALESCE(workflow_statuses.category, '') AS category, COUNT(*) AS count").
+		Joins("LEFT JOIN workflow_statuses ON workflow_statuses.key = tasks.status AND workflow_statuses.workflow_id = " +
+			"COALESCE(tasks.workflow_id, (SELECT id FROM workflows WHERE workflows.user_id = tasks.user_id AND workflows.is_default = 1 LIMIT 1))").
+		Group("category").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// CountOverdue считает незавершенные задачи не из архива с end_date раньше before по приоритетам
+func (r *statsRepository) CountOverdue(userID uint, projectID *uint, before time.Time) ([]models.PriorityCount, error) {
+	counts := []models.PriorityCount{}
+	err := r.tasks(userID, projectID).
+		Select("tasks.priority AS priority, COUNT(*) AS count").
+		Where("tasks.completed_at IS NULL AND tasks.archived_at IS NULL AND tasks.end_date < ?", before).
+		Group("tasks.priority").
+		Order(priorityOrder + " DESC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// CountOpenBefore считает задачи, созданные до начала периода и не завершенные к нему
+func (r *statsRepository) CountOpenBefore(userID uint, statsRange models.StatsRange) (int64, error) {
+	var count int64
+	err := r.tasks(userID, statsRange.ProjectID).
+		Where("tasks.created_at < ?", statsRange.From).
+		Where("tasks.completed_at IS NULL OR tasks.completed_at >= ?", statsRange.From).
+		Count(&count).Error
+	return count, err
+}
+
+// DailyCreated считает задачи, созданные за каждый день периода
+func (r *statsRepository) DailyCreated(userID uint, statsRange models.StatsRange) ([]models.DailyCount, error) {
+	return r.daily(userID, statsRange, "tasks.created_at")
+}
+
+// DailyCompleted считает задачи, завершенные за каждый день периода
+func (r *statsRepository) DailyCompleted(userID uint, statsRange models.StatsRange) ([]models.DailyCount, error) {
+	return r.daily(userID, statsRange, "tasks.completed_at")
+}
+
+// daily группирует задачи периода по дням из значения столбца column. Время хранится
+// в UTC, а день зависит от часового пояса пользователя, поэтому задачи соединяются
+// с границами дней периода, переданными сервисом.
+func (r *statsRepository) daily(userID uint, statsRange models.StatsRange, column string) ([]models.DailyCount, error) {
+	counts := []models.DailyCount{}
+	if len(statsRange.Days) == 0 {
+		return counts, nil
+	}
+
+	days := make([]string, len(statsRange.Days))
+	args := make([]interface{}, 0, len(statsRange.Days)*3)
+	for i, day := range statsRange.Days {
+		days[i] = "SELECT ? AS day, ? AS day_start, ? AS day_end"
+		args = append(args, day.Date, day.From, day.To)
+	}
+
+	err := r.tasks(userID, statsRange.ProjectID).
+		Select("days.day AS day, COUNT(*) AS count").
+		Joins("JOIN ("+strings.Join(days, " UNION ALL ")+") AS days ON "+
+			column+" >= days.day_start AND "+column+" < days.day_end", args...).
+		Where(column+" >= ? AND "+column+" < ?", statsRange.From, statsRange.To).
+		Group("days.day").
+		Order("days.day ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// CycleTime считает среднее время от создания до завершения задач, завершенных за период.
+// Начало берется из записи истории о создании задачи; у задач, созданных до появления
+// истории, — из created_at.
+func (r *statsRepository) CycleTime(userID uint, statsRange models.StatsRange) (*models.CycleTimeStats, error) {
+	var stats models.CycleTimeStats
+	err := r.tasks(userID, statsRange.ProjectID).
+		Select("COUNT(*) AS completed, "+
+			"COALESCE(AVG((julianday(substr(tasks.completed_at, 1, 19)) - "+
+			"julianday(substr(COALESCE(created.created_at, tasks.created_at), 1, 19))) * 86400), 0) AS average_seconds").
+		Joins("LEFT JOIN (SELECT task_id, MIN(created_at) AS created_at FROM task_revisions "+
+			"WHERE action = ? GROUP BY task_id) AS created ON created.task_id = tasks.id", models.TaskActionCreated).
+		Where("tasks.completed_at IS NOT NULL").
+		Where("tasks.completed_at >= ? AND tasks.completed_at < ?", statsRange.From, statsRange.To).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -1,20 +1,57 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"golang_server/internal/models"
-	
+	"golang_server/pkg/fts"
+
 	"gorm.io/gorm"
 )
 
 // TaskRepository интерфейс для работы с задачами
 type TaskRepository interface {
+	WithTx(tx *gorm.DB) TaskRepository
 	Create(task *models.Task) error
 	GetByID(id uint) (*models.Task, error)
 	GetByUserID(userID uint, params models.TaskQueryParams) ([]models.Task, int64, error)
+	GetSortValues(taskID uint, params models.TaskQueryParams) ([]interface{}, error)
+	Search(userID uint, match string, params models.TaskSearchParams) ([]models.TaskSearchHit, int64, error)
+	GetByIDs(ids []uint) ([]models.Task, error)
 	Update(task *models.Task) error
-	Delete(id uint) error
+	Delete(id uint, version int) error
+	GetByIDUnscoped(id uint) (*models.Task, error)
+	GetTrash(userID uint, params models.TaskQueryParams) ([]models.Task, int64, error)
+	GetTrashIDs(userID uint) ([]uint, error)
+	GetTrashedBefore(before time.Time, limit int) ([]uint, error)
+	Restore(id uint) error
+	SetArchived(id uint, version int, archivedAt *time.Time) error
+	GetCompletedBefore(userID *uint, projectID *uint, before time.Time, afterID uint, limit int) ([]models.Task, error)
+	GetOpenWithSLA(now time.Time, afterID uint, limit int) ([]models.Task, error)
+	SetSLABreached(id uint, version int, breachedAt time.Time) error
+	IncrementVersion(id uint) error
+	DetachChildren(parentID uint) error
+	Purge(id uint) error
+	GetBoard(userID uint, projectID *uint) ([]models.Task, error)
+	GetForCalendar(userID uint, filter models.CalendarTaskFilter) ([]models.Task, error)
+	GetForTimeline(userID uint, filter models.TimelineFilter) ([]models.Task, error)
+	GetLastPosition(userID uint, projectID *uint, status models.TaskStatus) (string, error)
+	GetNextPosition(userID uint, projectID *uint, status models.TaskStatus, position string) (string, error)
+	GetPrevPosition(userID uint, projectID *uint, status models.TaskStatus, position string) (string, error)
+	UpdatePosition(id uint, version int, status models.TaskStatus, position string, completedAt *time.Time) error
 }
 
+// ErrVersionConflict возвращается, если задачу изменили после того, как она была прочитана
+var ErrVersionConflict = errors.New("version conflict")
+
+// priorityOrder выражение для сортировки по приоритету от низкого к критическому
+const priorityOrder = "CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 WHEN 'critical' THEN 4 ELSE 0 END"
+
+// searchWeights веса столбцов title и description при ранжировании: совпадение
+// в названии важнее совпадения в описании
+const searchWeights = "10.0, 1.0"
+
 // taskRepository реализация репозитория задач
 type taskRepository struct {
 	db *gorm.DB
@@ -27,15 +64,22 @@ func NewTaskRepository(db *gorm.DB) TaskRepository {
 	}
 }
 
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *taskRepository) WithTx(tx *gorm.DB) TaskRepository {
+	return &taskRepository{
+		db: tx,
+	}
+}
+
 // Create создает новую задачу
 func (r *taskRepository) Create(task *models.Task) error {
-	return r.db.Create(task).Error
+	return r.db.Omit("CustomFieldValues").Create(task).Error
 }
 
 // GetByID получает задачу по ID
 func (r *taskRepository) GetByID(id uint) (*models.Task, error) {
 	var task models.Task
-	err := r.db.Preload("User").First(&task, id).Error
+	err := r.db.Preload("User").Preload("CustomFieldValues.Field").First(&task, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -49,51 +93,433 @@ func (r *taskRepository) GetByUserID(userID uint, params models.TaskQueryParams)
 
 	query := r.db.Where("user_id = ?", userID)
 
+	// Задачи из архива показываются только по запросу
+	if params.ArchivedOnly {
+		query = query.Where("archived_at IS NOT NULL")
+	} else if !params.IncludeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
+
+	// Просроченные задачи: не завершены и окончились раньше границы
+	if params.OverdueBefore != nil {
+		query = query.Where("completed_at IS NULL AND end_date < ?", *params.OverdueBefore)
+	}
+
 	// Фильтрация по статусу
 	if params.Status != "" {
 		query = query.Where("status = ?", params.Status)
 	}
 
-	// Поиск по названию
-	if params.Search != "" {
-		query = query.Where("title ILIKE ?", "%"+params.Search+"%")
+	// Фильтрация по проекту
+	if params.ProjectID != nil {
+		query = query.Where("project_id = ?", *params.ProjectID)
+	}
+
+	// Фильтрация по пользовательским полям через индекс (field_id, значение)
+	for _, filter := range params.CustomFieldFilters {
+		query = query.Where(
+			"id IN (SELECT task_id FROM custom_field_values WHERE field_id IN ? AND "+filter.Column+" "+filter.Operator+" ?)",
+			filter.FieldIDs, filter.Value,
+		)
+	}
+
+	// Полнотекстовый поиск по названию и описанию
+	// (запрос без слов, например из одних знаков препинания, ничего не находит)
+	match := fts.Query(params.Search)
+	if match != "" {
+		query = query.Where("id IN (SELECT rowid FROM tasks_fts WHERE tasks_fts MATCH ?)", match)
+	} else if params.Search != "" {
+		query = query.Where("1 = 0")
+	}
+
+	// Фильтрация по выражению фильтра, скомпилированному сервисом
+	if params.FilterCondition != "" {
+		query = query.Where(params.FilterCondition, params.FilterArgs...)
 	}
 
 	// Подсчет общего количества
-	query.Model(&models.Task{}).Count(&total)
+	if !params.SkipCount {
+		query.Model(&models.Task{}).Count(&total)
+	}
 
-	// Сортировка
-	orderBy := "created_at"
-	if params.Sort != "" {
-		switch params.Sort {
-		case "created_at", "start_date", "end_date", "status", "title":
-			orderBy = params.Sort
+	// Сортировка; при пагинации по курсору выбираются задачи после граничной,
+	// а для предыдущей страницы порядок обращается
+	keys := taskSortKeys(params)
+	if params.CursorBackward {
+		keys = reverseSortKeys(keys)
+	}
+	if params.CursorValues != nil {
+		if len(params.CursorValues) != len(keys) {
+			return nil, 0, errors.New("invalid cursor")
 		}
+		condition, vars := keysetCondition(keys, params.CursorValues)
+		query = query.Where(condition, vars...)
 	}
+	query = query.Order(orderClause(keys))
 
-	order := "DESC"
-	if params.Order == "asc" {
-		order = "ASC"
+	// Пагинация
+	if params.CursorValues != nil && params.Limit > 0 {
+		query = query.Limit(params.Limit)
+	} else if params.Page > 0 && params.Limit > 0 {
+		offset := (params.Page - 1) * params.Limit
+		query = query.Offset(offset).Limit(params.Limit)
 	}
 
-	query = query.Order(orderBy + " " + order)
+	err := query.Preload("CustomFieldValues.Field").Find(&tasks).Error
+	return tasks, total, err
+}
+
+// Search выполняет полнотекстовый поиск по задачам пользователя, не находящимся в корзине.
+// match — выражение FTS5, подготовленное fts.Query. Результаты упорядочены по релевантности.
+func (r *taskRepository) Search(userID uint, match string, params models.TaskSearchParams) ([]models.TaskSearchHit, int64, error) {
+	var hits []models.TaskSearchHit
+	var total int64
+
+	query := r.db.Table("tasks_fts").
+		Joins("JOIN tasks ON tasks.id = tasks_fts.rowid").
+		Where("tasks_fts MATCH ?", match).
+		Where("tasks.user_id = ? AND tasks.deleted_at IS NULL AND tasks.archived_at IS NULL", userID)
+
+	if params.ProjectID != nil {
+		query = query.Where("tasks.project_id = ?", *params.ProjectID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// bm25 возвращает тем меньшее значение, чем выше релевантность
+	query = query.Select(
+		"tasks.id AS task_id, -bm25(tasks_fts, "+searchWeights+") AS score, "+
+			"highlight(tasks_fts, 0, ?, ?) AS title, "+
+			"snippet(tasks_fts, 1, ?, ?, '…', 24) AS description",
+		models.SearchHighlightStart, models.SearchHighlightEnd,
+		models.SearchHighlightStart, models.SearchHighlightEnd,
+	).Order("score DESC, tasks.id DESC")
 
-	// Пагинация
 	if params.Page > 0 && params.Limit > 0 {
 		offset := (params.Page - 1) * params.Limit
 		query = query.Offset(offset).Limit(params.Limit)
 	}
 
-	err := query.Find(&tasks).Error
-	return tasks, total, err
+	err := query.Scan(&hits).Error
+	return hits, total, err
+}
+
+// GetByIDs получает задачи по списку ID
+func (r *taskRepository) GetByIDs(ids []uint) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Preload("User").Preload("CustomFieldValues.Field").Where("id IN ?", ids).Find(&tasks).Error
+	return tasks, err
 }
 
-// Update обновляет задачу
+// Update обновляет задачу, если ее версия не изменилась с момента чтения,
+// и увеличивает версию. Проверка и запись выполняются одним UPDATE.
 func (r *taskRepository) Update(task *models.Task) error {
-	return r.db.Save(task).Error
+	version := task.Version
+	task.Version++
+
+	result := r.db.Model(task).
+		Where("version = ?", version).
+		Select("*").
+		Omit("User", "CustomFieldValues", "CreatedAt").
+		Updates(task)
+	if result.Error != nil {
+		task.Version = version
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		task.Version = version
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// Delete перемещает задачу в корзину (мягкое удаление), если ее версия не изменилась,
+// и увеличивает версию, чтобы восстановленная задача не совпала с прежним ETag
+func (r *taskRepository) Delete(id uint, version int) error {
+	result := r.db.Model(&models.Task{}).
+		Where("id = ? AND version = ?", id, version).
+		UpdateColumns(map[string]interface{}{
+			"deleted_at": time.Now().UTC(),
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// GetByIDUnscoped получает задачу по ID, включая задачи в корзине
+func (r *taskRepository) GetByIDUnscoped(id uint) (*models.Task, error) {
+	var task models.Task
+	err := r.db.Unscoped().Preload("User").Preload("CustomFieldValues.Field").First(&task, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// GetTrash получает задачи пользователя в корзине, начиная с последних удаленных
+func (r *taskRepository) GetTrash(userID uint, params models.TaskQueryParams) ([]models.Task, int64, error) {
+	var tasks []models.Task
+	var total int64
+
+	query := r.db.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+
+	if params.ProjectID != nil {
+		query = query.Where("project_id = ?", *params.ProjectID)
+	}
+
+	query.Model(&models.Task{}).Count(&total)
+
+	if params.Page > 0 && params.Limit > 0 {
+		offset := (params.Page - 1) * params.Limit
+		query = query.Offset(offset).Limit(params.Limit)
+	}
+
+	err := query.Order("deleted_at DESC").Order("id DESC").
+		Preload("CustomFieldValues.Field").
+		Find(&tasks).Error
+	return tasks, total, err
+}
+
+// GetTrashIDs получает ID всех задач пользователя в корзине
+func (r *taskRepository) GetTrashIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Unscoped().Model(&models.Task{}).
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetTrashedBefore получает до limit ID задач, удаленных в корзину раньше before
+func (r *taskRepository) GetTrashedBefore(before time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Unscoped().Model(&models.Task{}).
+		Where("deleted_at < ?", before.UTC()).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Restore возвращает задачу из корзины
+func (r *taskRepository) Restore(id uint) error {
+	return r.db.Unscoped().Model(&models.Task{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		}).Error
+}
+
+// SetArchived помещает задачу в архив (archivedAt != nil) или возвращает из него,
+// если ее версия не изменилась, и увеличивает версию
+func (r *taskRepository) SetArchived(id uint, version int, archivedAt *time.Time) error {
+	result := r.db.Model(&models.Task{}).
+		Where("id = ? AND version = ?", id, version).
+		UpdateColumns(map[string]interface{}{
+			"archived_at": archivedAt,
+			"version":     gorm.Expr("version + 1"),
+			"updated_at":  time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// GetCompletedBefore получает задачи не из архива, завершенные раньше before,
+// всех пользователей (userID == nil) или одного пользователя, при необходимости в проекте.
+// Задачи выбираются порциями по limit в порядке ID, начиная после afterID.
+func (r *taskRepository) GetCompletedBefore(userID *uint, projectID *uint, before time.Time, afterID uint, limit int) ([]models.Task, error) {
+	query := r.db.Select("id", "user_id", "version").
+		Where("completed_at < ? AND archived_at IS NULL AND id > ?", before.UTC(), afterID)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	}
+
+	var tasks []models.Task
+	err := query.Order("id ASC").Limit(limit).Find(&tasks).Error
+	return tasks, err
+}
+
+// GetOpenWithSLA получает до limit незавершенных задач не из архива без отметки о нарушении
+// SLA с ID больше afterID, срок которых мог пройти к моменту now. Срок по рабочему
+// времени не раньше, чем created_at плюс срок политики, поэтому выбираются только задачи,
+// созданные раньше now хотя бы на срок одной из политик владельца.
+func (r *taskRepository) GetOpenWithSLA(now time.Time, afterID uint, limit int) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Select("id", "user_id", "project_id", "priority", "created_at").
+		Where("completed_at IS NULL AND archived_at IS NULL AND sla_breached_at IS NULL AND id > ?", afterID).
+		Where("EXISTS (SELECT 1 FROM sla_policies WHERE sla_policies.user_id = tasks.user_id AND "+
+			"julianday(substr(tasks.created_at, 1, 19)) <= julianday(?) - sla_policies.resolution_hours / 24.0)",
+			now.UTC().Format("2006-01-02 15:04:05")).
+		Order("id ASC").
+		Limit(limit).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// SetSLABreached отмечает нарушение SLA задачи, если ее версия не изменилась, и увеличивает версию
+func (r *taskRepository) SetSLABreached(id uint, version int, breachedAt time.Time) error {
+	result := r.db.Model(&models.Task{}).
+		Where("id = ? AND version = ?", id, version).
+		UpdateColumns(map[string]interface{}{
+			"sla_breached_at": breachedAt,
+			"version":         gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
 }
 
-// Delete удаляет задачу
-func (r *taskRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Task{}, id).Error
-} 
\ No newline at end of file
+// IncrementVersion увеличивает версию задачи при изменении связанных с ней данных,
+// которые входят в ответ с задачей (например, чек-листа)
+func (r *taskRepository) IncrementVersion(id uint) error {
+	return r.db.Model(&models.Task{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": time.Now().UTC(),
+		}).Error
+}
+
+// DetachChildren делает подзадачи задачи parentID, включая подзадачи в корзине,
+// самостоятельными задачами и увеличивает их версии
+func (r *taskRepository) DetachChildren(parentID uint) error {
+	return r.db.Unscoped().Model(&models.Task{}).
+		Where("parent_id = ?", parentID).
+		UpdateColumns(map[string]interface{}{
+			"parent_id":  nil,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": time.Now().UTC(),
+		}).Error
+}
+
+// Purge удаляет задачу безвозвратно
+func (r *taskRepository) Purge(id uint) error {
+	return r.db.Unscoped().Delete(&models.Task{}, id).Error
+}
+
+// GetBoard получает задачи пользователя в проекте (или вне проектов) в порядке их позиций на доске
+func (r *taskRepository) GetBoard(userID uint, projectID *uint) ([]models.Task, error) {
+	var tasks []models.Task
+	err := boardScope(r.db, projectID).
+		Where("user_id = ? AND archived_at IS NULL", userID).
+		Order("position ASC").
+		Order("id ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// GetForCalendar получает задачи пользователя вне архива для календаря в порядке начала.
+// Метка сравнивается без учета регистра латинских букв.
+func (r *taskRepository) GetForCalendar(userID uint, filter models.CalendarTaskFilter) ([]models.Task, error) {
+	var tasks []models.Task
+	query := r.db.Where("user_id = ? AND archived_at IS NULL", userID)
+	if filter.ProjectID != nil {
+		query = query.Where("project_id = ?", *filter.ProjectID)
+	}
+	if filter.Tag != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM json_each(tasks.tags) WHERE lower(json_each.value) = lower(?))", filter.Tag)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	err := query.
+		Order("start_date ASC").
+		Order("id ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// GetForTimeline получает задачи пользователя вне архива, пересекающие период,
+// в порядке начала
+func (r *taskRepository) GetForTimeline(userID uint, filter models.TimelineFilter) ([]models.Task, error) {
+	var tasks []models.Task
+	query := r.db.Where("user_id = ? AND archived_at IS NULL", userID).
+		Where("start_date < ? AND end_date >= ?", filter.To.AddDate(0, 0, 1), filter.From)
+	if filter.ProjectID != nil {
+		query = query.Where("project_id = ?", *filter.ProjectID)
+	}
+	err := query.
+		Preload("CustomFieldValues.Field").
+		Order("start_date ASC").
+		Order("id ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// boardScope ограничивает запрос доской проекта projectID или, если он не задан,
+// доской задач вне проектов
+func boardScope(db *gorm.DB, projectID *uint) *gorm.DB {
+	if projectID != nil {
+		return db.Where("project_id = ?", *projectID)
+	}
+	return db.Where("project_id IS NULL")
+}
+
+// GetLastPosition получает наибольшую позицию в колонке доски пользователя
+func (r *taskRepository) GetLastPosition(userID uint, projectID *uint, status models.TaskStatus) (string, error) {
+	var position string
+	err := boardScope(r.db.Model(&models.Task{}), projectID).
+		Where("user_id = ? AND status = ?", userID, status).
+		Select("COALESCE(MAX(position), '')").
+		Scan(&position).Error
+	return position, err
+}
+
+// GetNextPosition получает ближайшую позицию в колонке, следующую за position
+func (r *taskRepository) GetNextPosition(userID uint, projectID *uint, status models.TaskStatus, position string) (string, error) {
+	var next string
+	err := boardScope(r.db.Model(&models.Task{}), projectID).
+		Where("user_id = ? AND status = ? AND position > ?", userID, status, position).
+		Select("COALESCE(MIN(position), '')").
+		Scan(&next).Error
+	return next, err
+}
+
+// GetPrevPosition получает ближайшую позицию в колонке, предшествующую position
+func (r *taskRepository) GetPrevPosition(userID uint, projectID *uint, status models.TaskStatus, position string) (string, error) {
+	var prev string
+	err := boardScope(r.db.Model(&models.Task{}), projectID).
+		Where("user_id = ? AND status = ? AND position < ?", userID, status, position).
+		Select("COALESCE(MAX(position), '')").
+		Scan(&prev).Error
+	return prev, err
+}
+
+// UpdatePosition меняет статус и позицию задачи, затрагивая только одну строку.
+// Как и Update, запись выполняется только при неизменной версии задачи.
+func (r *taskRepository) UpdatePosition(id uint, version int, status models.TaskStatus, position string, completedAt *time.Time) error {
+	result := r.db.Model(&models.Task{}).
+		Where("id = ? AND version = ?", id, version).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"position":     position,
+			"completed_at": completedAt,
+			"version":      gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
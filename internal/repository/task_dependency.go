@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TaskDependencyRepository интерфейс для работы с зависимостями задач
+type TaskDependencyRepository interface {
+	WithTx(tx *gorm.DB) TaskDependencyRepository
+	Create(dependency *models.TaskDependency) error
+	Get(taskID, dependsOnID uint) (*models.TaskDependency, error)
+	GetByUserID(userID uint) ([]models.TaskDependency, error)
+	GetByTaskID(taskID uint) ([]models.TaskDependency, error)
+	GetDependents(taskID uint) ([]models.TaskDependency, error)
+	GetByTaskIDs(taskIDs []uint) ([]models.TaskDependency, error)
+	Delete(taskID, dependsOnID uint) (int64, error)
+	DeleteByTaskID(taskID uint) error
+}
+
+// taskDependencyRepository реализация репозитория зависимостей
+type taskDependencyRepository struct {
+	db *gorm.DB
+}
+
+// NewTaskDependencyRepository создает новый репозиторий зависимостей
+func NewTaskDependencyRepository(db *gorm.DB) TaskDependencyRepository {
+	return &taskDependencyRepository{
+		db: db,
+	}
+}
+
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *taskDependencyRepository) WithTx(tx *gorm.DB) TaskDependencyRepository {
+	return &taskDependencyRepository{
+		db: tx,
+	}
+}
+
+// Create создает зависимость
+func (r *taskDependencyRepository) Create(dependency *models.TaskDependency) error {
+	return r.db.Create(dependency).Error
+}
+
+// Get получает зависимость задачи taskID от задачи dependsOnID
+func (r *taskDependencyRepository) Get(taskID, dependsOnID uint) (*models.TaskDependency, error) {
+	var dependency models.TaskDependency
+	err := r.db.Where("task_id = ? AND depends_on_id = ?", taskID, dependsOnID).First(&dependency).Error
+	if err != nil {
+		return nil, err
+	}
+	return &dependency, nil
+}
+
+// GetByUserID получает все зависимости между задачами пользователя
+func (r *taskDependencyRepository) GetByUserID(userID uint) ([]models.TaskDependency, error) {
+	var dependencies []models.TaskDependency
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&dependencies).Error
+	return dependencies, err
+}
+
+// GetByTaskID получает зависимости задачи от других задач
+func (r *taskDependencyRepository) GetByTaskID(taskID uint) ([]models.TaskDependency, error) {
+	var dependencies []models.TaskDependency
+	err := r.db.Where("task_id = ?", taskID).Order("id ASC").Find(&dependencies).Error
+	return dependencies, err
+}
+
+// GetDependents получает зависимости других задач от задачи
+func (r *taskDependencyRepository) GetDependents(taskID uint) ([]models.TaskDependency, error) {
+	var dependencies []models.TaskDependency
+	err := r.db.Where("depends_on_id = ?", taskID).Order("id ASC").Find(&dependencies).Error
+	return dependencies, err
+}
+
+// GetByTaskIDs получает зависимости перечисленных задач от других задач
+func (r *taskDependencyRepository) GetByTaskIDs(taskIDs []uint) ([]models.TaskDependency, error) {
+	var dependencies []models.TaskDependency
+	if len(taskIDs) == 0 {
+		return dependencies, nil
+	}
+	err := r.db.Where("task_id IN ?", taskIDs).Order("id ASC").Find(&dependencies).Error
+	return dependencies, err
+}
+
+// Delete удаляет зависимость и возвращает число удаленных записей
+func (r *taskDependencyRepository) Delete(taskID, dependsOnID uint) (int64, error) {
+	result := r.db.Where("task_id = ? AND depends_on_id = ?", taskID, dependsOnID).Delete(&models.TaskDependency{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteByTaskID удаляет зависимости задачи в обе стороны
+func (r *taskDependencyRepository) DeleteByTaskID(taskID uint) error {
+	return r.db.Where("task_id = ? OR depends_on_id = ?", taskID, taskID).Delete(&models.TaskDependency{}).Error
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TaskRevisionRepository интерфейс для работы с историей изменений задач
+type TaskRevisionRepository interface {
+	WithTx(tx *gorm.DB) TaskRevisionRepository
+	Create(revision *models.TaskRevision) error
+	GetByID(id uint) (*models.TaskRevision, error)
+	GetByTaskID(taskID uint, params models.TaskHistoryParams) ([]models.TaskRevision, int64, error)
+	DeleteByTaskID(taskID uint) error
+}
+
+// taskRevisionRepository реализация репозитория истории задач
+type taskRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewTaskRevisionRepository создает новый репозиторий истории задач
+func NewTaskRevisionRepository(db *gorm.DB) TaskRevisionRepository {
+	return &taskRevisionRepository{
+		db: db,
+	}
+}
+
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *taskRevisionRepository) WithTx(tx *gorm.DB) TaskRevisionRepository {
+	return &taskRevisionRepository{
+		db: tx,
+	}
+}
+
+// Create создает запись истории
+func (r *taskRevisionRepository) Create(revision *models.TaskRevision) error {
+	return r.db.Omit("User").Create(revision).Error
+}
+
+// GetByID получает запись истории по ID
+func (r *taskRevisionRepository) GetByID(id uint) (*models.TaskRevision, error) {
+	var revision models.TaskRevision
+	err := r.db.Preload("User").First(&revision, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetByTaskID получает историю задачи, начиная с последнего изменения
+func (r *taskRevisionRepository) GetByTaskID(taskID uint, params models.TaskHistoryParams) ([]models.TaskRevision, int64, error) {
+	var revisions []models.TaskRevision
+	var total int64
+
+	query := r.db.Where("task_id = ?", taskID)
+
+	query.Model(&models.TaskRevision{}).Count(&total)
+
+	if params.Page > 0 && params.Limit > 0 {
+		offset := (params.Page - 1) * params.Limit
+		query = query.Offset(offset).Limit(params.Limit)
+	}
+
+	err := query.Preload("User").Order("id DESC").Find(&revisions).Error
+	return revisions, total, err
+}
+
+// DeleteByTaskID удаляет историю задачи
+func (r *taskRevisionRepository) DeleteByTaskID(taskID uint) error {
+	return r.db.Where("task_id = ?", taskID).Delete(&models.TaskRevision{}).Error
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"strings"
+
+	"golang_server/internal/models"
+	"golang_server/pkg/fts"
+
+	"gorm.io/gorm/clause"
+)
+
+// sortKey представляет одно выражение сортировки списка задач
+type sortKey struct {
+	expr string
+	vars []interface{}
+	desc bool
+	// numeric ключ сравнивается как число, иначе как текст
+	numeric bool
+	// nullable ключ может быть NULL: SQLite ставит NULL перед остальными значениями при ASC
+	nullable bool
+}
+
+// taskSortKeys возвращает ключи сортировки списка задач. Последним ключом всегда идет ID,
+// поэтому порядок однозначен и по нему можно продолжать выборку с курсора.
+func taskSortKeys(params models.TaskQueryParams) []sortKey {
+	var keys []sortKey
+	last := true
+	for _, sort := range params.SortFields {
+		switch sort.Field {
+		case "created_at", "updated_at", "start_date", "end_date", "status", "title", "position":
+			keys = append(keys, sortKey{expr: sort.Field, desc: sort.Desc})
+		case "completed_at":
+			keys = append(keys, sortKey{expr: sort.Field, desc: sort.Desc, nullable: true})
+		case "priority":
+			keys = append(keys, sortKey{expr: priorityOrder, desc: sort.Desc, numeric: true})
+		default:
+			if sort.CustomField == nil {
+				continue
+			}
+			// Задачи без значения поля идут в конце при любом направлении сортировки
+			column := sort.CustomField.Column
+			value := "(SELECT MIN(" + column + ") FROM custom_field_values WHERE custom_field_values.task_id = tasks.id AND custom_field_values.field_id IN ?)"
+			keys = append(keys,
+				sortKey{expr: value + " IS NULL", vars: []interface{}{sort.CustomField.FieldIDs}, numeric: true},
+				sortKey{
+					expr:     value,
+					vars:     []interface{}{sort.CustomField.FieldIDs},
+					desc:     sort.Desc,
+					numeric:  column == "number_value" || column == "user_value",
+					nullable: true,
+				},
+			)
+		}
+		last = sort.Desc
+	}
+
+	if len(keys) == 0 {
+		// Без явной сортировки результаты поиска упорядочиваются по релевантности
+		if match := fts.Query(params.Search); match != "" {
+			keys = append(keys, sortKey{
+				expr:    "(SELECT bm25(tasks_fts, " + searchWeights + ") FROM tasks_fts WHERE tasks_fts MATCH ? AND tasks_fts.rowid = tasks.id)",
+				vars:    []interface{}{match},
+				numeric: true,
+			})
+		}
+		keys = append(keys, sortKey{expr: "created_at", desc: true})
+	}
+
+	return append(keys, sortKey{expr: "id", desc: last, numeric: true})
+}
+
+// reverseSortKeys меняет направление всех ключей для выборки задач перед курсором
+func reverseSortKeys(keys []sortKey) []sortKey {
+	reversed := make([]sortKey, len(keys))
+	for i, key := range keys {
+		key.desc = !key.desc
+		reversed[i] = key
+	}
+	return reversed
+}
+
+// orderClause строит сортировку по ключам. Все выражения задаются одним OrderBy:
+// GORM не объединяет несколько выражений с параметрами.
+func orderClause(keys []sortKey) clause.OrderBy {
+	orders := make([]string, len(keys))
+	var vars []interface{}
+	for i, key := range keys {
+		direction := " ASC"
+		if key.desc {
+			direction = " DESC"
+		}
+		orders[i] = key.expr + direction
+		vars = append(vars, key.vars...)
+	}
+	return clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(orders, ", "), Vars: vars}}
+}
+
+// keysetCondition строит условие "задача идет после граничной" для значений ключей values:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... с учетом направления и NULL
+func keysetCondition(keys []sortKey, values []interface{}) (string, []interface{}) {
+	var terms []string
+	var vars []interface{}
+
+	for i, key := range keys {
+		var parts []string
+		var termVars []interface{}
+
+		for j := 0; j < i; j++ {
+			if values[j] == nil {
+				parts = append(parts, keys[j].expr+" IS NULL")
+				termVars = append(termVars, keys[j].vars...)
+			} else {
+				parts = append(parts, keys[j].expr+" = ?")
+				termVars = append(termVars, keys[j].vars...)
+				termVars = append(termVars, values[j])
+			}
+		}
+
+		switch {
+		case values[i] == nil && key.desc:
+			// При DESC значения NULL идут последними: после них ничего нет
+			continue
+		case values[i] == nil:
+			parts = append(parts, key.expr+" IS NOT NULL")
+			termVars = append(termVars, key.vars...)
+		case key.desc && key.nullable:
+			parts = append(parts, "("+key.expr+" < ? OR "+key.expr+" IS NULL)")
+			termVars = append(termVars, key.vars...)
+			termVars = append(termVars, values[i])
+			termVars = append(termVars, key.vars...)
+		case key.desc:
+			parts = append(parts, key.expr+" < ?")
+			termVars = append(termVars, key.vars...)
+			termVars = append(termVars, values[i])
+		default:
+			parts = append(parts, key.expr+" > ?")
+			termVars = append(termVars, key.vars...)
+			termVars = append(termVars, values[i])
+		}
+
+		terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+		vars = append(vars, termVars...)
+	}
+
+	if len(terms) == 0 {
+		return "1 = 0", nil
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", vars
+}
+
+// GetSortValues возвращает значения ключей сортировки задачи для построения курсора.
+// Даты и строки возвращаются в том виде, в котором хранятся, чтобы сравнение
+// с ними в keysetCondition совпадало с сортировкой.
+func (r *taskRepository) GetSortValues(taskID uint, params models.TaskQueryParams) ([]interface{}, error) {
+	keys := taskSortKeys(params)
+
+	columns := make([]string, len(keys))
+	var vars []interface{}
+	for i, key := range keys {
+		cast := "TEXT"
+		if key.numeric {
+			cast = "REAL"
+		}
+		columns[i] = "CAST(" + key.expr + " AS " + cast + ")"
+		vars = append(vars, key.vars...)
+	}
+	vars = append(vars, taskID)
+
+	rows, err := r.db.Raw("SELECT "+strings.Join(columns, ", ")+" FROM tasks WHERE id = ?", vars...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]interface{}, len(keys))
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	pointers := make([]interface{}, len(keys))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, err
+	}
+
+	for i, value := range values {
+		if bytes, ok := value.([]byte); ok {
+			values[i] = string(bytes)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TaskTemplateRepository интерфейс для работы с шаблонами задач
+type TaskTemplateRepository interface {
+	Create(template *models.TaskTemplate) error
+	GetByID(id uint) (*models.TaskTemplate, error)
+	GetByUserID(userID uint, projectID *uint) ([]models.TaskTemplate, error)
+	Update(template *models.TaskTemplate) error
+	Delete(id uint) error
+}
+
+// taskTemplateRepository реализация репозитория шаблонов задач
+type taskTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewTaskTemplateRepository создает новый репозиторий шаблонов задач
+func NewTaskTemplateRepository(db *gorm.DB) TaskTemplateRepository {
+	return &taskTemplateRepository{
+		db: db,
+	}
+}
+
+// Create создает шаблон
+func (r *taskTemplateRepository) Create(template *models.TaskTemplate) error {
+	return r.db.Create(template).Error
+}
+
+// GetByID получает шаблон по ID
+func (r *taskTemplateRepository) GetByID(id uint) (*models.TaskTemplate, error) {
+	var template models.TaskTemplate
+	err := r.db.First(&template, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetByUserID получает шаблоны пользователя, при projectID — только шаблоны проекта
+func (r *taskTemplateRepository) GetByUserID(userID uint, projectID *uint) ([]models.TaskTemplate, error) {
+	var templates []models.TaskTemplate
+
+	query := r.db.Where("user_id = ?", userID)
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	}
+
+	err := query.Order("name ASC, id ASC").Find(&templates).Error
+	return templates, err
+}
+
+// Update обновляет шаблон
+func (r *taskTemplateRepository) Update(template *models.TaskTemplate) error {
+	return r.db.Save(template).Error
+}
+
+// Delete удаляет шаблон
+func (r *taskTemplateRepository) Delete(id uint) error {
+	return r.db.Delete(&models.TaskTemplate{}, id).Error
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TaskWatcherRepository интерфейс для работы с подписчиками задач
+type TaskWatcherRepository interface {
+	WithTx(tx *gorm.DB) TaskWatcherRepository
+	AddIfMissing(watchers []models.TaskWatcher) error
+	Get(taskID, userID uint) (*models.TaskWatcher, error)
+	GetWatching(taskID uint) ([]models.TaskWatcher, error)
+	SetWatching(taskID, userID uint, watching bool, reason models.WatchReason) error
+	DeleteByTaskID(taskID uint) error
+}
+
+// taskWatcherRepository реализация репозитория подписчиков
+type taskWatcherRepository struct {
+	db *gorm.DB
+}
+
+// NewTaskWatcherRepository создает новый репозиторий подписчиков
+func NewTaskWatcherRepository(db *gorm.DB) TaskWatcherRepository {
+	return &taskWatcherRepository{
+		db: db,
+	}
+}
+
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *taskWatcherRepository) WithTx(tx *gorm.DB) TaskWatcherRepository {
+	return &taskWatcherRepository{
+		db: tx,
+	}
+}
+
+// AddIfMissing добавляет подписки, которых еще нет; существующие записи,
+// в том числе отписки, не меняются
+func (r *taskWatcherRepository) AddIfMissing(watchers []models.TaskWatcher) error {
+	if len(watchers) == 0 {
+		return nil
+	}
+	return r.db.Omit("User").
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "task_id"}, {Name: "user_id"}}, DoNothing: true}).
+		Create(&watchers).Error
+}
+
+// Get получает подписку пользователя на задачу
+func (r *taskWatcherRepository) Get(taskID, userID uint) (*models.TaskWatcher, error) {
+	var watcher models.TaskWatcher
+	err := r.db.Where("task_id = ? AND user_id = ?", taskID, userID).First(&watcher).Error
+	if err != nil {
+		return nil, err
+	}
+	return &watcher, nil
+}
+
+// GetWatching получает действующие подписки на задачу
+func (r *taskWatcherRepository) GetWatching(taskID uint) ([]models.TaskWatcher, error) {
+	var watchers []models.TaskWatcher
+	err := r.db.Preload("User").
+		Where("task_id = ? AND watching = ?", taskID, true).
+		Order("id ASC").
+		Find(&watchers).Error
+	return watchers, err
+}
+
+// SetWatching подписывает пользователя на задачу или отписывает от нее.
+// Причина записывается только для новой подписки.
+func (r *taskWatcherRepository) SetWatching(taskID, userID uint, watching bool, reason models.WatchReason) error {
+	watcher := models.TaskWatcher{
+		TaskID:   taskID,
+		UserID:   userID,
+		Reason:   reason,
+		Watching: watching,
+	}
+	return r.db.Omit("User").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "task_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"watching", "updated_at"}),
+		}).
+		Create(&watcher).Error
+}
+
+// DeleteByTaskID удаляет подписки на задачу
+func (r *taskWatcherRepository) DeleteByTaskID(taskID uint) error {
+	return r.db.Where("task_id = ?", taskID).Delete(&models.TaskWatcher{}).Error
+}
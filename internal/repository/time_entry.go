@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TimeEntryRepository интерфейс для работы с записями учета времени
+type TimeEntryRepository interface {
+	WithTx(tx *gorm.DB) TimeEntryRepository
+	Create(entry *models.TimeEntry) error
+	GetByID(id uint) (*models.TimeEntry, error)
+	GetByTaskID(taskID uint) ([]models.TimeEntry, error)
+	GetRunning(userID uint) (*models.TimeEntry, error)
+	Update(entry *models.TimeEntry) error
+	Delete(id uint) error
+	DeleteByTaskID(taskID uint) error
+	GetTotals(taskIDs []uint, now time.Time) (map[uint]int64, error)
+	Report(userID uint, groupBy []string, params models.TimeReportParams) ([]models.TimeReportRow, error)
+}
+
+// timeEntryRepository реализация репозитория записей времени
+type timeEntryRepository struct {
+	db *gorm.DB
+}
+
+// NewTimeEntryRepository создает новый репозиторий записей времени
+func NewTimeEntryRepository(db *gorm.DB) TimeEntryRepository {
+	return &timeEntryRepository{
+		db: db,
+	}
+}
+
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *timeEntryRepository) WithTx(tx *gorm.DB) TimeEntryRepository {
+	return &timeEntryRepository{
+		db: tx,
+	}
+}
+
+// Create создает новую запись времени
+func (r *timeEntryRepository) Create(entry *models.TimeEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// GetByID получает запись времени по ID
+func (r *timeEntryRepository) GetByID(id uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := r.db.First(&entry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetByTaskID получает записи времени задачи, начиная с последней
+func (r *timeEntryRepository) GetByTaskID(taskID uint) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	err := r.db.Where("task_id = ?", taskID).Order("started_at DESC").Find(&entries).Error
+	return entries, err
+}
+
+// GetRunning получает запущенный таймер пользователя
+func (r *timeEntryRepository) GetRunning(userID uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := r.db.Where("user_id = ? AND ended_at IS NULL", userID).First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Update обновляет запись времени
+func (r *timeEntryRepository) Update(entry *models.TimeEntry) error {
+	return r.db.Save(entry).Error
+}
+
+// Delete удаляет запись времени
+func (r *timeEntryRepository) Delete(id uint) error {
+	return r.db.Delete(&models.TimeEntry{}, id).Error
+}
+
+// DeleteByTaskID удаляет все записи времени задачи
+func (r *timeEntryRepository) DeleteByTaskID(taskID uint) error {
+	return r.db.Where("task_id = ?", taskID).Delete(&models.TimeEntry{}).Error
+}
+
+// GetTotals считает суммарное время по задачам; запущенные таймеры учитываются до now
+func (r *timeEntryRepository) GetTotals(taskIDs []uint, now time.Time) (map[uint]int64, error) {
+	totals := make(map[uint]int64, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return totals, nil
+	}
+
+	var rows []struct {
+		TaskID uint
+		Total  int64
+	}
+	err := r.db.Model(&models.TimeEntry{}).
+		Select("task_id, SUM(duration_seconds) AS total").
+		Where("task_id IN ? AND ended_at IS NOT NULL", taskIDs).
+		Group("task_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		totals[row.TaskID] = row.Total
+	}
+
+	var running []models.TimeEntry
+	err = r.db.Where("task_id IN ? AND ended_at IS NULL", taskIDs).Find(&running).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range running {
+		totals[entry.TaskID] += int64(now.Sub(entry.StartedAt).Seconds())
+	}
+
+	return totals, nil
+}
+
+// Report агрегирует завершенные записи времени по задачам пользователя.
+// groupBy содержит проверенные измерения: task, project, user.
+func (r *timeEntryRepository) Report(userID uint, groupBy []string, params models.TimeReportParams) ([]models.TimeReportRow, error) {
+	rows := []models.TimeReportRow{}
+
+	columns := []string{"COUNT(*) AS entries", "SUM(time_entries.duration_seconds) AS duration"}
+	var groups []string
+	for _, dimension := range groupBy {
+		switch dimension {
+		case "task":
+			columns = append(columns, "time_entries.task_id AS task_id", "tasks.title AS task_title")
+			groups = append(groups, "time_entries.task_id", "tasks.title")
+		case "project":
+			columns = append(columns, "tasks.project_id AS project_id")
+			groups = append(groups, "tasks.project_id")
+		case "user":
+			columns = append(columns, "time_entries.user_id AS user_id")
+			groups = append(groups, "time_entries.user_id")
+		}
+	}
+
+	query := r.db.Table("time_entries").
+		Select(strings.Join(columns, ", ")).
+		Joins("JOIN tasks ON tasks.id = time_entries.task_id").
+		Where("tasks.user_id = ? AND tasks.deleted_at IS NULL AND time_entries.ended_at IS NOT NULL", userID)
+
+	if params.From != nil {
+		query = query.Where("time_entries.started_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where("time_entries.started_at < ?", *params.To)
+	}
+	if params.ProjectID != nil {
+		query = query.Where("tasks.project_id = ?", *params.ProjectID)
+	}
+	if params.TaskID != nil {
+		query = query.Where("time_entries.task_id = ?", *params.TaskID)
+	}
+	if len(groups) > 0 {
+		query = query.Group(strings.Join(groups, ", "))
+	}
+
+	err := query.Order("duration DESC").Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// Transactor интерфейс для выполнения нескольких операций репозиториев в одной транзакции.
+// Репозитории привязываются к транзакции через свой метод WithTx.
+type Transactor interface {
+	WithTx(tx *gorm.DB) Transactor
+	Transaction(fn func(tx *gorm.DB) error) error
+}
+
+// transactor реализация Transactor поверх GORM
+type transactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor создает новый Transactor
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{
+		db: db,
+	}
+}
+
+// WithTx возвращает Transactor, который выполняет вложенные транзакции
+// внутри tx (через точки сохранения)
+func (t *transactor) WithTx(tx *gorm.DB) Transactor {
+	return &transactor{
+		db: tx,
+	}
+}
+
+// Transaction выполняет fn в транзакции; ошибка fn откатывает все изменения
+func (t *transactor) Transaction(fn func(tx *gorm.DB) error) error {
+	return t.db.Transaction(fn)
+}
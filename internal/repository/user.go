@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"strings"
+
 	"golang_server/internal/models"
 	
 	"gorm.io/gorm"
@@ -8,9 +10,11 @@ import (
 
 // UserRepository интерфейс для работы с пользователями
 type UserRepository interface {
+	WithTx(tx *gorm.DB) UserRepository
 	Create(user *models.User) error
 	GetByEmail(email string) (*models.User, error)
 	GetByID(id uint) (*models.User, error)
+	GetByUsernames(usernames []string) ([]models.User, error)
 	Update(user *models.User) error
 	Delete(id uint) error
 }
@@ -27,6 +31,13 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	}
 }
 
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *userRepository) WithTx(tx *gorm.DB) UserRepository {
+	return &userRepository{
+		db: tx,
+	}
+}
+
 // Create создает нового пользователя
 func (r *userRepository) Create(user *models.User) error {
 	return r.db.Create(user).Error
@@ -52,6 +63,20 @@ func (r *userRepository) GetByID(id uint) (*models.User, error) {
 	return &user, nil
 }
 
+// GetByUsernames получает пользователей по именам без учета регистра
+func (r *userRepository) GetByUsernames(usernames []string) ([]models.User, error) {
+	var users []models.User
+	if len(usernames) == 0 {
+		return users, nil
+	}
+	lowered := make([]string, len(usernames))
+	for i, username := range usernames {
+		lowered[i] = strings.ToLower(username)
+	}
+	err := r.db.Where("LOWER(username) IN ?", lowered).Find(&users).Error
+	return users, err
+}
+
 // Update обновляет пользователя
 func (r *userRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// WorkflowRepository интерфейс для работы с рабочими процессами
+type WorkflowRepository interface {
+	WithTx(tx *gorm.DB) WorkflowRepository
+	Create(workflow *models.Workflow) error
+	GetByID(id uint) (*models.Workflow, error)
+	GetByUserID(userID uint) ([]models.Workflow, error)
+	GetDefault(userID uint) (*models.Workflow, error)
+	Update(workflow *models.Workflow, replaceStatuses bool) error
+	SetDefault(userID, id uint) error
+	Delete(id uint) error
+	CountProjects(id uint) (int64, error)
+	CountTasksInStatuses(id uint, statuses []models.TaskStatus) (int64, error)
+}
+
+// workflowRepository реализация репозитория рабочих процессов
+type workflowRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkflowRepository создает новый репозиторий рабочих процессов
+func NewWorkflowRepository(db *gorm.DB) WorkflowRepository {
+	return &workflowRepository{
+		db: db,
+	}
+}
+
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *workflowRepository) WithTx(tx *gorm.DB) WorkflowRepository {
+	return &workflowRepository{
+		db: tx,
+	}
+}
+
+// withRelations подгружает статусы в порядке колонок и переходы
+func (r *workflowRepository) withRelations() *gorm.DB {
+	return r.db.
+		Preload("Statuses", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Transitions")
+}
+
+// Create создает новый рабочий процесс вместе со статусами и переходами
+func (r *workflowRepository) Create(workflow *models.Workflow) error {
+	return r.db.Create(workflow).Error
+}
+
+// GetByID получает рабочий процесс по ID
+func (r *workflowRepository) GetByID(id uint) (*models.Workflow, error) {
+	var workflow models.Workflow
+	err := r.withRelations().First(&workflow, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+// GetByUserID получает рабочие процессы пользователя
+func (r *workflowRepository) GetByUserID(userID uint) ([]models.Workflow, error) {
+	var workflows []models.Workflow
+	err := r.withRelations().
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&workflows).Error
+	return workflows, err
+}
+
+// GetDefault получает рабочий процесс пользователя по умолчанию
+func (r *workflowRepository) GetDefault(userID uint) (*models.Workflow, error) {
+	var workflow models.Workflow
+	err := r.withRelations().
+		Where("user_id = ? AND is_default = ?", userID, true).
+		First(&workflow).Error
+	if err != nil {
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+// Update обновляет рабочий процесс; при replaceStatuses статусы и переходы заменяются целиком
+func (r *workflowRepository) Update(workflow *models.Workflow, replaceStatuses bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(workflow).
+			Select("name", "is_default", "require_checklist").
+			Updates(map[string]interface{}{
+				"name":              workflow.Name,
+				"is_default":        workflow.IsDefault,
+				"require_checklist": workflow.RequireChecklist,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		if !replaceStatuses {
+			return nil
+		}
+
+		if err := tx.Where("workflow_id = ?", workflow.ID).Delete(&models.WorkflowStatus{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workflow_id = ?", workflow.ID).Delete(&models.WorkflowTransition{}).Error; err != nil {
+			return err
+		}
+
+		for i := range workflow.Statuses {
+			workflow.Statuses[i].ID = 0
+			workflow.Statuses[i].WorkflowID = workflow.ID
+		}
+		for i := range workflow.Transitions {
+			workflow.Transitions[i].ID = 0
+			workflow.Transitions[i].WorkflowID = workflow.ID
+		}
+
+		if len(workflow.Statuses) > 0 {
+			if err := tx.Create(&workflow.Statuses).Error; err != nil {
+				return err
+			}
+		}
+		if len(workflow.Transitions) > 0 {
+			if err := tx.Create(&workflow.Transitions).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetDefault делает рабочий процесс процессом пользователя по умолчанию
+func (r *workflowRepository) SetDefault(userID, id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Workflow{}).
+			Where("user_id = ? AND id <> ?", userID, id).
+			Update("is_default", false).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.Workflow{}).
+			Where("id = ?", id).
+			Update("is_default", true).Error
+	})
+}
+
+// Delete удаляет рабочий процесс вместе со статусами и переходами
+func (r *workflowRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("workflow_id = ?", id).Delete(&models.WorkflowStatus{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workflow_id = ?", id).Delete(&models.WorkflowTransition{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Workflow{}, id).Error
+	})
+}
+
+// CountProjects считает проекты, использующие рабочий процесс
+func (r *workflowRepository) CountProjects(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Project{}).Where("workflow_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// CountTasksInStatuses считает задачи процесса, находящиеся в указанных статусах.
+// Без списка статусов считаются все задачи процесса. Задачи в корзине учитываются,
+// чтобы после восстановления их статус оставался допустимым.
+func (r *workflowRepository) CountTasksInStatuses(id uint, statuses []models.TaskStatus) (int64, error) {
+	var count int64
+	query := r.db.Unscoped().Model(&models.Task{}).Where("workflow_id = ?", id)
+	if statuses != nil {
+		query = query.Where("status IN ?", statuses)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
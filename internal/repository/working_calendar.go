@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WorkingCalendarRepository интерфейс для работы с рабочими неделями и нерабочими днями
+type WorkingCalendarRepository interface {
+	WithTx(tx *gorm.DB) WorkingCalendarRepository
+	GetByUserID(userID uint) (*models.WorkingCalendar, error)
+	Save(calendar *models.WorkingCalendar) error
+	GetHolidays(userID uint, from, to string) ([]models.Holiday, error)
+	AddHolidays(holidays []models.Holiday) (int64, error)
+	DeleteHoliday(userID uint, date string) (int64, error)
+	DeleteImportedHolidays(userID uint) error
+}
+
+// workingCalendarRepository реализация репозитория календарей рабочих дней
+type workingCalendarRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkingCalendarRepository создает новый репозиторий календарей рабочих дней
+func NewWorkingCalendarRepository(db *gorm.DB) WorkingCalendarRepository {
+	return &workingCalendarRepository{
+		db: db,
+	}
+}
+
+// WithTx возвращает репозиторий, работающий в транзакции tx
+func (r *workingCalendarRepository) WithTx(tx *gorm.DB) WorkingCalendarRepository {
+	return &workingCalendarRepository{
+		db: tx,
+	}
+}
+
+// GetByUserID получает рабочую неделю пользователя
+func (r *workingCalendarRepository) GetByUserID(userID uint) (*models.WorkingCalendar, error) {
+	var calendar models.WorkingCalendar
+	err := r.db.Where("user_id = ?", userID).First(&calendar).Error
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+// Save создает или заменяет рабочую неделю пользователя
+func (r *workingCalendarRepository) Save(calendar *models.WorkingCalendar) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"weekdays", "updated_at"}),
+	}).Create(calendar).Error
+}
+
+// GetHolidays получает нерабочие дни пользователя по порядку дат; пустые границы
+// периода (даты YYYY-MM-DD, включительно) не ограничивают выборку
+func (r *workingCalendarRepository) GetHolidays(userID uint, from, to string) ([]models.Holiday, error) {
+	var holidays []models.Holiday
+	query := r.db.Where("user_id = ?", userID)
+	if from != "" {
+		query = query.Where("date >= ?", from)
+	}
+	if to != "" {
+		query = query.Where("date <= ?", to)
+	}
+	err := query.Order("date ASC").Find(&holidays).Error
+	return holidays, err
+}
+
+// AddHolidays добавляет нерабочие дни, которых еще нет, и возвращает число добавленных
+func (r *workingCalendarRepository) AddHolidays(holidays []models.Holiday) (int64, error) {
+	if len(holidays) == 0 {
+		return 0, nil
+	}
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "date"}},
+		DoNothing: true,
+	}).CreateInBatches(&holidays, 500)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteHoliday удаляет нерабочий день и возвращает число удаленных записей
+func (r *workingCalendarRepository) DeleteHoliday(userID uint, date string) (int64, error) {
+	result := r.db.Where("user_id = ? AND date = ?", userID, date).Delete(&models.Holiday{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteImportedHolidays удаляет нерабочие дни пользователя, загруженные из файлов
+func (r *workingCalendarRepository) DeleteImportedHolidays(userID uint) error {
+	return r.db.Where("user_id = ? AND imported = ?", userID, true).Delete(&models.Holiday{}).Error
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// appPasswordBytes длина секретной части пароля приложения в байтах
+	appPasswordBytes = 32
+	// appPasswordPrefix префикс, по которому пароль приложения легко узнать в конфигурации
+	appPasswordPrefix = "tsk_"
+	// appPasswordTouchInterval как часто обновляется время последнего использования
+	appPasswordTouchInterval = time.Minute
+)
+
+// AppPasswordService интерфейс для сервиса паролей приложений
+type AppPasswordService interface {
+	GetPasswords(userID uint) ([]models.AppPassword, error)
+	CreatePassword(userID uint, req models.CreateAppPasswordRequest) (*models.AppPasswordResponse, error)
+	DeletePassword(userID, id uint) error
+	Authenticate(login, token string) (*models.User, error)
+}
+
+// appPasswordService реализация сервиса паролей приложений
+type appPasswordService struct {
+	appPasswordRepo repository.AppPasswordRepository
+	userRepo        repository.UserRepository
+}
+
+// NewAppPasswordService создает новый сервис паролей приложений
+func NewAppPasswordService(appPasswordRepo repository.AppPasswordRepository, userRepo repository.UserRepository) AppPasswordService {
+	return &appPasswordService{
+		appPasswordRepo: appPasswordRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// GetPasswords получает пароли приложений пользователя без токенов
+func (s *appPasswordService) GetPasswords(userID uint) ([]models.AppPassword, error) {
+	return s.appPasswordRepo.GetByUserID(userID)
+}
+
+// CreatePassword создает пароль приложения; токен возвращается только в ответе на создание
+func (s *appPasswordService) CreatePassword(userID uint, req models.CreateAppPasswordRequest) (*models.AppPasswordResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("invalid name: name is required")
+	}
+
+	secret := make([]byte, appPasswordBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	token := appPasswordPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	password := &models.AppPassword{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hashToken(token),
+	}
+	if err := s.appPasswordRepo.Create(password); err != nil {
+		return nil, err
+	}
+
+	return &models.AppPasswordResponse{AppPassword: *password, Token: token}, nil
+}
+
+// DeletePassword отзывает пароль приложения
+func (s *appPasswordService) DeletePassword(userID, id uint) error {
+	deleted, err := s.appPasswordRepo.Delete(userID, id)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("app password not found")
+	}
+	return nil
+}
+
+// Authenticate находит владельца пароля приложения. Логин, если передан, должен
+// совпадать с email или именем пользователя; при входе по токену Bearer он пустой.
+func (s *appPasswordService) Authenticate(login, token string) (*models.User, error) {
+	password, err := s.appPasswordRepo.GetByTokenHash(hashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid credentials")
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(password.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid credentials")
+		}
+		return nil, err
+	}
+	if login != "" && !strings.EqualFold(login, user.Email) && login != user.Username {
+		return nil, errors.New("invalid credentials")
+	}
+
+	now := time.Now().UTC()
+	if password.LastUsedAt == nil || now.Sub(*password.LastUsedAt) >= appPasswordTouchInterval {
+		if err := s.appPasswordRepo.TouchLastUsed(password.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
@@ -0,0 +1,486 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+	"golang_server/pkg/ical"
+
+	"gorm.io/gorm"
+)
+
+// CalDAVService интерфейс для сервиса синхронизации задач по CalDAV.
+// Задачи пользователя вне архива образуют одну коллекцию дел VTODO.
+type CalDAVService interface {
+	GetCollection(userID uint) (*models.CalDAVCollection, error)
+	GetObjects(userID uint, filter models.CalDAVFilter) ([]models.CalDAVObject, error)
+	GetObject(userID uint, name string) (*models.CalDAVObject, error)
+	PutObject(userID uint, name string, data io.Reader, expectedVersion *int, mustExist, mustNotExist bool) (bool, error)
+	DeleteObject(userID uint, name string, expectedVersion *int) error
+}
+
+// calDAVService реализация сервиса CalDAV. Чтение идет напрямую из репозитория,
+// а изменения — через сервис задач, чтобы действовали его проверки и история изменений.
+type calDAVService struct {
+	taskService        TaskService
+	taskRepo           repository.TaskRepository
+	calendarObjectRepo repository.CalendarObjectRepository
+	userRepo           repository.UserRepository
+	workflowService    WorkflowService
+}
+
+// NewCalDAVService создает новый сервис CalDAV
+func NewCalDAVService(taskService TaskService, taskRepo repository.TaskRepository, calendarObjectRepo repository.CalendarObjectRepository, userRepo repository.UserRepository, workflowService WorkflowService) CalDAVService {
+	return &calDAVService{
+		taskService:        taskService,
+		taskRepo:           taskRepo,
+		calendarObjectRepo: calendarObjectRepo,
+		userRepo:           userRepo,
+		workflowService:    workflowService,
+	}
+}
+
+// calDAVEntry задача коллекции с именем ресурса и UID
+type calDAVEntry struct {
+	task *models.Task
+	name string
+	uid  string
+}
+
+// GetCollection возвращает свойства коллекции. CTag — хеш имен и версий всех ресурсов,
+// поэтому он меняется при создании, изменении и удалении любой задачи.
+func (s *calDAVService) GetCollection(userID uint) (*models.CalDAVCollection, error) {
+	entries, err := s.listEntries(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	for _, entry := range entries {
+		fmt.Fprintf(hash, "%s:%d\n", entry.name, entry.task.Version)
+	}
+	return &models.CalDAVCollection{
+		DisplayName: "Tasks",
+		CTag:        hex.EncodeToString(hash.Sum(nil)[:16]),
+	}, nil
+}
+
+// GetObjects возвращает ресурсы коллекции, подходящие под фильтр calendar-query:
+// задача должна пересекаться с интервалом фильтра
+func (s *calDAVService) GetObjects(userID uint, filter models.CalDAVFilter) ([]models.CalDAVObject, error) {
+	if filter.Component != "" && filter.Component != "VTODO" {
+		return []models.CalDAVObject{}, nil
+	}
+
+	entries, err := s.listEntries(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	workflows := newWorkflowCache(s.workflowService, userID)
+	objects := make([]models.CalDAVObject, 0, len(entries))
+	for _, entry := range entries {
+		if filter.Start != nil && entry.task.EndDate.Before(*filter.Start) {
+			continue
+		}
+		if filter.End != nil && !entry.task.StartDate.Before(*filter.End) {
+			continue
+		}
+		object, err := s.buildObject(entry, workflows)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, *object)
+	}
+	return objects, nil
+}
+
+// GetObject возвращает ресурс коллекции по имени
+func (s *calDAVService) GetObject(userID uint, name string) (*models.CalDAVObject, error) {
+	entry, err := s.resolve(userID, name)
+	if err != nil {
+		return nil, err
+	}
+	if entry.task == nil {
+		return nil, errors.New("calendar object not found")
+	}
+	return s.buildObject(*entry, newWorkflowCache(s.workflowService, userID))
+}
+
+// PutObject создает задачу из дела VTODO или обновляет задачу ресурса. Свойства,
+// которых нет в задаче (напоминания, повторения), не сохраняются. Возвращает
+// created = true, если ресурс создан.
+func (s *calDAVService) PutObject(userID uint, name string, data io.Reader, expectedVersion *int, mustExist, mustNotExist bool) (bool, error) {
+	if !strings.HasSuffix(name, ".ics") || strings.Contains(name, "/") {
+		return false, errors.New("invalid calendar object name")
+	}
+
+	todo, err := parseCalendarTodo(data)
+	if err != nil {
+		return false, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return false, err
+	}
+	fields, err := parseTodoFields(todo, user.Location())
+	if err != nil {
+		return false, err
+	}
+
+	entry, err := s.resolve(userID, name)
+	if err != nil {
+		return false, err
+	}
+	workflows := newWorkflowCache(s.workflowService, userID)
+
+	if entry.task == nil {
+		if expectedVersion != nil || mustExist {
+			return false, repository.ErrVersionConflict
+		}
+		// Имена по умолчанию заняты задачами, иначе у задачи появились бы два адреса
+		if _, ok := parseCalDAVName(name); ok {
+			return false, errors.New("invalid calendar object name: task-<id>.ics names are reserved")
+		}
+		return true, s.createFromTodo(userID, name, fields, user.Location(), workflows)
+	}
+	if mustNotExist {
+		return false, repository.ErrVersionConflict
+	}
+	return false, s.updateFromTodo(userID, entry.task, fields, expectedVersion, workflows)
+}
+
+// DeleteObject перемещает задачу ресурса в корзину
+func (s *calDAVService) DeleteObject(userID uint, name string, expectedVersion *int) error {
+	entry, err := s.resolve(userID, name)
+	if err != nil {
+		return err
+	}
+	if entry.task == nil {
+		return errors.New("calendar object not found")
+	}
+	return s.taskService.DeleteTask(userID, entry.task.ID, expectedVersion)
+}
+
+// createFromTodo создает задачу и в той же транзакции запоминает выбранные клиентом имя ресурса и UID.
+// Дело без дат получает срок на сегодня в часовом поясе пользователя.
+func (s *calDAVService) createFromTodo(userID uint, name string, fields *todoFields, location *time.Location, workflows *workflowCache) error {
+	start, due := fields.start, fields.due
+	switch {
+	case start == nil && due == nil:
+		today := overdueBefore(time.Now(), location)
+		start, due = &today, &today
+	case start == nil:
+		start = due
+	case due == nil:
+		due = start
+	}
+
+	workflow, err := workflows.get(nil)
+	if err != nil {
+		return err
+	}
+	var status models.TaskStatus
+	if fields.category != models.StatusCategoryTodo {
+		status = workflow.StatusForCategory(workflow.InitialStatus(), fields.category)
+	}
+
+	_, err = s.taskService.CreateCalendarTask(userID, models.CreateTaskRequest{
+		Title:       fields.title,
+		Description: fields.description,
+		Priority:    fields.priority,
+		StartDate:   *start,
+		EndDate:     *due,
+		Tags:        fields.tags,
+	}, status, &models.CalendarObject{
+		UserID: userID,
+		Name:   name,
+		UID:    fields.uid,
+	})
+	return err
+}
+
+// updateFromTodo обновляет задачу по делу VTODO. Ресурс заменяется целиком, поэтому
+// отсутствующие описание, метки и приоритет очищаются. Без DTSTART начало совпадает
+// со сроком (так задача и выгружается), а без DUE срок остается прежним.
+// Статус меняется, только если изменилась его категория.
+func (s *calDAVService) updateFromTodo(userID uint, task *models.Task, fields *todoFields, expectedVersion *int, workflows *workflowCache) error {
+	start, due := fields.start, fields.due
+	if due == nil {
+		due = &task.EndDate
+		if start == nil {
+			start = &task.StartDate
+		}
+	} else if start == nil {
+		start = due
+	}
+	if due.Before(*start) {
+		due = start
+	}
+
+	tags := fields.tags
+	if tags == nil {
+		tags = []string{}
+	}
+	req := models.UpdateTaskRequest{
+		Title:           &fields.title,
+		Description:     &fields.description,
+		Priority:        &fields.priority,
+		StartDate:       start,
+		EndDate:         due,
+		Tags:            &tags,
+		ExpectedVersion: expectedVersion,
+	}
+
+	category, err := workflows.category(task)
+	if err != nil {
+		return err
+	}
+	if category != fields.category {
+		workflow, err := workflows.get(task.WorkflowID)
+		if err != nil {
+			return err
+		}
+		if status := workflow.StatusForCategory(task.Status, fields.category); status != "" && status != task.Status {
+			req.Status = &status
+		}
+	}
+
+	_, err = s.taskService.UpdateTask(userID, task.ID, req)
+	return err
+}
+
+// listEntries получает задачи коллекции с именами ресурсов
+func (s *calDAVService) listEntries(userID uint) ([]calDAVEntry, error) {
+	tasks, err := s.taskRepo.GetForCalendar(userID, models.CalendarTaskFilter{})
+	if err != nil {
+		return nil, err
+	}
+	objects, err := s.calendarObjectRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byTask := make(map[uint]models.CalendarObject, len(objects))
+	for _, object := range objects {
+		byTask[object.TaskID] = object
+	}
+
+	entries := make([]calDAVEntry, len(tasks))
+	for i := range tasks {
+		entries[i] = calDAVEntry{task: &tasks[i], name: calDAVName(tasks[i].ID), uid: calendarUID(tasks[i].ID)}
+		if object, ok := byTask[tasks[i].ID]; ok {
+			entries[i].name = object.Name
+			entries[i].uid = object.UID
+		}
+	}
+	return entries, nil
+}
+
+// resolve находит задачу ресурса: сначала среди имен, выбранных клиентами, затем
+// по имени по умолчанию task-<id>.ics. Отсутствующий ресурс возвращается с task = nil.
+func (s *calDAVService) resolve(userID uint, name string) (*calDAVEntry, error) {
+	entry := &calDAVEntry{name: name}
+
+	taskID, uid := uint(0), ""
+	object, err := s.calendarObjectRepo.GetByName(userID, name)
+	switch {
+	case err == nil:
+		taskID, uid = object.TaskID, object.UID
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		id, ok := parseCalDAVName(name)
+		if !ok {
+			return entry, nil
+		}
+		taskID, uid = id, calendarUID(id)
+	default:
+		return nil, err
+	}
+
+	// Задача в корзине или чужая задача для клиента не существует
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entry, nil
+		}
+		return nil, err
+	}
+	if task.UserID != userID {
+		return entry, nil
+	}
+
+	entry.task, entry.uid = task, uid
+	return entry, nil
+}
+
+// buildObject представляет задачу ресурсом с календарем из одного дела VTODO
+func (s *calDAVService) buildObject(entry calDAVEntry, workflows *workflowCache) (*models.CalDAVObject, error) {
+	category, err := workflows.category(entry.task)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := newCalendar()
+	calendar.Components = append(calendar.Components, calendarTodo(entry.task, category, entry.uid))
+
+	var data bytes.Buffer
+	if err := ical.Encode(&data, calendar); err != nil {
+		return nil, err
+	}
+	return &models.CalDAVObject{Name: entry.name, Version: entry.task.Version, Data: data.Bytes()}, nil
+}
+
+// calDAVName возвращает имя ресурса задачи по умолчанию
+func calDAVName(taskID uint) string {
+	return fmt.Sprintf("task-%d.ics", taskID)
+}
+
+// parseCalDAVName извлекает ID задачи из имени ресурса по умолчанию
+func parseCalDAVName(name string) (uint, bool) {
+	if !strings.HasPrefix(name, "task-") || !strings.HasSuffix(name, ".ics") {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, "task-"), ".ics"), 10, 32)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// todoFields поля задачи, прочитанные из дела VTODO
+type todoFields struct {
+	uid         string
+	title       string
+	description string
+	priority    models.TaskPriority
+	category    models.StatusCategory
+	start       *time.Time
+	due         *time.Time
+	tags        []string
+}
+
+// parseCalendarTodo разбирает календарь ресурса и возвращает его единственное дело.
+// Часовые пояса VTIMEZONE пропускаются; другие компоненты не поддерживаются.
+func parseCalendarTodo(data io.Reader) (*ical.Component, error) {
+	calendar, err := ical.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar data: %w", err)
+	}
+	if calendar.Name != "VCALENDAR" {
+		return nil, errors.New("invalid calendar data: VCALENDAR expected")
+	}
+
+	var todo *ical.Component
+	for i := range calendar.Components {
+		switch calendar.Components[i].Name {
+		case "VTIMEZONE":
+		case "VTODO":
+			if todo != nil {
+				return nil, errors.New("invalid calendar data: a resource must contain a single VTODO, recurrence overrides are not supported")
+			}
+			todo = &calendar.Components[i]
+		default:
+			return nil, errors.New("unsupported calendar component: " + calendar.Components[i].Name)
+		}
+	}
+	if todo == nil {
+		return nil, errors.New("invalid calendar data: VTODO expected")
+	}
+	return todo, nil
+}
+
+// parseTodoFields читает поля задачи из дела. «Плавающее» время и неизвестные пояса
+// TZID берутся в часовом поясе пользователя.
+func parseTodoFields(todo *ical.Component, location *time.Location) (*todoFields, error) {
+	fields := &todoFields{
+		priority: models.TaskPriorityMedium,
+		category: models.StatusCategoryTodo,
+	}
+
+	if uid := todo.Get("UID"); uid != nil {
+		fields.uid = strings.TrimSpace(uid.Value)
+	}
+	if fields.uid == "" {
+		return nil, errors.New("invalid calendar data: UID is required")
+	}
+
+	if summary := todo.Get("SUMMARY"); summary != nil {
+		fields.title = strings.TrimSpace(ical.UnescapeText(summary.Value))
+	}
+	if length := utf8.RuneCountInString(fields.title); length < 1 || length > 255 {
+		return nil, errors.New("invalid calendar data: SUMMARY must be between 1 and 255 characters")
+	}
+	if description := todo.Get("DESCRIPTION"); description != nil {
+		fields.description = ical.UnescapeText(description.Value)
+	}
+
+	if priority := todo.Get("PRIORITY"); priority != nil {
+		value, err := strconv.Atoi(strings.TrimSpace(priority.Value))
+		if err != nil || value < 0 || value > 9 {
+			return nil, errors.New("invalid calendar data: PRIORITY must be between 0 and 9")
+		}
+		fields.priority = taskPriorityFromCalendar(value)
+	}
+
+	for _, categories := range todo.GetAll("CATEGORIES") {
+		for _, tag := range ical.SplitText(categories.Value) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				fields.tags = append(fields.tags, tag)
+			}
+		}
+	}
+
+	status := ""
+	if property := todo.Get("STATUS"); property != nil {
+		status = strings.ToUpper(strings.TrimSpace(property.Value))
+	}
+	switch {
+	case status == "COMPLETED" || status == "CANCELLED" || todo.Get("COMPLETED") != nil:
+		fields.category = models.StatusCategoryDone
+	case status == "IN-PROCESS":
+		fields.category = models.StatusCategoryDoing
+	}
+
+	for _, date := range []struct {
+		name   string
+		target **time.Time
+	}{{"DTSTART", &fields.start}, {"DUE", &fields.due}} {
+		property := todo.Get(date.name)
+		if property == nil {
+			continue
+		}
+		t, _, err := ical.ParseTime(property, location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid calendar data: invalid %s", date.name)
+		}
+		t = t.UTC()
+		*date.target = &t
+	}
+
+	return fields, nil
+}
+
+// taskPriorityFromCalendar переводит PRIORITY в приоритет задачи:
+// 1 — критический, 2–4 — высокий, 6–9 — низкий, 0 (не задан) и 5 — средний
+func taskPriorityFromCalendar(value int) models.TaskPriority {
+	switch {
+	case value == 1:
+		return models.TaskPriorityCritical
+	case value >= 2 && value <= 4:
+		return models.TaskPriorityHigh
+	case value >= 6:
+		return models.TaskPriorityLow
+	}
+	return models.TaskPriorityMedium
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// failingCalendarObjectRepo не может сохранить ресурс календаря
+type failingCalendarObjectRepo struct {
+	repository.CalendarObjectRepository
+}
+
+func (r *failingCalendarObjectRepo) WithTx(tx *gorm.DB) repository.CalendarObjectRepository {
+	return &failingCalendarObjectRepo{CalendarObjectRepository: r.CalendarObjectRepository.WithTx(tx)}
+}
+
+func (r *failingCalendarObjectRepo) Save(*models.CalendarObject) error {
+	return errors.New("disk is full")
+}
+
+// todoObject формирует ресурс CalDAV с одним делом
+func todoObject(uid, summary string) *strings.Reader {
+	return strings.NewReader("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\n" +
+		"UID:" + uid + "\r\nSUMMARY:" + summary + "\r\nDUE;VALUE=DATE:20261020\r\n" +
+		"END:VTODO\r\nEND:VCALENDAR\r\n")
+}
+
+// newCalDAVService создает сервис CalDAV поверх сервиса задач окружения
+func (e *testEnv) newCalDAVService() CalDAVService {
+	return NewCalDAVService(
+		e.taskService,
+		e.taskRepo,
+		repository.NewCalendarObjectRepository(e.db),
+		e.userRepo,
+		NewWorkflowService(repository.NewTransactor(e.db), repository.NewWorkflowRepository(e.db)),
+	)
+}
+
+func TestPutObjectCreatesTaskWithObjectName(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+	calDAV := env.newCalDAVService()
+
+	created, err := calDAV.PutObject(userID, "client-1.ics", todoObject("client-1", "Milk"), nil, false, true)
+	if err != nil {
+		t.Fatalf("put object: %v", err)
+	}
+	if !created {
+		t.Fatal("expected a new object")
+	}
+
+	object, err := calDAV.GetObject(userID, "client-1.ics")
+	if err != nil {
+		t.Fatalf("get object: %v", err)
+	}
+	if !strings.Contains(string(object.Data), "UID:client-1") {
+		t.Errorf("object data = %q, want the client UID", object.Data)
+	}
+}
+
+func TestPutObjectRollsBackTaskWhenObjectIsNotSaved(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+	env.taskService.calendarObjectRepo = &failingCalendarObjectRepo{
+		CalendarObjectRepository: env.taskService.calendarObjectRepo,
+	}
+
+	_, err := env.newCalDAVService().PutObject(userID, "client-1.ics", todoObject("client-1", "Milk"), nil, false, true)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	var tasks int64
+	env.db.Unscoped().Model(&models.Task{}).Count(&tasks)
+	if tasks != 0 {
+		t.Errorf("%d tasks left without a calendar object, want none", tasks)
+	}
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+	"golang_server/pkg/ical"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// calendarTokenBytes длина секретного токена подписки в байтах
+	calendarTokenBytes = 32
+	// calendarUIDDomain домен в UID компонентов: UID задачи не меняется между выгрузками
+	calendarUIDDomain = "tasks.golang-server"
+)
+
+// CalendarService интерфейс для сервиса календаря задач в формате iCalendar
+type CalendarService interface {
+	GetSubscription(userID uint) (*models.CalendarFeedResponse, error)
+	RegenerateToken(userID uint) (*models.CalendarFeedResponse, error)
+	RevokeToken(userID uint) error
+	BuildFeed(token string, params models.CalendarFeedParams) (*ical.Component, error)
+}
+
+// calendarService реализация сервиса календаря задач
+type calendarService struct {
+	calendarFeedRepo repository.CalendarFeedRepository
+	taskRepo         repository.TaskRepository
+	userRepo         repository.UserRepository
+	workflowService  WorkflowService
+}
+
+// NewCalendarService создает новый сервис календаря задач
+func NewCalendarService(calendarFeedRepo repository.CalendarFeedRepository, taskRepo repository.TaskRepository, userRepo repository.UserRepository, workflowService WorkflowService) CalendarService {
+	return &calendarService{
+		calendarFeedRepo: calendarFeedRepo,
+		taskRepo:         taskRepo,
+		userRepo:         userRepo,
+		workflowService:  workflowService,
+	}
+}
+
+// GetSubscription возвращает состояние подписки пользователя без токена
+func (s *calendarService) GetSubscription(userID uint) (*models.CalendarFeedResponse, error) {
+	feed, err := s.calendarFeedRepo.GetByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.CalendarFeedResponse{Enabled: false}, nil
+		}
+		return nil, err
+	}
+	return &models.CalendarFeedResponse{Enabled: true, CreatedAt: &feed.CreatedAt}, nil
+}
+
+// RegenerateToken создает новый токен подписки; прежний адрес перестает работать
+func (s *calendarService) RegenerateToken(userID uint) (*models.CalendarFeedResponse, error) {
+	secret := make([]byte, calendarTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	now := time.Now().UTC()
+	feed := &models.CalendarFeed{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.calendarFeedRepo.Save(feed); err != nil {
+		return nil, err
+	}
+
+	return &models.CalendarFeedResponse{Enabled: true, Token: token, CreatedAt: &now}, nil
+}
+
+// RevokeToken отключает подписку пользователя
+func (s *calendarService) RevokeToken(userID uint) error {
+	deleted, err := s.calendarFeedRepo.DeleteByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("calendar feed not found")
+	}
+	return nil
+}
+
+// BuildFeed строит календарь задач владельца токена. Задача выгружается событием
+// VEVENT или делом VTODO с постоянным UID; статус берется по категории статуса
+// в процессе задачи. Задачи в архиве и в корзине не выгружаются.
+func (s *calendarService) BuildFeed(token string, params models.CalendarFeedParams) (*ical.Component, error) {
+	feed, err := s.calendarFeedRepo.GetByTokenHash(hashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("calendar feed not found")
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(feed.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := models.CalendarTaskFilter{
+		ProjectID: params.ProjectID,
+		Tag:       strings.TrimSpace(params.Tag),
+	}
+	for _, status := range strings.Split(params.Status, ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			filter.Statuses = append(filter.Statuses, models.TaskStatus(status))
+		}
+	}
+
+	tasks, err := s.taskRepo.GetForCalendar(user.ID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := newCalendar()
+	calendar.Add("METHOD", "PUBLISH")
+	calendar.AddText("X-WR-CALNAME", "Tasks: "+user.Username)
+
+	workflows := newWorkflowCache(s.workflowService, user.ID)
+	for i := range tasks {
+		category, err := workflows.category(&tasks[i])
+		if err != nil {
+			return nil, err
+		}
+		uid := calendarUID(tasks[i].ID)
+		if params.Component == models.CalendarComponentTodo {
+			calendar.Components = append(calendar.Components, calendarTodo(&tasks[i], category, uid))
+		} else {
+			calendar.Components = append(calendar.Components, calendarEvent(&tasks[i], category, uid))
+		}
+	}
+
+	return calendar, nil
+}
+
+// newCalendar создает пустой календарь с обязательными свойствами
+func newCalendar() *ical.Component {
+	calendar := &ical.Component{Name: "VCALENDAR"}
+	calendar.Add("VERSION", "2.0")
+	calendar.Add("PRODID", "-//golang_server//Tasks//RU")
+	calendar.Add("CALSCALE", "GREGORIAN")
+	return calendar
+}
+
+// workflowCache загружает процессы задач пользователя не больше одного раза за запрос
+type workflowCache struct {
+	workflowService WorkflowService
+	userID          uint
+	workflows       map[uint]*models.Workflow
+}
+
+// newWorkflowCache создает кэш процессов пользователя
+func newWorkflowCache(workflowService WorkflowService, userID uint) *workflowCache {
+	return &workflowCache{
+		workflowService: workflowService,
+		userID:          userID,
+		workflows:       make(map[uint]*models.Workflow),
+	}
+}
+
+// get возвращает процесс по ID; nil означает процесс пользователя по умолчанию
+func (c *workflowCache) get(workflowID *uint) (*models.Workflow, error) {
+	var key uint
+	if workflowID != nil {
+		key = *workflowID
+	}
+
+	if workflow, ok := c.workflows[key]; ok {
+		return workflow, nil
+	}
+
+	var workflow *models.Workflow
+	var err error
+	if workflowID != nil {
+		workflow, err = c.workflowService.GetWorkflowByID(c.userID, *workflowID)
+	} else {
+		workflow, err = c.workflowService.GetDefaultWorkflow(c.userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.workflows[key] = workflow
+	return workflow, nil
+}
+
+// category определяет категорию статуса задачи по ее процессу
+func (c *workflowCache) category(task *models.Task) (models.StatusCategory, error) {
+	workflow, err := c.get(task.WorkflowID)
+	if err != nil {
+		return "", err
+	}
+
+	if status, ok := workflow.FindStatus(task.Status); ok {
+		return status.Category, nil
+	}
+	if task.CompletedAt != nil {
+		return models.StatusCategoryDone, nil
+	}
+	return models.StatusCategoryTodo, nil
+}
+
+// calendarEvent представляет задачу событием VEVENT. Задача с датами без времени
+// становится событием на весь день: DTEND указывает на день после окончания.
+func calendarEvent(task *models.Task, category models.StatusCategory, uid string) ical.Component {
+	event := ical.Component{Name: "VEVENT"}
+	addCalendarCommon(&event, task, uid)
+
+	if isAllDay(task) {
+		event.AddDate("DTSTART", task.StartDate)
+		event.AddDate("DTEND", task.EndDate.AddDate(0, 0, 1))
+	} else {
+		event.AddDateTime("DTSTART", task.StartDate)
+		event.AddDateTime("DTEND", task.EndDate)
+	}
+
+	status := "CONFIRMED"
+	if category == models.StatusCategoryTodo {
+		status = "TENTATIVE"
+	}
+	event.Add("STATUS", status)
+	event.Add("TRANSP", "TRANSPARENT")
+	return event
+}
+
+// calendarTodo представляет задачу делом VTODO со сроком DUE. DTSTART указывается,
+// только если начало раньше срока, как того требует RFC 5545.
+func calendarTodo(task *models.Task, category models.StatusCategory, uid string) ical.Component {
+	todo := ical.Component{Name: "VTODO"}
+	addCalendarCommon(&todo, task, uid)
+
+	if isAllDay(task) {
+		if task.StartDate.Before(task.EndDate) {
+			todo.AddDate("DTSTART", task.StartDate)
+		}
+		todo.AddDate("DUE", task.EndDate)
+	} else {
+		if task.StartDate.Before(task.EndDate) {
+			todo.AddDateTime("DTSTART", task.StartDate)
+		}
+		todo.AddDateTime("DUE", task.EndDate)
+	}
+
+	switch category {
+	case models.StatusCategoryDone:
+		todo.Add("STATUS", "COMPLETED")
+		todo.Add("PERCENT-COMPLETE", "100")
+		if task.CompletedAt != nil {
+			todo.AddDateTime("COMPLETED", *task.CompletedAt)
+		}
+	case models.StatusCategoryDoing:
+		todo.Add("STATUS", "IN-PROCESS")
+	default:
+		todo.Add("STATUS", "NEEDS-ACTION")
+	}
+	return todo
+}
+
+// addCalendarCommon добавляет свойства, общие для событий и дел
+func addCalendarCommon(component *ical.Component, task *models.Task, uid string) {
+	component.Add("UID", uid)
+	component.AddDateTime("DTSTAMP", task.UpdatedAt)
+	component.AddDateTime("CREATED", task.CreatedAt)
+	component.AddDateTime("LAST-MODIFIED", task.UpdatedAt)
+	// Версия задачи начинается с 1, а SEQUENCE — с 0
+	component.Add("SEQUENCE", fmt.Sprint(task.Version-1))
+	component.AddText("SUMMARY", task.Title)
+	if task.Description != "" {
+		component.AddText("DESCRIPTION", task.Description)
+	}
+	component.Add("PRIORITY", calendarPriority(task.Priority))
+	if len(task.Tags) > 0 {
+		categories := make([]string, len(task.Tags))
+		for i, tag := range task.Tags {
+			categories[i] = ical.EscapeText(tag)
+		}
+		component.Add("CATEGORIES", strings.Join(categories, ","))
+	}
+}
+
+// calendarUID возвращает UID задачи по умолчанию
+func calendarUID(taskID uint) string {
+	return fmt.Sprintf("task-%d@%s", taskID, calendarUIDDomain)
+}
+
+// calendarPriority переводит приоритет задачи в шкалу PRIORITY: 1 — наивысший, 9 — низший
+func calendarPriority(priority models.TaskPriority) string {
+	switch priority {
+	case models.TaskPriorityCritical:
+		return "1"
+	case models.TaskPriorityHigh:
+		return "3"
+	case models.TaskPriorityLow:
+		return "9"
+	}
+	return "5"
+}
+
+// isAllDay проверяет, что даты задачи заданы без времени (полночь UTC)
+func isAllDay(task *models.Task) bool {
+	return isMidnightUTC(task.StartDate) && isMidnightUTC(task.EndDate)
+}
+
+// isMidnightUTC проверяет, что момент приходится на начало суток UTC
+func isMidnightUTC(t time.Time) bool {
+	t = t.UTC()
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+}
+
+// hashToken возвращает хеш секретного токена для хранения в базе
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
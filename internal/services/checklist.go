@@ -0,0 +1,254 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+	"golang_server/pkg/rank"
+
+	"gorm.io/gorm"
+)
+
+// maxChecklistItems максимальное число пунктов в чек-листе задачи
+const maxChecklistItems = 100
+
+// ChecklistService интерфейс для сервиса чек-листов задач
+type ChecklistService interface {
+	GetItems(userID, taskID uint) ([]models.ChecklistItem, error)
+	CreateItem(userID, taskID uint, req models.CreateChecklistItemRequest) (*models.ChecklistItem, error)
+	UpdateItem(userID, taskID, itemID uint, req models.UpdateChecklistItemRequest) (*models.ChecklistItem, error)
+	ToggleItem(userID, taskID, itemID uint) (*models.ChecklistItem, error)
+	DeleteItem(userID, taskID, itemID uint) error
+	ReorderItems(userID, taskID uint, req models.ReorderChecklistRequest) ([]models.ChecklistItem, error)
+}
+
+// checklistService реализация сервиса чек-листов
+type checklistService struct {
+	transactor    repository.Transactor
+	checklistRepo repository.ChecklistRepository
+	taskRepo      repository.TaskRepository
+}
+
+// NewChecklistService создает новый сервис чек-листов
+func NewChecklistService(transactor repository.Transactor, checklistRepo repository.ChecklistRepository, taskRepo repository.TaskRepository) ChecklistService {
+	return &checklistService{
+		transactor:    transactor,
+		checklistRepo: checklistRepo,
+		taskRepo:      taskRepo,
+	}
+}
+
+// GetItems получает пункты чек-листа задачи по порядку
+func (s *checklistService) GetItems(userID, taskID uint) ([]models.ChecklistItem, error) {
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.checklistRepo.GetByTaskID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []models.ChecklistItem{}
+	}
+	return items, nil
+}
+
+// CreateItem добавляет пункт в конец чек-листа задачи
+func (s *checklistService) CreateItem(userID, taskID uint, req models.CreateChecklistItemRequest) (*models.ChecklistItem, error) {
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.checklistRepo.GetByTaskID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) >= maxChecklistItems {
+		return nil, fmt.Errorf("checklist is full: a task can have at most %d items", maxChecklistItems)
+	}
+
+	last, err := s.checklistRepo.GetLastPosition(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &models.ChecklistItem{
+		TaskID:   taskID,
+		Title:    req.Title,
+		Required: req.Required,
+		Position: rank.After(last),
+	}
+	err = s.changeChecklist(taskID, func(checklistRepo repository.ChecklistRepository) error {
+		return checklistRepo.Create(item)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// UpdateItem изменяет пункт чек-листа
+func (s *checklistService) UpdateItem(userID, taskID, itemID uint, req models.UpdateChecklistItemRequest) (*models.ChecklistItem, error) {
+	item, err := s.getItem(userID, taskID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		item.Title = *req.Title
+	}
+	if req.Required != nil {
+		item.Required = *req.Required
+	}
+	if req.Done != nil {
+		setDone(item, *req.Done)
+	}
+
+	err = s.changeChecklist(taskID, func(checklistRepo repository.ChecklistRepository) error {
+		return checklistRepo.Update(item)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// ToggleItem отмечает пункт чек-листа выполненным или снимает отметку
+func (s *checklistService) ToggleItem(userID, taskID, itemID uint) (*models.ChecklistItem, error) {
+	item, err := s.getItem(userID, taskID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	setDone(item, !item.Done)
+
+	err = s.changeChecklist(taskID, func(checklistRepo repository.ChecklistRepository) error {
+		return checklistRepo.Update(item)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// DeleteItem удаляет пункт чек-листа
+func (s *checklistService) DeleteItem(userID, taskID, itemID uint) error {
+	item, err := s.getItem(userID, taskID, itemID)
+	if err != nil {
+		return err
+	}
+	return s.changeChecklist(taskID, func(checklistRepo repository.ChecklistRepository) error {
+		return checklistRepo.Delete(item.ID)
+	})
+}
+
+// ReorderItems расставляет пункты чек-листа в указанном порядке.
+// Порядок должен содержать каждый пункт чек-листа ровно один раз.
+func (s *checklistService) ReorderItems(userID, taskID uint, req models.ReorderChecklistRequest) ([]models.ChecklistItem, error) {
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.checklistRepo.GetByTaskID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[uint]int, len(items))
+	for i, item := range items {
+		index[item.ID] = i
+	}
+	if len(req.ItemIDs) != len(items) {
+		return nil, errors.New("invalid order: item_ids must list every checklist item exactly once")
+	}
+	seen := make(map[uint]bool, len(req.ItemIDs))
+	for _, itemID := range req.ItemIDs {
+		if _, ok := index[itemID]; !ok || seen[itemID] {
+			return nil, errors.New("invalid order: item_ids must list every checklist item exactly once")
+		}
+		seen[itemID] = true
+	}
+
+	positions := rank.Sequence(len(req.ItemIDs))
+	err = s.transactor.Transaction(func(tx *gorm.DB) error {
+		for i, itemID := range req.ItemIDs {
+			if err := s.checklistRepo.WithTx(tx).UpdatePosition(itemID, positions[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reordered := make([]models.ChecklistItem, len(req.ItemIDs))
+	for i, itemID := range req.ItemIDs {
+		reordered[i] = items[index[itemID]]
+		reordered[i].Position = positions[i]
+	}
+	return reordered, nil
+}
+
+// changeChecklist изменяет чек-лист задачи и в той же транзакции увеличивает версию задачи:
+// счетчики чек-листа входят в ответ с задачей, и ее ETag должен измениться
+func (s *checklistService) changeChecklist(taskID uint, change func(checklistRepo repository.ChecklistRepository) error) error {
+	return s.transactor.Transaction(func(tx *gorm.DB) error {
+		if err := change(s.checklistRepo.WithTx(tx)); err != nil {
+			return err
+		}
+		return s.taskRepo.WithTx(tx).IncrementVersion(taskID)
+	})
+}
+
+// checkTask проверяет, что задача существует и принадлежит пользователю
+func (s *checklistService) checkTask(userID, taskID uint) error {
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("task not found")
+		}
+		return err
+	}
+	if task.UserID != userID {
+		return errors.New("access denied")
+	}
+	return nil
+}
+
+// getItem получает пункт чек-листа задачи пользователя
+func (s *checklistService) getItem(userID, taskID, itemID uint) (*models.ChecklistItem, error) {
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.checklistRepo.GetByID(itemID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("checklist item not found")
+		}
+		return nil, err
+	}
+	if item.TaskID != taskID {
+		return nil, errors.New("checklist item not found")
+	}
+	return item, nil
+}
+
+// setDone меняет отметку выполнения пункта и момент ее установки
+func setDone(item *models.ChecklistItem, done bool) {
+	if item.Done == done {
+		return
+	}
+	item.Done = done
+	if done {
+		now := time.Now().UTC()
+		item.DoneAt = &now
+	} else {
+		item.DoneAt = nil
+	}
+}
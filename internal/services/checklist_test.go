@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+)
+
+func TestChecklistChangesBumpTaskVersion(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	task := env.createTask(t, userID, "Onboarding", day, day)
+
+	checklist := NewChecklistService(repository.NewTransactor(env.db), repository.NewChecklistRepository(env.db), env.taskRepo)
+	version := task.Version
+	expectBump := func(action string) {
+		t.Helper()
+		current, err := env.taskService.GetTaskByID(userID, task.ID)
+		if err != nil {
+			t.Fatalf("get task: %v", err)
+		}
+		if current.Version != version+1 {
+			t.Fatalf("version after %s = %d, want %d", action, current.Version, version+1)
+		}
+		version = current.Version
+	}
+
+	item, err := checklist.CreateItem(userID, task.ID, models.CreateChecklistItemRequest{Title: "Sign NDA"})
+	if err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	expectBump("create")
+
+	if _, err := checklist.ToggleItem(userID, task.ID, item.ID); err != nil {
+		t.Fatalf("toggle item: %v", err)
+	}
+	expectBump("toggle")
+
+	title := "Sign the NDA"
+	if _, err := checklist.UpdateItem(userID, task.ID, item.ID, models.UpdateChecklistItemRequest{Title: &title}); err != nil {
+		t.Fatalf("update item: %v", err)
+	}
+	expectBump("update")
+
+	if err := checklist.DeleteItem(userID, task.ID, item.ID); err != nil {
+		t.Fatalf("delete item: %v", err)
+	}
+	expectBump("delete")
+}
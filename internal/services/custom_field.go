@@ -0,0 +1,461 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+	"golang_server/pkg/validator"
+
+	"gorm.io/gorm"
+)
+
+// CustomFieldService интерфейс для сервиса пользовательских полей
+type CustomFieldService interface {
+	CreateField(userID, projectID uint, req models.CreateCustomFieldRequest) (*models.CustomField, error)
+	GetFields(userID, projectID uint) ([]models.CustomField, error)
+	UpdateField(userID, projectID, fieldID uint, req models.UpdateCustomFieldRequest) (*models.CustomField, error)
+	DeleteField(userID, projectID, fieldID uint) error
+	BuildValues(projectID *uint, input map[string]interface{}, creating bool) ([]uint, []models.CustomFieldValue, error)
+	BuildQuery(userID uint, params *models.TaskQueryParams) error
+	WithTx(tx *gorm.DB) CustomFieldService
+}
+
+// customFieldService реализация сервиса пользовательских полей
+type customFieldService struct {
+	customFieldRepo repository.CustomFieldRepository
+	projectRepo     repository.ProjectRepository
+	userRepo        repository.UserRepository
+}
+
+// NewCustomFieldService создает новый сервис пользовательских полей
+func NewCustomFieldService(customFieldRepo repository.CustomFieldRepository, projectRepo repository.ProjectRepository, userRepo repository.UserRepository) CustomFieldService {
+	return &customFieldService{
+		customFieldRepo: customFieldRepo,
+		projectRepo:     projectRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// WithTx возвращает сервис, работающий в транзакции tx
+func (s *customFieldService) WithTx(tx *gorm.DB) CustomFieldService {
+	txService := *s
+	txService.customFieldRepo = s.customFieldRepo.WithTx(tx)
+	txService.projectRepo = s.projectRepo.WithTx(tx)
+	txService.userRepo = s.userRepo.WithTx(tx)
+	return &txService
+}
+
+// CreateField создает пользовательское поле проекта
+func (s *customFieldService) CreateField(userID, projectID uint, req models.CreateCustomFieldRequest) (*models.CustomField, error) {
+	if err := s.checkProject(userID, projectID); err != nil {
+		return nil, err
+	}
+
+	if !validator.IsValidFieldKey(req.Key) {
+		return nil, errors.New("invalid custom field: malformed key " + req.Key)
+	}
+	options, err := normalizeOptions(req.Type, req.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.customFieldRepo.GetByKey(userID, req.Key, &projectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, errors.New("custom field with this key already exists")
+	}
+
+	field := &models.CustomField{
+		ProjectID: projectID,
+		Key:       req.Key,
+		Name:      req.Name,
+		Type:      req.Type,
+		Options:   options,
+		Required:  req.Required,
+	}
+
+	if err := s.customFieldRepo.Create(field); err != nil {
+		return nil, err
+	}
+
+	return field, nil
+}
+
+// GetFields получает пользовательские поля проекта
+func (s *customFieldService) GetFields(userID, projectID uint) ([]models.CustomField, error) {
+	if err := s.checkProject(userID, projectID); err != nil {
+		return nil, err
+	}
+	return s.customFieldRepo.GetByProjectID(projectID)
+}
+
+// UpdateField обновляет пользовательское поле проекта
+func (s *customFieldService) UpdateField(userID, projectID, fieldID uint, req models.UpdateCustomFieldRequest) (*models.CustomField, error) {
+	field, err := s.getField(userID, projectID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		field.Name = *req.Name
+	}
+	if req.Required != nil {
+		field.Required = *req.Required
+	}
+	if req.Options != nil {
+		options, err := normalizeOptions(field.Type, req.Options)
+		if err != nil {
+			return nil, err
+		}
+
+		// Нельзя удалить вариант, который уже выбран в задачах
+		var removed []string
+		for _, old := range field.Options {
+			kept := false
+			for _, option := range options {
+				if option == old {
+					kept = true
+					break
+				}
+			}
+			if !kept {
+				removed = append(removed, old)
+			}
+		}
+		if len(removed) > 0 {
+			count, err := s.customFieldRepo.CountValuesWithOptions(field.ID, removed)
+			if err != nil {
+				return nil, err
+			}
+			if count > 0 {
+				return nil, errors.New("option is in use by tasks")
+			}
+		}
+		field.Options = options
+	}
+
+	if err := s.customFieldRepo.Update(field); err != nil {
+		return nil, err
+	}
+
+	return field, nil
+}
+
+// DeleteField удаляет пользовательское поле вместе со значениями в задачах
+func (s *customFieldService) DeleteField(userID, projectID, fieldID uint) error {
+	field, err := s.getField(userID, projectID, fieldID)
+	if err != nil {
+		return err
+	}
+	return s.customFieldRepo.Delete(field.ID)
+}
+
+// BuildValues проверяет значения пользовательских полей из запроса и готовит их к сохранению.
+// Возвращает ID полей, значения которых нужно заменить, и новые значения.
+// При создании задачи проверяется наличие всех обязательных полей.
+func (s *customFieldService) BuildValues(projectID *uint, input map[string]interface{}, creating bool) ([]uint, []models.CustomFieldValue, error) {
+	if projectID == nil {
+		if len(input) > 0 {
+			return nil, nil, errors.New("invalid custom field: custom fields require a project")
+		}
+		return nil, nil, nil
+	}
+
+	fields, err := s.customFieldRepo.GetByProjectID(*projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byKey := make(map[string]*models.CustomField, len(fields))
+	for i := range fields {
+		byKey[fields[i].Key] = &fields[i]
+	}
+
+	for key := range input {
+		if _, ok := byKey[key]; !ok {
+			return nil, nil, errors.New("invalid custom field: unknown field " + key)
+		}
+	}
+
+	var fieldIDs []uint
+	var values []models.CustomFieldValue
+	for i := range fields {
+		field := &fields[i]
+		raw, present := input[field.Key]
+		if !present {
+			if creating && field.Required {
+				return nil, nil, errors.New("invalid custom field: " + field.Key + " is required")
+			}
+			continue
+		}
+
+		if raw == nil {
+			if field.Required {
+				return nil, nil, errors.New("invalid custom field: " + field.Key + " is required")
+			}
+			fieldIDs = append(fieldIDs, field.ID)
+			continue
+		}
+
+		fieldValues, err := s.parseValue(field, raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid custom field: %s: %w", field.Key, err)
+		}
+		fieldIDs = append(fieldIDs, field.ID)
+		values = append(values, fieldValues...)
+	}
+
+	return fieldIDs, values, nil
+}
+
+// BuildQuery переводит фильтры cf[<ключ>] и сортировку cf.<ключ> в условия для репозитория.
+// Значения числовых полей и дат могут начинаться с оператора сравнения: >, >=, <, <=.
+func (s *customFieldService) BuildQuery(userID uint, params *models.TaskQueryParams) error {
+	for key, raw := range params.CustomFields {
+		fields, err := s.customFieldRepo.GetByKey(userID, key, params.ProjectID)
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			return errors.New("invalid custom field: unknown field " + key)
+		}
+
+		// Одинаковый ключ в разных проектах должен иметь один тип
+		field := fields[0]
+		ids := make([]uint, len(fields))
+		for i, f := range fields {
+			if f.Type != field.Type {
+				return errors.New("invalid custom field: " + key + " has different types across projects, filter by project_id")
+			}
+			ids[i] = f.ID
+		}
+
+		operator, literal := "=", raw
+		if field.Type == models.CustomFieldTypeNumber || field.Type == models.CustomFieldTypeDate {
+			for _, op := range []string{">=", "<=", ">", "<"} {
+				if strings.HasPrefix(raw, op) {
+					operator, literal = op, strings.TrimSpace(raw[len(op):])
+					break
+				}
+			}
+		}
+
+		var value interface{}
+		switch field.Type {
+		case models.CustomFieldTypeNumber:
+			number, err := strconv.ParseFloat(literal, 64)
+			if err != nil {
+				return errors.New("invalid custom field: " + key + ": expected a number")
+			}
+			value = number
+		case models.CustomFieldTypeDate:
+			date, err := parseDateValue(literal)
+			if err != nil {
+				return errors.New("invalid custom field: " + key + ": expected a date")
+			}
+			value = date
+		case models.CustomFieldTypeUser:
+			id, err := strconv.ParseUint(literal, 10, 32)
+			if err != nil {
+				return errors.New("invalid custom field: " + key + ": expected a user ID")
+			}
+			value = uint(id)
+		default:
+			value = literal
+		}
+
+		params.CustomFieldFilters = append(params.CustomFieldFilters, models.CustomFieldFilter{
+			FieldIDs: ids,
+			Column:   field.Type.ValueColumn(),
+			Operator: operator,
+			Value:    value,
+		})
+	}
+
+	for i, sort := range params.SortFields {
+		key, ok := strings.CutPrefix(sort.Field, "cf.")
+		if !ok {
+			continue
+		}
+
+		fields, err := s.customFieldRepo.GetByKey(userID, key, params.ProjectID)
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			return errors.New("invalid custom field: unknown field " + key)
+		}
+
+		ids := make([]uint, len(fields))
+		for j, f := range fields {
+			if f.Type != fields[0].Type {
+				return errors.New("invalid custom field: " + key + " has different types across projects, filter by project_id")
+			}
+			ids[j] = f.ID
+		}
+
+		params.SortFields[i].CustomField = &models.CustomFieldSort{
+			FieldIDs: ids,
+			Column:   fields[0].Type.ValueColumn(),
+		}
+	}
+
+	return nil
+}
+
+// parseValue проверяет значение поля и переводит его в строки CustomFieldValue
+func (s *customFieldService) parseValue(field *models.CustomField, raw interface{}) ([]models.CustomFieldValue, error) {
+	value := models.CustomFieldValue{FieldID: field.ID}
+
+	switch field.Type {
+	case models.CustomFieldTypeText:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("expected a string")
+		}
+		if len(text) > 1000 {
+			return nil, errors.New("text is too long")
+		}
+		value.TextValue = &text
+
+	case models.CustomFieldTypeNumber:
+		number, ok := raw.(float64)
+		if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, errors.New("expected a number")
+		}
+		value.NumberValue = &number
+
+	case models.CustomFieldTypeDate:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("expected a date string")
+		}
+		date, err := parseDateValue(text)
+		if err != nil {
+			return nil, errors.New("expected a date in YYYY-MM-DD or RFC 3339 format")
+		}
+		value.DateValue = &date
+
+	case models.CustomFieldTypeSelect:
+		option, ok := raw.(string)
+		if !ok || !field.HasOption(option) {
+			return nil, errors.New("expected one of the field options")
+		}
+		value.TextValue = &option
+
+	case models.CustomFieldTypeMultiSelect:
+		list, ok := raw.([]interface{})
+		if !ok {
+			return nil, errors.New("expected a list of field options")
+		}
+		seen := make(map[string]bool, len(list))
+		values := make([]models.CustomFieldValue, 0, len(list))
+		for _, item := range list {
+			option, ok := item.(string)
+			if !ok || !field.HasOption(option) {
+				return nil, errors.New("expected a list of field options")
+			}
+			if seen[option] {
+				continue
+			}
+			seen[option] = true
+			values = append(values, models.CustomFieldValue{FieldID: field.ID, TextValue: &option})
+		}
+		return values, nil
+
+	case models.CustomFieldTypeUser:
+		number, ok := raw.(float64)
+		if !ok || number <= 0 || number != math.Trunc(number) {
+			return nil, errors.New("expected a user ID")
+		}
+		userID := uint(number)
+		if _, err := s.userRepo.GetByID(userID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("user not found")
+			}
+			return nil, err
+		}
+		value.UserValue = &userID
+	}
+
+	return []models.CustomFieldValue{value}, nil
+}
+
+// checkProject проверяет, что проект существует и принадлежит пользователю
+func (s *customFieldService) checkProject(userID, projectID uint) error {
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("project not found")
+		}
+		return err
+	}
+	if project.UserID != userID {
+		return errors.New("access denied")
+	}
+	return nil
+}
+
+// getField получает поле проекта и проверяет доступ к проекту
+func (s *customFieldService) getField(userID, projectID, fieldID uint) (*models.CustomField, error) {
+	if err := s.checkProject(userID, projectID); err != nil {
+		return nil, err
+	}
+
+	field, err := s.customFieldRepo.GetByID(fieldID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("custom field not found")
+		}
+		return nil, err
+	}
+	if field.ProjectID != projectID {
+		return nil, errors.New("custom field not found")
+	}
+
+	return field, nil
+}
+
+// normalizeOptions проверяет варианты выбора с учетом типа поля
+func normalizeOptions(fieldType models.CustomFieldType, options []string) ([]string, error) {
+	if !fieldType.HasOptions() {
+		if len(options) > 0 {
+			return nil, errors.New("invalid custom field: options are only allowed for select fields")
+		}
+		return nil, nil
+	}
+
+	if len(options) == 0 {
+		return nil, errors.New("invalid custom field: select fields require options")
+	}
+
+	seen := make(map[string]bool, len(options))
+	for _, option := range options {
+		if seen[option] {
+			return nil, errors.New("invalid custom field: duplicate option " + option)
+		}
+		seen[option] = true
+	}
+	return options, nil
+}
+
+// parseDateValue разбирает дату в формате YYYY-MM-DD или RFC 3339
+func parseDateValue(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// Храним даты в UTC, чтобы сравнение в базе не зависело от смещения
+	return date.UTC(), nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrIdempotencyKeyReused возвращается, если ключ уже использован с другим запросом
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used with a different request")
+	// ErrIdempotencyInProgress возвращается, пока запрос с тем же ключом еще выполняется
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is in progress")
+)
+
+// IdempotencyService интерфейс для сервиса ключей идемпотентности
+type IdempotencyService interface {
+	Begin(userID uint, key, fingerprint string) (*models.IdempotencyKey, bool, error)
+	Complete(record *models.IdempotencyKey, response models.IdempotentResponse) error
+	Release(record *models.IdempotencyKey) error
+	PurgeExpired(now time.Time) (int64, error)
+}
+
+// idempotencyService реализация сервиса ключей идемпотентности
+type idempotencyService struct {
+	idempotencyRepo repository.IdempotencyKeyRepository
+	ttl             time.Duration
+}
+
+// NewIdempotencyService создает новый сервис ключей идемпотентности
+func NewIdempotencyService(idempotencyRepo repository.IdempotencyKeyRepository, ttl time.Duration) IdempotencyService {
+	return &idempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+	}
+}
+
+// Begin резервирует ключ для нового запроса. Если ключ уже использован, возвращает
+// сохраненную запись и replay = true для повтора завершенного запроса.
+// Одновременные запросы с одним ключом разрешаются уникальным индексом:
+// выполняется только тот, кто первым сохранил ключ.
+func (s *idempotencyService) Begin(userID uint, key, fingerprint string) (*models.IdempotencyKey, bool, error) {
+	now := time.Now()
+
+	for attempt := 0; attempt < 2; attempt++ {
+		record := &models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Fingerprint: fingerprint,
+			ExpiresAt:   now.Add(s.ttl).Unix(),
+		}
+		err := s.idempotencyRepo.Create(record)
+		if err == nil {
+			return record, false, nil
+		}
+		if !isUniqueViolation(err) {
+			return nil, false, err
+		}
+
+		existing, err := s.idempotencyRepo.GetByKey(userID, key)
+		if err != nil {
+			// Ключ удалили между вставкой и чтением: пробуем сохранить его снова
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return nil, false, err
+		}
+
+		// Просроченный ключ освобождается и резервируется заново
+		if existing.ExpiresAt <= now.Unix() {
+			if err := s.idempotencyRepo.Delete(existing.ID); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+
+		if existing.Fingerprint != fingerprint {
+			return nil, false, ErrIdempotencyKeyReused
+		}
+		if !existing.Completed {
+			return nil, false, ErrIdempotencyInProgress
+		}
+		return existing, true, nil
+	}
+
+	return nil, false, ErrIdempotencyInProgress
+}
+
+// Complete сохраняет ответ на запрос для последующих повторов
+func (s *idempotencyService) Complete(record *models.IdempotencyKey, response models.IdempotentResponse) error {
+	record.Completed = true
+	record.StatusCode = response.StatusCode
+	record.ContentType = response.ContentType
+	record.ETag = response.ETag
+	record.Location = response.Location
+	record.Link = response.Link
+	record.Body = response.Body
+	return s.idempotencyRepo.Update(record)
+}
+
+// Release освобождает ключ, чтобы запрос можно было повторить (например, после ошибки сервера)
+func (s *idempotencyService) Release(record *models.IdempotencyKey) error {
+	return s.idempotencyRepo.Delete(record.ID)
+}
+
+// PurgeExpired удаляет ключи с истекшим сроком хранения
+func (s *idempotencyService) PurgeExpired(now time.Time) (int64, error) {
+	return s.idempotencyRepo.DeleteExpired(now.Unix())
+}
+
+// isUniqueViolation проверяет, что ошибка вызвана нарушением уникального индекса
+func isUniqueViolation(err error) bool {
+	return errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
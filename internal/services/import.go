@@ -0,0 +1,482 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxImportErrors число ошибок по записям, возвращаемых в отчете
+	maxImportErrors = 100
+	// maxImportLineSize максимальная длина строки файла NDJSON
+	maxImportLineSize = 1 << 20
+)
+
+// ImportService интерфейс для сервиса загрузки задач из файлов
+type ImportService interface {
+	ImportTasks(userID uint, req models.TaskImportRequest, file io.Reader) (*models.ImportReport, *models.ImportJob, error)
+	GetJob(userID, jobID uint) (*models.ImportJob, error)
+	FailInterruptedJobs() (int64, error)
+}
+
+// importService реализация сервиса загрузки задач
+type importService struct {
+	taskService   TaskService
+	importJobRepo repository.ImportJobRepository
+
+	// maxRows максимальное число записей в файле
+	maxRows int
+	// asyncRows число записей, начиная с которого загрузка выполняется в фоне
+	asyncRows int
+
+	// progress число обработанных записей выполняющихся фоновых загрузок. Ход проверки
+	// записей хранится только в памяти, ход создания задач сохраняется и в базу после
+	// каждой порции.
+	mu       sync.Mutex
+	progress map[uint]int
+}
+
+// NewImportService создает новый сервис загрузки задач
+func NewImportService(taskService TaskService, importJobRepo repository.ImportJobRepository, maxRows, asyncRows int) ImportService {
+	return &importService{
+		taskService:   taskService,
+		importJobRepo: importJobRepo,
+		maxRows:       maxRows,
+		asyncRows:     asyncRows,
+		progress:      make(map[uint]int),
+	}
+}
+
+// ImportTasks разбирает файл и загружает из него задачи. Небольшой файл обрабатывается
+// сразу и возвращается отчет; для файла из asyncRows и более записей создается фоновая
+// загрузка, ход которой можно получить через GetJob.
+func (s *importService) ImportTasks(userID uint, req models.TaskImportRequest, file io.Reader) (*models.ImportReport, *models.ImportJob, error) {
+	if err := validateImportMapping(req.Mapping); err != nil {
+		return nil, nil, err
+	}
+
+	records, ignored, err := readImportRecords(req.Format, req.Mapping, file, s.maxRows)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("invalid import: file contains no records")
+	}
+
+	if s.asyncRows <= 0 || len(records) < s.asyncRows {
+		report, err := s.runImport(userID, req, records, ignored, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return report, nil, nil
+	}
+
+	job := &models.ImportJob{
+		UserID: userID,
+		Format: req.Format,
+		DryRun: req.DryRun,
+		Status: models.ImportJobPending,
+		Total:  len(records),
+	}
+	if err := s.importJobRepo.Create(job); err != nil {
+		return nil, nil, err
+	}
+	s.setProgress(job.ID, 0)
+
+	go s.runJob(*job, userID, req, records, ignored)
+
+	return nil, job, nil
+}
+
+// GetJob получает фоновую загрузку пользователя с текущим ходом выполнения
+func (s *importService) GetJob(userID, jobID uint) (*models.ImportJob, error) {
+	job, err := s.importJobRepo.GetByID(jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("import job not found")
+		}
+		return nil, err
+	}
+
+	if job.UserID != userID {
+		return nil, errors.New("access denied")
+	}
+
+	if !job.Status.IsFinished() {
+		s.mu.Lock()
+		if processed, ok := s.progress[job.ID]; ok {
+			job.Processed = processed
+		}
+		s.mu.Unlock()
+	}
+
+	return job, nil
+}
+
+// FailInterruptedJobs отмечает неудавшимися фоновые загрузки, прерванные остановкой сервера
+func (s *importService) FailInterruptedJobs() (int64, error) {
+	return s.importJobRepo.FailUnfinished("import was interrupted by a server restart")
+}
+
+// runJob выполняет фоновую загрузку и сохраняет ее результат. Паника при загрузке
+// отмечает загрузку неудавшейся и не останавливает сервер.
+func (s *importService) runJob(job models.ImportJob, userID uint, req models.TaskImportRequest, records []models.ImportRecord, ignored []string) {
+	defer s.clearProgress(job.ID)
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Import job %d: panic: %v\n%s", job.ID, r, debug.Stack())
+			now := time.Now().UTC()
+			job.FinishedAt = &now
+			job.Status = models.ImportJobFailed
+			job.Error = "import failed with an internal error"
+			job.Processed = 0
+			if err := s.importJobRepo.Update(&job); err != nil {
+				log.Printf("Import job %d: failed to save result: %v", job.ID, err)
+			}
+		}
+	}()
+
+	report, err := s.runImport(userID, req, records, ignored, &job)
+
+	now := time.Now().UTC()
+	job.FinishedAt = &now
+	job.Processed = job.Total
+	job.Report = report
+	switch {
+	case err != nil:
+		job.Status = models.ImportJobFailed
+		job.Error = err.Error()
+		job.Processed = 0
+	case !req.DryRun && report.Invalid > 0:
+		job.Status = models.ImportJobFailed
+		job.Error = "import contains invalid records"
+	default:
+		job.Status = models.ImportJobCompleted
+	}
+
+	if err := s.importJobRepo.Update(&job); err != nil {
+		log.Printf("Import job %d: failed to save result: %v", job.ID, err)
+	}
+}
+
+// runImport проверяет записи и, если ошибок нет и это не пробный запуск, создает задачи.
+// Для фоновой загрузки job отмечается смена этапа и ход выполнения.
+func (s *importService) runImport(userID uint, req models.TaskImportRequest, records []models.ImportRecord, ignored []string, job *models.ImportJob) (*models.ImportReport, error) {
+	report := &models.ImportReport{
+		DryRun:         req.DryRun,
+		Total:          len(records),
+		Errors:         []models.ImportRowError{},
+		IgnoredColumns: ignored,
+	}
+
+	if err := s.startStage(job, models.ImportJobValidating); err != nil {
+		return nil, err
+	}
+	items, rowErrors, err := s.taskService.PrepareImport(userID, req, records, s.progressFunc(job))
+	if err != nil {
+		return nil, err
+	}
+
+	report.Valid = len(items)
+	report.Invalid = len(records) - len(items)
+	report.ErrorsTotal = len(rowErrors)
+	if len(rowErrors) > maxImportErrors {
+		rowErrors = rowErrors[:maxImportErrors]
+	}
+	report.Errors = append(report.Errors, rowErrors...)
+
+	if req.DryRun || report.Invalid > 0 {
+		return report, nil
+	}
+
+	if err := s.startStage(job, models.ImportJobImporting); err != nil {
+		return nil, err
+	}
+	// Задачи создаются в одной транзакции, поэтому ход загрузки отмечается только в памяти
+	report.Created, err = s.taskService.ImportTasks(userID, items, s.progressFunc(job))
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// startStage сохраняет новый этап фоновой загрузки и сбрасывает ее ход
+func (s *importService) startStage(job *models.ImportJob, status models.ImportJobStatus) error {
+	if job == nil {
+		return nil
+	}
+	job.Status = status
+	job.Processed = 0
+	s.setProgress(job.ID, 0)
+	return s.importJobRepo.Update(job)
+}
+
+// progressFunc возвращает функцию, отмечающую ход фоновой загрузки
+func (s *importService) progressFunc(job *models.ImportJob) func(int) {
+	if job == nil {
+		return func(int) {}
+	}
+	return func(processed int) {
+		s.setProgress(job.ID, processed)
+	}
+}
+
+// setProgress запоминает число обработанных записей загрузки
+func (s *importService) setProgress(jobID uint, processed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.progress[jobID] = processed
+}
+
+// clearProgress удаляет ход завершенной загрузки
+func (s *importService) clearProgress(jobID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.progress, jobID)
+}
+
+// validateImportMapping проверяет, что колонки сопоставлены известным полям задачи
+// и каждое поле заполняется не более чем одной колонкой. Пустое поле пропускает колонку.
+func validateImportMapping(mapping map[string]string) error {
+	sources := make(map[string]string, len(mapping))
+	for column, field := range mapping {
+		if field == "" {
+			continue
+		}
+		if !isImportField(field) {
+			return fmt.Errorf("invalid mapping: unknown field %q for column %q", field, column)
+		}
+		if other, ok := sources[field]; ok {
+			return fmt.Errorf("invalid mapping: field %q is mapped from both %q and %q", field, other, column)
+		}
+		sources[field] = column
+	}
+	return nil
+}
+
+// importMapper определяет поле задачи для колонки файла: по соответствию из запроса
+// или по совпадению имени колонки с полем, если поле не занято соответствием
+type importMapper struct {
+	mapping map[string]string
+	mapped  map[string]bool
+	ignored map[string]bool
+}
+
+// newImportMapper создает сопоставление колонок по проверенному соответствию
+func newImportMapper(mapping map[string]string) *importMapper {
+	mapped := make(map[string]bool, len(mapping))
+	for _, field := range mapping {
+		mapped[field] = true
+	}
+	return &importMapper{
+		mapping: mapping,
+		mapped:  mapped,
+		ignored: make(map[string]bool),
+	}
+}
+
+// field возвращает поле задачи для колонки или пустую строку, если колонка не загружается
+func (m *importMapper) field(column string) string {
+	if field, ok := m.mapping[column]; ok {
+		return field
+	}
+	if isImportField(column) && !m.mapped[column] {
+		return column
+	}
+	m.ignored[column] = true
+	return ""
+}
+
+// apply переводит объект записи JSON в значения полей задачи. Вложенный объект
+// custom_fields, как в выгрузке, раскрывается в поля cf.<ключ>.
+func (m *importMapper) apply(object map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(object))
+	for column, value := range object {
+		if _, mapped := m.mapping[column]; column == "custom_fields" && !mapped {
+			continue
+		}
+		if field := m.field(column); field != "" {
+			values[field] = value
+		}
+	}
+
+	if _, mapped := m.mapping["custom_fields"]; !mapped && object["custom_fields"] != nil {
+		nested, ok := object["custom_fields"].(map[string]interface{})
+		if !ok {
+			m.ignored["custom_fields"] = true
+			return values
+		}
+		for key, value := range nested {
+			field := "cf." + key
+			if _, set := values[field]; !set && !m.mapped[field] {
+				values[field] = value
+			}
+		}
+	}
+	return values
+}
+
+// ignoredColumns возвращает незагружаемые колонки по алфавиту
+func (m *importMapper) ignoredColumns() []string {
+	columns := make([]string, 0, len(m.ignored))
+	for column := range m.ignored {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// readImportRecords читает записи файла в формате format и применяет к ним соответствие колонок
+func readImportRecords(format models.ImportFormat, mapping map[string]string, file io.Reader, maxRows int) ([]models.ImportRecord, []string, error) {
+	mapper := newImportMapper(mapping)
+
+	var records []models.ImportRecord
+	var err error
+	switch format {
+	case models.ImportFormatCSV:
+		records, err = readImportCSV(mapper, file, maxRows)
+	case models.ImportFormatJSON:
+		records, err = readImportJSON(mapper, file, maxRows)
+	case models.ImportFormatNDJSON:
+		records, err = readImportNDJSON(mapper, file, maxRows)
+	default:
+		err = errors.New("invalid import: unknown format, expected csv, json or ndjson")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return records, mapper.ignoredColumns(), nil
+}
+
+// readImportCSV читает CSV с заголовком; названия колонок берутся из первой строки
+func readImportCSV(mapper *importMapper, file io.Reader, maxRows int) ([]models.ImportRecord, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid import: %w", err)
+	}
+
+	// Таблицы часто сохраняют CSV с меткой порядка байтов в начале
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	fields := make([]string, len(header))
+	for i, column := range header {
+		fields[i] = mapper.field(strings.TrimSpace(column))
+	}
+
+	var records []models.ImportRecord
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid import: %w", err)
+		}
+		if len(records) == maxRows {
+			return nil, fmt.Errorf("invalid import: file contains more than %d records", maxRows)
+		}
+
+		record := models.ImportRecord{Row: len(records) + 1}
+		if len(row) != len(header) {
+			record.Error = fmt.Sprintf("expected %d columns, got %d", len(header), len(row))
+		} else {
+			record.Values = make(map[string]interface{}, len(row))
+			for i, value := range row {
+				if fields[i] != "" {
+					record.Values[fields[i]] = value
+				}
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// readImportJSON читает JSON-массив объектов, разбирая его по одному элементу
+func readImportJSON(mapper *importMapper, file io.Reader, maxRows int) ([]models.ImportRecord, error) {
+	decoder := json.NewDecoder(file)
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if delim, ok := token.(json.Delim); err != nil || !ok || delim != '[' {
+		return nil, errors.New("invalid import: expected a JSON array of objects")
+	}
+
+	var records []models.ImportRecord
+	for decoder.More() {
+		if len(records) == maxRows {
+			return nil, fmt.Errorf("invalid import: file contains more than %d records", maxRows)
+		}
+
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("invalid import: record %d: %w", len(records)+1, err)
+		}
+		records = append(records, importJSONRecord(mapper, len(records)+1, value))
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("invalid import: %w", err)
+	}
+	return records, nil
+}
+
+// readImportNDJSON читает объекты JSON по одному в строке; пустые строки пропускаются.
+// Строка с неверным JSON отмечается ошибкой записи, остальные строки читаются дальше.
+func readImportNDJSON(mapper *importMapper, file io.Reader, maxRows int) ([]models.ImportRecord, error) {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+
+	var records []models.ImportRecord
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if len(records) == maxRows {
+			return nil, fmt.Errorf("invalid import: file contains more than %d records", maxRows)
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(line, &value); err != nil {
+			records = append(records, models.ImportRecord{Row: len(records) + 1, Error: "invalid JSON: " + err.Error()})
+			continue
+		}
+		records = append(records, importJSONRecord(mapper, len(records)+1, value))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid import: %w", err)
+	}
+	return records, nil
+}
+
+// importJSONRecord переводит элемент JSON в запись; элемент должен быть объектом
+func importJSONRecord(mapper *importMapper, row int, value interface{}) models.ImportRecord {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return models.ImportRecord{Row: row, Error: "expected a JSON object"}
+	}
+	return models.ImportRecord{Row: row, Values: mapper.apply(object)}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+)
+
+// importItems готовит n проверенных записей загрузки
+func importItems(n int) []models.TaskImportItem {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	items := make([]models.TaskImportItem, n)
+	for i := range items {
+		items[i] = models.TaskImportItem{
+			Row: i + 1,
+			Request: models.CreateTaskRequest{
+				Title:     "Imported",
+				StartDate: day,
+				EndDate:   day,
+			},
+		}
+	}
+	return items
+}
+
+func TestImportTasksReportsProgress(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	total := importProgressInterval*2 + 50
+	var progress []int
+	var visible int64
+	created, err := env.taskService.ImportTasks(userID, importItems(total), func(n int) {
+		progress = append(progress, n)
+		// Другие соединения не видят задачи до завершения загрузки
+		var count int64
+		env.db.Model(&models.Task{}).Count(&count)
+		visible += count
+	})
+	if err != nil {
+		t.Fatalf("import tasks: %v", err)
+	}
+	if created != total {
+		t.Fatalf("created = %d, want %d", created, total)
+	}
+
+	if visible != 0 {
+		t.Errorf("%d tasks were visible while the import was running", visible)
+	}
+
+	want := []int{importProgressInterval, importProgressInterval * 2, total}
+	if len(progress) != len(want) {
+		t.Fatalf("progress = %v, want %v", progress, want)
+	}
+	for i := range want {
+		if progress[i] != want[i] {
+			t.Fatalf("progress = %v, want %v", progress, want)
+		}
+	}
+}
+
+func TestImportTasksRollsBackOnFailure(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	items := importItems(importProgressInterval*2 + 10)
+	missingParent := uint(100000)
+	items[len(items)-1].Request.ParentID = &missingParent
+
+	_, err := env.taskService.ImportTasks(userID, items, func(int) {})
+	if err == nil || !strings.HasPrefix(err.Error(), "record 410:") {
+		t.Fatalf("import error = %v, want a failure in record 410", err)
+	}
+
+	var left int64
+	env.db.Unscoped().Model(&models.Task{}).Count(&left)
+	if left != 0 {
+		t.Errorf("%d tasks left after the failed import, want none", left)
+	}
+	var revisions int64
+	env.db.Model(&models.TaskRevision{}).Count(&revisions)
+	if revisions != 0 {
+		t.Errorf("%d revisions left after the failed import, want none", revisions)
+	}
+}
+
+// panickingTaskService падает при проверке записей загрузки
+type panickingTaskService struct {
+	TaskService
+}
+
+func (panickingTaskService) PrepareImport(uint, models.TaskImportRequest, []models.ImportRecord, func(int)) ([]models.TaskImportItem, []models.ImportRowError, error) {
+	panic("unexpected record")
+}
+
+func TestImportJobPanicMarksJobFailed(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	importService := NewImportService(panickingTaskService{}, repository.NewImportJobRepository(env.db), 100, 1)
+	file := strings.NewReader("title,start_date,end_date\nFirst,2026-10-19,2026-10-19\n")
+	_, job, err := importService.ImportTasks(userID, models.TaskImportRequest{Format: models.ImportFormatCSV}, file)
+	if err != nil {
+		t.Fatalf("import tasks: %v", err)
+	}
+	if job == nil {
+		t.Fatal("expected a background import job")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err = importService.GetJob(userID, job.ID)
+		if err != nil {
+			t.Fatalf("get job: %v", err)
+		}
+		if job.Status.IsFinished() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job is still %s", job.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if job.Status != models.ImportJobFailed || job.Error == "" || job.FinishedAt == nil {
+		t.Errorf("job = %+v, want a finished failed job with an error", job)
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// NotificationService интерфейс для сервиса уведомлений
+type NotificationService interface {
+	GetNotifications(userID uint, params *models.NotificationParams) ([]models.NotificationResponse, int64, int64, error)
+	MarkRead(userID, notificationID uint) error
+	MarkAllRead(userID uint) (int64, error)
+	GetPreferences(userID uint) (*models.NotificationPreferencesResponse, error)
+	UpdatePreferences(userID uint, req models.UpdateNotificationPreferencesRequest) (*models.NotificationPreferencesResponse, error)
+}
+
+// notificationService реализация сервиса уведомлений
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+// NewNotificationService создает новый сервис уведомлений
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+// GetNotifications получает уведомления пользователя и число непрочитанных.
+// Параметры страницы приводятся к значениям по умолчанию на месте.
+func (s *notificationService) GetNotifications(userID uint, params *models.NotificationParams) ([]models.NotificationResponse, int64, int64, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	notifications, total, err := s.notificationRepo.GetByUserID(userID, *params)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	notificationResponses := make([]models.NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		notificationResponses[i] = notification.ToResponse()
+	}
+
+	return notificationResponses, total, unread, nil
+}
+
+// MarkRead отмечает уведомление прочитанным
+func (s *notificationService) MarkRead(userID, notificationID uint) error {
+	notification, err := s.notificationRepo.GetByID(notificationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("notification not found")
+		}
+		return err
+	}
+
+	// Проверяем, что уведомление адресовано пользователю
+	if notification.UserID != userID {
+		return errors.New("access denied")
+	}
+
+	return s.notificationRepo.MarkRead(notification.ID)
+}
+
+// MarkAllRead отмечает прочитанными все уведомления пользователя
+func (s *notificationService) MarkAllRead(userID uint) (int64, error) {
+	return s.notificationRepo.MarkAllRead(userID)
+}
+
+// GetPreferences получает настройки уведомлений пользователя
+func (s *notificationService) GetPreferences(userID uint) (*models.NotificationPreferencesResponse, error) {
+	preference, err := s.getPreference(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferenceResponse := preference.ToResponse()
+	return &preferenceResponse, nil
+}
+
+// UpdatePreferences изменяет настройки уведомлений пользователя
+func (s *notificationService) UpdatePreferences(userID uint, req models.UpdateNotificationPreferencesRequest) (*models.NotificationPreferencesResponse, error) {
+	preference, err := s.getPreference(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[models.TaskAction]bool, len(models.NotificationEvents))
+	for _, event := range models.NotificationEvents {
+		known[event] = true
+	}
+	for event := range req.Events {
+		if !known[event] {
+			return nil, fmt.Errorf("invalid preferences: unknown event %q", event)
+		}
+	}
+
+	// Список отключенных событий строится в порядке NotificationEvents
+	disabled := []models.TaskAction{}
+	for _, event := range models.NotificationEvents {
+		enabled, ok := req.Events[event]
+		if !ok {
+			enabled = preference.Enabled(event)
+		}
+		if !enabled {
+			disabled = append(disabled, event)
+		}
+	}
+	preference.DisabledEvents = disabled
+	if req.OwnChanges != nil {
+		preference.OwnChanges = *req.OwnChanges
+	}
+
+	if err := s.notificationRepo.SavePreferences(preference); err != nil {
+		return nil, err
+	}
+
+	preferenceResponse := preference.ToResponse()
+	return &preferenceResponse, nil
+}
+
+// getPreference получает сохраненные настройки или настройки по умолчанию
+func (s *notificationService) getPreference(userID uint) (*models.NotificationPreference, error) {
+	preferences, err := s.notificationRepo.GetPreferences([]uint{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	preference, ok := preferences[userID]
+	if !ok {
+		preference = models.NotificationPreference{UserID: userID}
+	}
+	return &preference, nil
+}
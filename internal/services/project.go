@@ -0,0 +1,144 @@
+package services
+
+import (
+	"errors"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// ProjectService интерфейс для сервиса проектов
+type ProjectService interface {
+	CreateProject(userID uint, req models.CreateProjectRequest) (*models.ProjectResponse, error)
+	GetProjects(userID uint) ([]models.ProjectResponse, error)
+	GetProjectByID(userID, projectID uint) (*models.ProjectResponse, error)
+	UpdateProject(userID, projectID uint, req models.UpdateProjectRequest) (*models.ProjectResponse, error)
+	DeleteProject(userID, projectID uint) error
+}
+
+// projectService реализация сервиса проектов
+type projectService struct {
+	projectRepo     repository.ProjectRepository
+	workflowService WorkflowService
+}
+
+// NewProjectService создает новый сервис проектов
+func NewProjectService(projectRepo repository.ProjectRepository, workflowService WorkflowService) ProjectService {
+	return &projectService{
+		projectRepo:     projectRepo,
+		workflowService: workflowService,
+	}
+}
+
+// CreateProject создает новый проект
+func (s *projectService) CreateProject(userID uint, req models.CreateProjectRequest) (*models.ProjectResponse, error) {
+	var workflow *models.Workflow
+	var err error
+	if req.WorkflowID != nil {
+		workflow, err = s.workflowService.GetWorkflowByID(userID, *req.WorkflowID)
+	} else {
+		workflow, err = s.workflowService.GetDefaultWorkflow(userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	project := &models.Project{
+		Name:        req.Name,
+		Description: req.Description,
+		UserID:      userID,
+		WorkflowID:  workflow.ID,
+	}
+
+	if err := s.projectRepo.Create(project); err != nil {
+		return nil, err
+	}
+
+	projectResponse := project.ToResponse()
+	return &projectResponse, nil
+}
+
+// GetProjects получает проекты пользователя
+func (s *projectService) GetProjects(userID uint) ([]models.ProjectResponse, error) {
+	projects, err := s.projectRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectResponses := make([]models.ProjectResponse, len(projects))
+	for i, project := range projects {
+		projectResponses[i] = project.ToResponse()
+	}
+
+	return projectResponses, nil
+}
+
+// GetProjectByID получает проект по ID
+func (s *projectService) GetProjectByID(userID, projectID uint) (*models.ProjectResponse, error) {
+	project, err := s.getProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectResponse := project.ToResponse()
+	return &projectResponse, nil
+}
+
+// UpdateProject обновляет проект
+func (s *projectService) UpdateProject(userID, projectID uint, req models.UpdateProjectRequest) (*models.ProjectResponse, error) {
+	project, err := s.getProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		project.Name = *req.Name
+	}
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+
+	if err := s.projectRepo.Update(project); err != nil {
+		return nil, err
+	}
+
+	projectResponse := project.ToResponse()
+	return &projectResponse, nil
+}
+
+// DeleteProject удаляет проект без задач
+func (s *projectService) DeleteProject(userID, projectID uint) error {
+	project, err := s.getProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.projectRepo.CountTasks(project.ID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("project has tasks")
+	}
+
+	return s.projectRepo.Delete(project.ID)
+}
+
+// getProject получает проект и проверяет, что он принадлежит пользователю
+func (s *projectService) getProject(userID, projectID uint) (*models.Project, error) {
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("project not found")
+		}
+		return nil, err
+	}
+
+	if project.UserID != userID {
+		return nil, errors.New("access denied")
+	}
+
+	return project, nil
+}
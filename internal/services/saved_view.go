@@ -0,0 +1,328 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+	"golang_server/pkg/filter"
+
+	"gorm.io/gorm"
+)
+
+// savedViewColumns колонки списка задач, которые можно выбрать в представлении (кроме cf.<ключ>)
+var savedViewColumns = map[string]bool{
+	"id":                 true,
+	"title":              true,
+	"description":        true,
+	"status":             true,
+	"priority":           true,
+	"position":           true,
+	"start_date":         true,
+	"end_date":           true,
+	"project_id":         true,
+	"workflow_id":        true,
+	"parent_id":          true,
+	"tags":               true,
+	"completed_at":       true,
+	"version":            true,
+	"created_at":         true,
+	"updated_at":         true,
+	"total_time_seconds": true,
+}
+
+// SavedViewService интерфейс для сервиса сохраненных представлений
+type SavedViewService interface {
+	CreateView(userID uint, req models.CreateSavedViewRequest) (*models.SavedViewResponse, error)
+	GetViews(userID uint, projectID *uint) ([]models.SavedViewResponse, error)
+	GetView(userID, viewID uint) (*models.SavedViewResponse, error)
+	UpdateView(userID, viewID uint, req models.UpdateSavedViewRequest) (*models.SavedViewResponse, error)
+	DeleteView(userID, viewID uint) error
+	ApplyView(userID uint, view string, params *models.TaskQueryParams) (*models.SavedViewResponse, error)
+}
+
+// savedViewService реализация сервиса сохраненных представлений
+type savedViewService struct {
+	transactor    repository.Transactor
+	savedViewRepo repository.SavedViewRepository
+	projectRepo   repository.ProjectRepository
+}
+
+// NewSavedViewService создает новый сервис сохраненных представлений
+func NewSavedViewService(transactor repository.Transactor, savedViewRepo repository.SavedViewRepository, projectRepo repository.ProjectRepository) SavedViewService {
+	return &savedViewService{
+		transactor:    transactor,
+		savedViewRepo: savedViewRepo,
+		projectRepo:   projectRepo,
+	}
+}
+
+// CreateView создает представление
+func (s *savedViewService) CreateView(userID uint, req models.CreateSavedViewRequest) (*models.SavedViewResponse, error) {
+	view := &models.SavedView{
+		UserID:    userID,
+		ProjectID: req.ProjectID,
+		Name:      req.Name,
+		Shared:    req.Shared,
+		IsDefault: req.IsDefault,
+		Query:     req.Query,
+		Columns:   req.Columns,
+	}
+	if err := s.validateView(userID, view); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveView(view); err != nil {
+		return nil, err
+	}
+
+	viewResponse := view.ToResponse()
+	return &viewResponse, nil
+}
+
+// GetViews получает представления пользователя и открытые представления доступных ему проектов
+func (s *savedViewService) GetViews(userID uint, projectID *uint) ([]models.SavedViewResponse, error) {
+	views, err := s.savedViewRepo.GetVisible(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	viewResponses := make([]models.SavedViewResponse, len(views))
+	for i, view := range views {
+		viewResponses[i] = viewResponse(userID, &view)
+	}
+	return viewResponses, nil
+}
+
+// GetView получает представление по ID
+func (s *savedViewService) GetView(userID, viewID uint) (*models.SavedViewResponse, error) {
+	view, err := s.getVisibleView(userID, viewID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := viewResponse(userID, view)
+	return &response, nil
+}
+
+// UpdateView обновляет представление; изменять его может только автор
+func (s *savedViewService) UpdateView(userID, viewID uint, req models.UpdateSavedViewRequest) (*models.SavedViewResponse, error) {
+	view, err := s.getOwnView(userID, viewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		view.Name = *req.Name
+	}
+	if req.Shared != nil {
+		view.Shared = *req.Shared
+	}
+	if req.Query != nil {
+		view.Query = *req.Query
+	}
+	if req.Columns != nil {
+		view.Columns = *req.Columns
+	}
+
+	if req.IsDefault != nil {
+		view.IsDefault = *req.IsDefault
+	}
+
+	if err := s.validateView(userID, view); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveView(view); err != nil {
+		return nil, err
+	}
+
+	viewResponse := view.ToResponse()
+	return &viewResponse, nil
+}
+
+// DeleteView удаляет представление
+func (s *savedViewService) DeleteView(userID, viewID uint) error {
+	view, err := s.getOwnView(userID, viewID)
+	if err != nil {
+		return err
+	}
+	return s.savedViewRepo.Delete(view.ID)
+}
+
+// ApplyView дополняет параметры списка задач условиями представления.
+// view — ID представления; "none" отключает представление по умолчанию, которое
+// применяется, если в запросе нет ни фильтров, ни сортировки.
+// Параметры запроса имеют приоритет над представлением, а выражения фильтров объединяются.
+func (s *savedViewService) ApplyView(userID uint, view string, params *models.TaskQueryParams) (*models.SavedViewResponse, error) {
+	var savedView *models.SavedView
+
+	switch view {
+	case "none":
+		return nil, nil
+
+	case "":
+		if params.Status != "" || params.ProjectID != nil || params.Search != "" || params.Filter != "" ||
+			len(params.CustomFields) > 0 || params.Sort != "" || params.Order != "" {
+			return nil, nil
+		}
+		defaultView, err := s.savedViewRepo.GetDefault(userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		savedView = defaultView
+
+	default:
+		viewID, err := strconv.ParseUint(view, 10, 32)
+		if err != nil {
+			return nil, errors.New("invalid view: view must be an ID or none")
+		}
+		savedView, err = s.getVisibleView(userID, uint(viewID))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query := savedView.Query
+	if params.ProjectID == nil {
+		params.ProjectID = savedView.ProjectID
+	}
+	if params.Status == "" {
+		params.Status = query.Status
+	}
+	if params.Search == "" {
+		params.Search = query.Search
+	}
+	if params.Sort == "" {
+		params.Sort = query.Sort
+	}
+	if params.Order == "" {
+		params.Order = query.Order
+	}
+	if params.Limit <= 0 {
+		params.Limit = query.Limit
+	}
+	params.ViewFilter = query.Filter
+
+	if len(query.CustomFields) > 0 {
+		customFields := make(map[string]string, len(query.CustomFields)+len(params.CustomFields))
+		for key, value := range query.CustomFields {
+			customFields[key] = value
+		}
+		for key, value := range params.CustomFields {
+			customFields[key] = value
+		}
+		params.CustomFields = customFields
+	}
+
+	response := viewResponse(userID, savedView)
+	return &response, nil
+}
+
+// saveView сохраняет представление; представление по умолчанию снимает эту отметку
+// с остальных представлений пользователя в той же транзакции
+func (s *savedViewService) saveView(view *models.SavedView) error {
+	return s.transactor.Transaction(func(tx *gorm.DB) error {
+		savedViewRepo := s.savedViewRepo.WithTx(tx)
+		if view.IsDefault {
+			if err := savedViewRepo.ClearDefault(view.UserID); err != nil {
+				return err
+			}
+		}
+		if view.ID == 0 {
+			return savedViewRepo.Create(view)
+		}
+		return savedViewRepo.Update(view)
+	})
+}
+
+// validateView проверяет проект, выражение фильтра, сортировку и колонки представления
+func (s *savedViewService) validateView(userID uint, view *models.SavedView) error {
+	if view.ProjectID != nil {
+		project, err := s.projectRepo.GetByID(*view.ProjectID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("project not found")
+			}
+			return err
+		}
+		if project.UserID != userID {
+			return errors.New("access denied")
+		}
+	} else if view.Shared {
+		return errors.New("invalid view: only project views can be shared")
+	}
+
+	if view.Query.Filter != "" {
+		if _, _, err := filter.Compile(view.Query.Filter, taskFilterSchema, time.Now()); err != nil {
+			return errors.New("invalid view: " + err.Error())
+		}
+	}
+	if _, err := parseTaskSort(view.Query.Sort, view.Query.Order); err != nil {
+		return errors.New("invalid view: " + err.Error())
+	}
+
+	for _, column := range view.Columns {
+		if !savedViewColumns[column] && !strings.HasPrefix(column, "cf.") {
+			return errors.New("invalid view: unknown column " + column)
+		}
+	}
+
+	return nil
+}
+
+// getVisibleView получает представление, доступное пользователю: свое
+// или открытое представление проекта, к которому у пользователя есть доступ
+func (s *savedViewService) getVisibleView(userID, viewID uint) (*models.SavedView, error) {
+	view, err := s.savedViewRepo.GetByID(viewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("view not found")
+		}
+		return nil, err
+	}
+
+	if view.UserID == userID {
+		return view, nil
+	}
+	if view.Shared && view.ProjectID != nil {
+		hasAccess, err := s.projectRepo.HasAccess(*view.ProjectID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if hasAccess {
+			return view, nil
+		}
+	}
+	return nil, errors.New("access denied")
+}
+
+// viewResponse конвертирует представление в ответ для пользователя userID:
+// открытое представление другого пользователя доступно только для чтения
+func viewResponse(userID uint, view *models.SavedView) models.SavedViewResponse {
+	response := view.ToResponse()
+	response.ReadOnly = view.UserID != userID
+	return response
+}
+
+// getOwnView получает представление и проверяет, что оно принадлежит пользователю
+func (s *savedViewService) getOwnView(userID, viewID uint) (*models.SavedView, error) {
+	view, err := s.savedViewRepo.GetByID(viewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("view not found")
+		}
+		return nil, err
+	}
+
+	if view.UserID != userID {
+		return nil, errors.New("access denied")
+	}
+	return view, nil
+}
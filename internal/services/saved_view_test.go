@@ -0,0 +1,136 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+)
+
+func TestSavedViewKeepsSingleDefault(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+	views := NewSavedViewService(repository.NewTransactor(env.db), repository.NewSavedViewRepository(env.db), repository.NewProjectRepository(env.db))
+
+	first, err := views.CreateView(userID, models.CreateSavedViewRequest{Name: "First", IsDefault: true})
+	if err != nil {
+		t.Fatalf("create first view: %v", err)
+	}
+	second, err := views.CreateView(userID, models.CreateSavedViewRequest{Name: "Second", IsDefault: true})
+	if err != nil {
+		t.Fatalf("create second view: %v", err)
+	}
+
+	// Повторная отметка уже выбранного представления не должна снимать ее
+	isDefault := true
+	if _, err := views.UpdateView(userID, second.ID, models.UpdateSavedViewRequest{IsDefault: &isDefault}); err != nil {
+		t.Fatalf("update view: %v", err)
+	}
+
+	got, err := views.GetViews(userID, nil)
+	if err != nil {
+		t.Fatalf("get views: %v", err)
+	}
+	defaults := map[uint]bool{}
+	for _, view := range got {
+		defaults[view.ID] = view.IsDefault
+	}
+	if defaults[first.ID] || !defaults[second.ID] {
+		t.Fatalf("defaults = %v, want only view %d", defaults, second.ID)
+	}
+
+	// Индекс не дает отметить второе представление в обход сервиса
+	err = env.db.Model(&models.SavedView{}).Where("id = ?", first.ID).UpdateColumn("is_default", true).Error
+	if err == nil {
+		t.Error("a second default view should violate the unique index")
+	}
+}
+
+func TestSharedViewIsVisibleWithProjectAccess(t *testing.T) {
+	env := newTestEnv(t)
+	aliceID := env.createUser(t, "alice")
+	bobID := env.createUser(t, "bob")
+	projectRepo := repository.NewProjectRepository(env.db)
+	views := NewSavedViewService(repository.NewTransactor(env.db), repository.NewSavedViewRepository(env.db), projectRepo)
+
+	workflow, err := env.taskService.workflowService.GetDefaultWorkflow(aliceID)
+	if err != nil {
+		t.Fatalf("get default workflow: %v", err)
+	}
+	project := &models.Project{Name: "Project", UserID: aliceID, WorkflowID: workflow.ID}
+	if err := projectRepo.Create(project); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+
+	shared, err := views.CreateView(aliceID, models.CreateSavedViewRequest{Name: "Shared", ProjectID: &project.ID, Shared: true})
+	if err != nil {
+		t.Fatalf("create shared view: %v", err)
+	}
+	private, err := views.CreateView(aliceID, models.CreateSavedViewRequest{Name: "Private", ProjectID: &project.ID})
+	if err != nil {
+		t.Fatalf("create private view: %v", err)
+	}
+
+	if _, err := views.GetView(bobID, shared.ID); err == nil {
+		t.Error("bob should not see the shared view without access to the project")
+	}
+	if got, err := views.GetViews(bobID, nil); err != nil || len(got) != 0 {
+		t.Errorf("bob's views = %v, %v, want none", got, err)
+	}
+
+	// Подписка на задачу проекта открывает доступ к проекту
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	task := env.createTask(t, aliceID, "Task", start, start)
+	if err := env.db.Model(&models.Task{}).Where("id = ?", task.ID).UpdateColumn("project_id", project.ID).Error; err != nil {
+		t.Fatalf("move task to project: %v", err)
+	}
+	watcher := models.TaskWatcher{TaskID: task.ID, UserID: bobID, Reason: models.WatchReasonAssignee, Watching: true}
+	if err := env.db.Create(&watcher).Error; err != nil {
+		t.Fatalf("add watcher: %v", err)
+	}
+
+	got, err := views.GetView(bobID, shared.ID)
+	if err != nil {
+		t.Fatalf("get shared view: %v", err)
+	}
+	if !got.ReadOnly {
+		t.Error("someone else's shared view should be read-only")
+	}
+	if _, err := views.GetView(bobID, private.ID); err == nil {
+		t.Error("bob should not see a view that is not shared")
+	}
+	list, err := views.GetViews(bobID, nil)
+	if err != nil || len(list) != 1 || list[0].ID != shared.ID {
+		t.Errorf("bob's views = %v, %v, want only the shared view", list, err)
+	}
+
+	name := "Renamed"
+	if _, err := views.UpdateView(bobID, shared.ID, models.UpdateSavedViewRequest{Name: &name}); err == nil {
+		t.Error("bob should not update alice's view")
+	}
+	if err := views.DeleteView(bobID, shared.ID); err == nil {
+		t.Error("bob should not delete alice's view")
+	}
+}
+
+func TestSavedViewIsPrivate(t *testing.T) {
+	env := newTestEnv(t)
+	aliceID := env.createUser(t, "alice")
+	bobID := env.createUser(t, "bob")
+	views := NewSavedViewService(repository.NewTransactor(env.db), repository.NewSavedViewRepository(env.db), repository.NewProjectRepository(env.db))
+
+	view, err := views.CreateView(aliceID, models.CreateSavedViewRequest{Name: "Mine"})
+	if err != nil {
+		t.Fatalf("create view: %v", err)
+	}
+
+	if _, err := views.GetView(bobID, view.ID); err == nil {
+		t.Error("another user should not see the view")
+	}
+	params := models.TaskQueryParams{}
+	if _, err := views.ApplyView(bobID, strconv.FormatUint(uint64(view.ID), 10), &params); err == nil {
+		t.Error("another user should not apply the view")
+	}
+}
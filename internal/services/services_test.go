@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang_server/internal/database"
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testEnv сервисы, работающие с отдельной базой данных теста
+type testEnv struct {
+	db          *gorm.DB
+	userRepo    repository.UserRepository
+	taskRepo    repository.TaskRepository
+	taskService *taskService
+}
+
+// newTestEnv создает базу данных во временном каталоге теста и сервисы над ней
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	db, err := database.Init(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("init database: %v", err)
+	}
+	db.Logger = logger.Discard
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	transactor := repository.NewTransactor(db)
+	userRepo := repository.NewUserRepository(db)
+	taskRepo := repository.NewTaskRepository(db)
+	projectRepo := repository.NewProjectRepository(db)
+	customFieldRepo := repository.NewCustomFieldRepository(db)
+	workflowService := NewWorkflowService(transactor, repository.NewWorkflowRepository(db))
+	customFieldService := NewCustomFieldService(customFieldRepo, projectRepo, userRepo)
+	workingCalendarService := NewWorkingCalendarService(transactor, repository.NewWorkingCalendarRepository(db), userRepo)
+
+	tasks := NewTaskService(
+		transactor,
+		taskRepo,
+		projectRepo,
+		customFieldRepo,
+		repository.NewTimeEntryRepository(db),
+		repository.NewTaskRevisionRepository(db),
+		repository.NewChecklistRepository(db),
+		repository.NewTaskWatcherRepository(db),
+		repository.NewNotificationRepository(db),
+		repository.NewCalendarObjectRepository(db),
+		repository.NewTaskDependencyRepository(db),
+		userRepo,
+		repository.NewSLAPolicyRepository(db),
+		workflowService,
+		customFieldService,
+		workingCalendarService,
+		100,
+		[]byte("test-secret"),
+	)
+
+	return &testEnv{
+		db:          db,
+		userRepo:    userRepo,
+		taskRepo:    taskRepo,
+		taskService: tasks.(*taskService),
+	}
+}
+
+// createUser создает пользователя с именем name
+func (e *testEnv) createUser(t *testing.T, name string) uint {
+	t.Helper()
+
+	user := &models.User{
+		Username: name,
+		Email:    fmt.Sprintf("%s@example.com", name),
+		Password: "password",
+	}
+	if err := e.userRepo.Create(user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user.ID
+}
+
+// createTask создает задачу пользователя с датами start и end
+func (e *testEnv) createTask(t *testing.T, userID uint, title string, start, end time.Time) *models.TaskResponse {
+	t.Helper()
+
+	task, err := e.taskService.CreateTask(userID, models.CreateTaskRequest{
+		Title:     title,
+		StartDate: start,
+		EndDate:   end,
+	})
+	if err != nil {
+		t.Fatalf("create task %q: %v", title, err)
+	}
+	return task
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// SLAPolicyService интерфейс для сервиса политик SLA
+type SLAPolicyService interface {
+	GetPolicies(userID uint) ([]models.SLAPolicyResponse, error)
+	CreatePolicy(userID uint, req models.CreateSLAPolicyRequest) (*models.SLAPolicyResponse, error)
+	UpdatePolicy(userID, policyID uint, req models.UpdateSLAPolicyRequest) (*models.SLAPolicyResponse, error)
+	DeletePolicy(userID, policyID uint) error
+}
+
+// slaPolicyService реализация сервиса политик SLA
+type slaPolicyService struct {
+	slaPolicyRepo repository.SLAPolicyRepository
+	projectRepo   repository.ProjectRepository
+}
+
+// NewSLAPolicyService создает новый сервис политик SLA
+func NewSLAPolicyService(slaPolicyRepo repository.SLAPolicyRepository, projectRepo repository.ProjectRepository) SLAPolicyService {
+	return &slaPolicyService{
+		slaPolicyRepo: slaPolicyRepo,
+		projectRepo:   projectRepo,
+	}
+}
+
+// GetPolicies получает политики SLA пользователя
+func (s *slaPolicyService) GetPolicies(userID uint) ([]models.SLAPolicyResponse, error) {
+	policies, err := s.slaPolicyRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	policyResponses := make([]models.SLAPolicyResponse, len(policies))
+	for i, policy := range policies {
+		policyResponses[i] = policy.ToResponse()
+	}
+	return policyResponses, nil
+}
+
+// CreatePolicy создает политику SLA. Для каждого сочетания проекта и приоритета
+// у пользователя может быть только одна политика.
+func (s *slaPolicyService) CreatePolicy(userID uint, req models.CreateSLAPolicyRequest) (*models.SLAPolicyResponse, error) {
+	if req.ProjectID != nil {
+		project, err := s.projectRepo.GetByID(*req.ProjectID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("project not found")
+			}
+			return nil, err
+		}
+		if project.UserID != userID {
+			return nil, errors.New("project not found")
+		}
+	}
+
+	policies, err := s.slaPolicyRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		if sameProject(policy.ProjectID, req.ProjectID) && policy.Priority == req.Priority {
+			return nil, errors.New("sla policy already exists for this project and priority")
+		}
+	}
+
+	policy := &models.SLAPolicy{
+		UserID:          userID,
+		Name:            req.Name,
+		ProjectID:       req.ProjectID,
+		Priority:        req.Priority,
+		ResolutionHours: req.ResolutionHours,
+		BusinessDays:    req.BusinessDays,
+	}
+	if err := s.slaPolicyRepo.Create(policy); err != nil {
+		return nil, err
+	}
+
+	policyResponse := policy.ToResponse()
+	return &policyResponse, nil
+}
+
+// UpdatePolicy обновляет политику SLA
+func (s *slaPolicyService) UpdatePolicy(userID, policyID uint, req models.UpdateSLAPolicyRequest) (*models.SLAPolicyResponse, error) {
+	policy, err := s.getPolicy(userID, policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		policy.Name = *req.Name
+	}
+	if req.ResolutionHours != nil {
+		policy.ResolutionHours = *req.ResolutionHours
+	}
+	if req.BusinessDays != nil {
+		policy.BusinessDays = *req.BusinessDays
+	}
+
+	if err := s.slaPolicyRepo.Update(policy); err != nil {
+		return nil, err
+	}
+
+	policyResponse := policy.ToResponse()
+	return &policyResponse, nil
+}
+
+// DeletePolicy удаляет политику SLA. Отметки о нарушениях, сделанные по ней, сохраняются.
+func (s *slaPolicyService) DeletePolicy(userID, policyID uint) error {
+	if _, err := s.getPolicy(userID, policyID); err != nil {
+		return err
+	}
+	return s.slaPolicyRepo.Delete(policyID)
+}
+
+// getPolicy получает политику SLA и проверяет, что она принадлежит пользователю
+func (s *slaPolicyService) getPolicy(userID, policyID uint) (*models.SLAPolicy, error) {
+	policy, err := s.slaPolicyRepo.GetByID(policyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("sla policy not found")
+		}
+		return nil, err
+	}
+
+	if policy.UserID != userID {
+		return nil, errors.New("access denied")
+	}
+
+	return policy, nil
+}
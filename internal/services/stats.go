@@ -0,0 +1,202 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// statsDefaultDays длина периода статистики по умолчанию
+	statsDefaultDays = 30
+	// statsMaxDays наибольшая длина периода статистики
+	statsMaxDays = 366
+	// statsDateFormat формат дат периода статистики
+	statsDateFormat = "2006-01-02"
+)
+
+// StatsService интерфейс для сервиса статистики по задачам
+type StatsService interface {
+	GetStats(userID uint, params models.StatsParams) (*models.StatsResponse, error)
+}
+
+// statsService реализация сервиса статистики
+type statsService struct {
+	statsRepo   repository.StatsRepository
+	projectRepo repository.ProjectRepository
+	userRepo    repository.UserRepository
+}
+
+// NewStatsService создает новый сервис статистики
+func NewStatsService(statsRepo repository.StatsRepository, projectRepo repository.ProjectRepository, userRepo repository.UserRepository) StatsService {
+	return &statsService{
+		statsRepo:   statsRepo,
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// GetStats собирает статистику по задачам пользователя за период. По умолчанию период —
+// последние 30 дней по текущей дате пользователя; даты from и to включаются в период.
+// Числа по статусам и просрочке относятся к текущему моменту, остальные — к периоду.
+func (s *statsService) GetStats(userID uint, params models.StatsParams) (*models.StatsResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	location := user.Location()
+
+	if params.Interval == "" {
+		params.Interval = "day"
+	}
+
+	to := overdueBefore(now, location)
+	if params.To != nil {
+		to = params.To.UTC()
+	}
+	from := to.AddDate(0, 0, -(statsDefaultDays - 1))
+	if params.From != nil {
+		from = params.From.UTC()
+	}
+	if from.After(to) {
+		return nil, errors.New("invalid range: from is after to")
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days > statsMaxDays {
+		return nil, errors.New("invalid range: period is longer than 366 days")
+	}
+
+	if params.ProjectID != nil {
+		project, err := s.projectRepo.GetByID(*params.ProjectID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("project not found")
+			}
+			return nil, err
+		}
+		if project.UserID != userID {
+			return nil, errors.New("project not found")
+		}
+	}
+
+	// Даты периода — дни пользователя, поэтому границы берутся в его часовом поясе
+	statsRange := models.StatsRange{
+		From:      time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, location).UTC(),
+		To:        time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, location).UTC(),
+		Days:      make([]models.StatsDay, days),
+		ProjectID: params.ProjectID,
+	}
+	for i := range statsRange.Days {
+		date := from.AddDate(0, 0, i)
+		statsRange.Days[i] = models.StatsDay{
+			Date: date.Format(statsDateFormat),
+			From: time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location).UTC(),
+			To:   time.Date(date.Year(), date.Month(), date.Day()+1, 0, 0, 0, 0, location).UTC(),
+		}
+	}
+	stats := &models.StatsResponse{
+		From:      from.Format(statsDateFormat),
+		To:        to.Format(statsDateFormat),
+		Interval:  params.Interval,
+		ProjectID: params.ProjectID,
+	}
+
+	stats.ByStatus, err = s.statsRepo.CountByStatus(userID, params.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryCounts, err := s.statsRepo.CountByCategory(userID, params.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	stats.ByCategory = map[models.StatusCategory]int64{
+		models.StatusCategoryTodo:  0,
+		models.StatusCategoryDoing: 0,
+		models.StatusCategoryDone:  0,
+	}
+	for _, categoryCount := range categoryCounts {
+		if _, ok := stats.ByCategory[categoryCount.Category]; ok {
+			stats.ByCategory[categoryCount.Category] = categoryCount.Count
+		}
+	}
+
+	stats.Overdue.ByPriority, err = s.statsRepo.CountOverdue(userID, params.ProjectID, overdueBefore(now, location))
+	if err != nil {
+		return nil, err
+	}
+	for _, priorityCount := range stats.Overdue.ByPriority {
+		stats.Overdue.Total += priorityCount.Count
+	}
+
+	cycleTime, err := s.statsRepo.CycleTime(userID, statsRange)
+	if err != nil {
+		return nil, err
+	}
+	stats.CycleTime = *cycleTime
+
+	created, err := s.statsRepo.DailyCreated(userID, statsRange)
+	if err != nil {
+		return nil, err
+	}
+	completed, err := s.statsRepo.DailyCompleted(userID, statsRange)
+	if err != nil {
+		return nil, err
+	}
+	openBefore, err := s.statsRepo.CountOpenBefore(userID, statsRange)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Throughput, stats.Burndown = buildSeries(from, days, params.Interval, countsByDay(created), countsByDay(completed), openBefore)
+	return stats, nil
+}
+
+// buildSeries строит по дневным числам созданных и завершенных задач ряд за дни или недели
+// (неделя начинается с понедельника и обозначается его датой) и ежедневный остаток
+// незавершенных задач. Идеальный остаток равномерно убывает от начального до нуля.
+func buildSeries(from time.Time, days int, interval string, created, completed map[string]int64, openBefore int64) ([]models.ThroughputPoint, []models.BurndownPoint) {
+	throughput := []models.ThroughputPoint{}
+	burndown := make([]models.BurndownPoint, 0, days)
+
+	remaining := openBefore
+	for i := 0; i < days; i++ {
+		date := from.AddDate(0, 0, i)
+		day := date.Format(statsDateFormat)
+
+		period := day
+		if interval == "week" {
+			offset := (int(date.Weekday()) + 6) % 7
+			period = date.AddDate(0, 0, -offset).Format(statsDateFormat)
+		}
+		if len(throughput) == 0 || throughput[len(throughput)-1].Period != period {
+			throughput = append(throughput, models.ThroughputPoint{Period: period})
+		}
+		point := &throughput[len(throughput)-1]
+		point.Created += created[day]
+		point.Completed += completed[day]
+
+		remaining += created[day] - completed[day]
+		ideal := float64(openBefore)
+		if days > 1 {
+			ideal = float64(openBefore) * float64(days-1-i) / float64(days-1)
+		}
+		burndown = append(burndown, models.BurndownPoint{Date: day, Remaining: remaining, Ideal: ideal})
+	}
+
+	return throughput, burndown
+}
+
+// countsByDay превращает дневные числа в словарь по дате
+func countsByDay(counts []models.DailyCount) map[string]int64 {
+	byDay := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		byDay[count.Day] = count.Count
+	}
+	return byDay
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+)
+
+func TestGetStatsBucketsDaysInUserTimezone(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+	if err := env.db.Model(&models.User{}).Where("id = ?", userID).Update("timezone", "Europe/Moscow").Error; err != nil {
+		t.Fatalf("set timezone: %v", err)
+	}
+
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	// 22:30 UTC 18 октября — уже 19 октября по Москве, 21:30 UTC 19 октября — еще 19-е
+	createdAt := []time.Time{
+		time.Date(2026, 10, 18, 20, 30, 0, 0, time.UTC),
+		time.Date(2026, 10, 18, 22, 30, 0, 0, time.UTC),
+		time.Date(2026, 10, 19, 20, 30, 0, 0, time.UTC),
+		time.Date(2026, 10, 19, 21, 30, 0, 0, time.UTC),
+	}
+	for _, at := range createdAt {
+		task := env.createTask(t, userID, "Task", day, day)
+		if err := env.db.Model(&models.Task{}).Where("id = ?", task.ID).UpdateColumn("created_at", at).Error; err != nil {
+			t.Fatalf("set created_at: %v", err)
+		}
+	}
+
+	stats, err := NewStatsService(repository.NewStatsRepository(env.db), repository.NewProjectRepository(env.db), env.userRepo).
+		GetStats(userID, models.StatsParams{From: &day, To: &day})
+	if err != nil {
+		t.Fatalf("get stats: %v", err)
+	}
+
+	if len(stats.Throughput) != 1 || stats.Throughput[0].Period != "2026-10-19" || stats.Throughput[0].Created != 2 {
+		t.Fatalf("throughput = %+v, want 2 tasks created on 2026-10-19", stats.Throughput)
+	}
+	// Задача, созданная 18 октября по Москве, открыта к началу периода
+	if len(stats.Burndown) != 1 || stats.Burndown[0].Remaining != 3 {
+		t.Errorf("burndown = %+v, want 3 open tasks at the end of the day", stats.Burndown)
+	}
+}
+
+func TestGetStatsCountsDaysAcrossDSTChange(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database is not available: %v", err)
+	}
+
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+	if err := env.db.Model(&models.User{}).Where("id = ?", userID).Update("timezone", "America/New_York").Error; err != nil {
+		t.Fatalf("set timezone: %v", err)
+	}
+
+	// 1 ноября 2026 года в Нью-Йорке заканчивается летнее время: в этом дне 25 часов
+	from := time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	createdAt := []time.Time{
+		time.Date(2026, 10, 31, 23, 30, 0, 0, location),
+		time.Date(2026, 11, 1, 0, 30, 0, 0, location),
+		time.Date(2026, 11, 1, 23, 30, 0, 0, location),
+		time.Date(2026, 11, 2, 23, 30, 0, 0, location),
+	}
+	for _, at := range createdAt {
+		task := env.createTask(t, userID, "Task", from, from)
+		if err := env.db.Model(&models.Task{}).Where("id = ?", task.ID).UpdateColumn("created_at", at.UTC()).Error; err != nil {
+			t.Fatalf("set created_at: %v", err)
+		}
+	}
+
+	stats, err := NewStatsService(repository.NewStatsRepository(env.db), repository.NewProjectRepository(env.db), env.userRepo).
+		GetStats(userID, models.StatsParams{From: &from, To: &to})
+	if err != nil {
+		t.Fatalf("get stats: %v", err)
+	}
+
+	want := map[string]int64{"2026-10-31": 1, "2026-11-01": 2, "2026-11-02": 1}
+	if len(stats.Throughput) != len(want) {
+		t.Fatalf("throughput = %+v, want %d days", stats.Throughput, len(want))
+	}
+	for _, point := range stats.Throughput {
+		if point.Created != want[point.Period] {
+			t.Errorf("created on %s = %d, want %d", point.Period, point.Created, want[point.Period])
+		}
+	}
+}
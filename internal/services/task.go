@@ -1,61 +1,326 @@
 package services
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"golang_server/internal/models"
 	"golang_server/internal/repository"
+	"golang_server/pkg/cursor"
+	"golang_server/pkg/fts"
+	"golang_server/pkg/jsonpatch"
+	"golang_server/pkg/rank"
 
 	"gorm.io/gorm"
 )
 
+const (
+	// maxTags максимальное число меток задачи
+	maxTags = 20
+	// maxTagLength максимальная длина метки в символах
+	maxTagLength = 50
+	// purgeBatchSize число задач, удаляемых из корзины за одну транзакцию
+	purgeBatchSize = 100
+)
+
 // TaskService интерфейс для сервиса задач
 type TaskService interface {
 	CreateTask(userID uint, req models.CreateTaskRequest) (*models.TaskResponse, error)
-	GetTasks(userID uint, params models.TaskQueryParams) ([]models.TaskResponse, int64, error)
+	CreateCalendarTask(userID uint, req models.CreateTaskRequest, status models.TaskStatus, object *models.CalendarObject) (*models.TaskResponse, error)
+	CreateTaskTree(userID uint, req models.CreateTaskRequest, subtasks []models.CreateTaskRequest) (*models.TaskResponse, []models.TaskResponse, error)
+	GetTasks(userID uint, params models.TaskQueryParams) (*models.TaskPage, error)
+	SearchTasks(userID uint, params models.TaskSearchParams) ([]models.TaskSearchResult, int64, error)
 	GetTaskByID(userID, taskID uint) (*models.TaskResponse, error)
 	UpdateTask(userID, taskID uint, req models.UpdateTaskRequest) (*models.TaskResponse, error)
-	DeleteTask(userID, taskID uint) error
+	PatchTask(userID, taskID uint, patchType models.PatchType, patch []byte, expectedVersion *int) (*models.TaskResponse, error)
+	DeleteTask(userID, taskID uint, expectedVersion *int) error
+	MoveTask(userID, taskID uint, req models.MoveTaskRequest) (*models.TaskResponse, error)
+	GetBoard(userID uint, projectID *uint) ([]models.BoardColumn, error)
+	BulkTasks(userID uint, req models.BulkTaskRequest) (*models.BulkTaskResult, error)
+	ExportTasks(userID uint, params models.TaskQueryParams, exporter TaskExporter) error
+	PrepareImport(userID uint, req models.TaskImportRequest, records []models.ImportRecord, progress func(int)) ([]models.TaskImportItem, []models.ImportRowError, error)
+	ImportTasks(userID uint, items []models.TaskImportItem, progress func(int)) (int, error)
+	GetTrash(userID uint, params models.TaskQueryParams) ([]models.TaskResponse, int64, error)
+	RestoreTask(userID, taskID uint) (*models.TaskResponse, error)
+	ArchiveTask(userID, taskID uint) (*models.TaskResponse, error)
+	UnarchiveTask(userID, taskID uint) (*models.TaskResponse, error)
+	ArchiveCompleted(userID uint, req models.ArchiveCompletedRequest) (*models.ArchiveResult, error)
+	ArchiveExpired(before time.Time) (*models.ArchiveResult, error)
+	CheckSLA(now time.Time) (int, error)
+	PurgeTask(userID, taskID uint) error
+	EmptyTrash(userID uint) (int, error)
+	PurgeExpired(before time.Time) (int, error)
+	GetTaskHistory(userID, taskID uint, params models.TaskHistoryParams) ([]models.TaskRevisionResponse, int64, error)
+	RevertTask(userID, taskID, revisionID uint) (*models.TaskResponse, error)
+	GetDependencies(userID, taskID uint) (*models.TaskDependenciesResponse, error)
+	AddDependency(userID, taskID uint, req models.CreateTaskDependencyRequest) (*models.TaskDependency, error)
+	RemoveDependency(userID, taskID, dependsOnID uint) error
+	GetTimeline(userID uint, params models.TimelineParams) (*models.TimelineResponse, error)
+	ShiftTask(userID, taskID uint, req models.ShiftTaskRequest) (*models.ShiftTaskResult, error)
 }
 
 // taskService реализация сервиса задач
 type taskService struct {
-	taskRepo repository.TaskRepository
+	transactor         repository.Transactor
+	taskRepo           repository.TaskRepository
+	projectRepo        repository.ProjectRepository
+	customFieldRepo    repository.CustomFieldRepository
+	timeEntryRepo      repository.TimeEntryRepository
+	revisionRepo       repository.TaskRevisionRepository
+	checklistRepo      repository.ChecklistRepository
+	watcherRepo        repository.TaskWatcherRepository
+	notificationRepo   repository.NotificationRepository
+	calendarObjectRepo repository.CalendarObjectRepository
+	dependencyRepo     repository.TaskDependencyRepository
+	userRepo           repository.UserRepository
+	slaPolicyRepo      repository.SLAPolicyRepository
+	workflowService    WorkflowService
+	customFieldService CustomFieldService
+	// workingCalendarService календари рабочих дней для расписания и сроков SLA
+	workingCalendarService WorkingCalendarService
+
+	// bulkMaxItems максимальное число задач в одной массовой операции
+	bulkMaxItems int
+
+	// cursorSecret ключ подписи курсоров пагинации
+	cursorSecret []byte
 }
 
 // NewTaskService создает новый сервис задач
-func NewTaskService(taskRepo repository.TaskRepository) TaskService {
+func NewTaskService(
+	transactor repository.Transactor,
+	taskRepo repository.TaskRepository,
+	projectRepo repository.ProjectRepository,
+	customFieldRepo repository.CustomFieldRepository,
+	timeEntryRepo repository.TimeEntryRepository,
+	revisionRepo repository.TaskRevisionRepository,
+	checklistRepo repository.ChecklistRepository,
+	watcherRepo repository.TaskWatcherRepository,
+	notificationRepo repository.NotificationRepository,
+	calendarObjectRepo repository.CalendarObjectRepository,
+	dependencyRepo repository.TaskDependencyRepository,
+	userRepo repository.UserRepository,
+	slaPolicyRepo repository.SLAPolicyRepository,
+	workflowService WorkflowService,
+	customFieldService CustomFieldService,
+	workingCalendarService WorkingCalendarService,
+	bulkMaxItems int,
+	cursorSecret []byte,
+) TaskService {
 	return &taskService{
-		taskRepo: taskRepo,
+		transactor:             transactor,
+		taskRepo:               taskRepo,
+		projectRepo:            projectRepo,
+		customFieldRepo:        customFieldRepo,
+		timeEntryRepo:          timeEntryRepo,
+		revisionRepo:           revisionRepo,
+		checklistRepo:          checklistRepo,
+		watcherRepo:            watcherRepo,
+		notificationRepo:       notificationRepo,
+		calendarObjectRepo:     calendarObjectRepo,
+		dependencyRepo:         dependencyRepo,
+		userRepo:               userRepo,
+		slaPolicyRepo:          slaPolicyRepo,
+		workflowService:        workflowService,
+		customFieldService:     customFieldService,
+		workingCalendarService: workingCalendarService,
+		bulkMaxItems:           bulkMaxItems,
+		cursorSecret:           cursorSecret,
 	}
 }
 
 // CreateTask создает новую задачу
 func (s *taskService) CreateTask(userID uint, req models.CreateTaskRequest) (*models.TaskResponse, error) {
+	return s.createTask(userID, req, "")
+}
+
+// CreateCalendarTask создает задачу сразу в статусе status без проверки переходов
+// и в той же транзакции сохраняет ресурс календаря object, который на нее ссылается
+func (s *taskService) CreateCalendarTask(userID uint, req models.CreateTaskRequest, status models.TaskStatus, object *models.CalendarObject) (*models.TaskResponse, error) {
+	// Процесс по умолчанию создается при первом обращении вне транзакции
+	if _, err := s.resolveWorkflow(userID, req.ProjectID); err != nil {
+		return nil, err
+	}
+
+	var task *models.TaskResponse
+	err := s.transactor.Transaction(func(tx *gorm.DB) error {
+		txService := s.withTx(tx)
+
+		var err error
+		task, err = txService.createTask(userID, req, status)
+		if err != nil {
+			return err
+		}
+		object.TaskID = task.ID
+		return txService.calendarObjectRepo.Save(object)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// createTask создает задачу в статусе status; пустой статус означает начальный статус процесса
+func (s *taskService) createTask(userID uint, req models.CreateTaskRequest, status models.TaskStatus) (*models.TaskResponse, error) {
 	// Проверяем, что дата окончания не раньше даты начала
 	if req.EndDate.Before(req.StartDate) {
 		return nil, errors.New("end date cannot be before start date")
 	}
 
+	priority := req.Priority
+	if priority == "" {
+		priority = models.TaskPriorityMedium
+	}
+	if !priority.IsValid() {
+		return nil, errors.New("invalid priority")
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Checklist) > maxChecklistItems {
+		return nil, fmt.Errorf("invalid checklist: a task can have at most %d items", maxChecklistItems)
+	}
+	for _, item := range req.Checklist {
+		if length := utf8.RuneCountInString(strings.TrimSpace(item.Title)); length < 1 || length > 255 {
+			return nil, errors.New("invalid checklist: item title must be between 1 and 255 characters")
+		}
+	}
+
+	// Подзадача создается в том же проекте, что и родительская задача
+	if req.ParentID != nil {
+		parent, err := s.taskRepo.GetByID(*req.ParentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("invalid parent task: task not found")
+			}
+			return nil, err
+		}
+		if parent.UserID != userID {
+			return nil, errors.New("invalid parent task: task not found")
+		}
+		if !sameProject(parent.ProjectID, req.ProjectID) {
+			return nil, errors.New("invalid parent task: parent belongs to another project")
+		}
+	}
+
+	// Задача проекта следует процессу проекта, остальные — процессу по умолчанию
+	workflow, err := s.resolveWorkflow(userID, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		status = workflow.InitialStatus()
+	} else if _, ok := workflow.FindStatus(status); !ok {
+		return nil, errors.New("invalid status")
+	}
+
+	fieldIDs, values, err := s.customFieldService.BuildValues(req.ProjectID, req.CustomFields, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Новая задача попадает в конец колонки
+	last, err := s.taskRepo.GetLastPosition(userID, req.ProjectID, status)
+	if err != nil {
+		return nil, err
+	}
+
 	task := &models.Task{
 		Title:       req.Title,
 		Description: req.Description,
-		StartDate:   req.StartDate,
-		EndDate:     req.EndDate,
-		Status:      models.TaskStatusPending,
+		StartDate:   req.StartDate.UTC(),
+		EndDate:     req.EndDate.UTC(),
+		Status:      status,
+		Priority:    priority,
+		Position:    rank.After(last),
 		UserID:      userID,
+		ProjectID:   req.ProjectID,
+		WorkflowID:  &workflow.ID,
+		ParentID:    req.ParentID,
+		Tags:        tags,
 	}
+	applyStatusCategory(task, workflow)
 
-	if err := s.taskRepo.Create(task); err != nil {
+	// Задача, значения ее полей, чек-лист и запись истории сохраняются атомарно
+	err = s.transactor.Transaction(func(tx *gorm.DB) error {
+		if err := s.taskRepo.WithTx(tx).Create(task); err != nil {
+			return err
+		}
+		if err := s.customFieldRepo.WithTx(tx).ReplaceValues(task.ID, fieldIDs, values); err != nil {
+			return err
+		}
+		positions := rank.Sequence(len(req.Checklist))
+		for i, itemReq := range req.Checklist {
+			item := &models.ChecklistItem{
+				TaskID:   task.ID,
+				Title:    itemReq.Title,
+				Required: itemReq.Required,
+				Position: positions[i],
+			}
+			if err := s.checklistRepo.WithTx(tx).Create(item); err != nil {
+				return err
+			}
+		}
+		return s.recordRevision(tx, userID, task.ID, models.TaskActionCreated, nil, nil)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	taskResponse := task.ToResponse()
-	return &taskResponse, nil
+	return s.reloadTask(task.ID)
+}
+
+// CreateTaskTree атомарно создает задачу и ее подзадачи.
+// Подзадачи создаются в проекте родительской задачи.
+func (s *taskService) CreateTaskTree(userID uint, req models.CreateTaskRequest, subtasks []models.CreateTaskRequest) (*models.TaskResponse, []models.TaskResponse, error) {
+	// Процесс по умолчанию создается при первом обращении вне транзакции,
+	// поэтому определяем его до начала транзакции
+	if _, err := s.resolveWorkflow(userID, req.ProjectID); err != nil {
+		return nil, nil, err
+	}
+
+	var task *models.TaskResponse
+	created := make([]models.TaskResponse, 0, len(subtasks))
+
+	err := s.transactor.Transaction(func(tx *gorm.DB) error {
+		txService := s.withTx(tx)
+
+		var err error
+		task, err = txService.CreateTask(userID, req)
+		if err != nil {
+			return err
+		}
+
+		for _, subtaskReq := range subtasks {
+			subtaskReq.ParentID = &task.ID
+			subtaskReq.ProjectID = req.ProjectID
+			subtask, err := txService.CreateTask(userID, subtaskReq)
+			if err != nil {
+				return err
+			}
+			created = append(created, *subtask)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return task, created, nil
 }
 
-// GetTasks получает список задач пользователя
-func (s *taskService) GetTasks(userID uint, params models.TaskQueryParams) ([]models.TaskResponse, int64, error) {
+// GetTasks получает список задач пользователя.
+// Страница выбирается по номеру (page) или по курсору (cursor); в обоих режимах
+// возвращаются курсоры соседних страниц.
+func (s *taskService) GetTasks(userID uint, params models.TaskQueryParams) (*models.TaskPage, error) {
 	// Устанавливаем значения по умолчанию для пагинации
 	if params.Page <= 0 {
 		params.Page = 1
@@ -67,17 +332,161 @@ func (s *taskService) GetTasks(userID uint, params models.TaskQueryParams) ([]mo
 		params.Limit = 100
 	}
 
+	if err := s.prepareTaskQuery(userID, &params); err != nil {
+		return nil, err
+	}
+
+	limit := params.Limit
+	query := taskQuerySignature(params)
+	if params.Cursor != "" {
+		var payload taskCursor
+		if err := cursor.Decode(s.cursorSecret, params.Cursor, &payload); err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+		if payload.Query != query {
+			return nil, errors.New("invalid cursor: filters or sort differ from the original request")
+		}
+		params.CursorValues = payload.Values
+		params.CursorBackward = payload.Backward
+		// Лишняя задача показывает, есть ли следующая страница в направлении выборки
+		params.Limit = limit + 1
+	}
+
+	// По умолчанию общее число задач считается только при пагинации по страницам
+	countTotal := params.Cursor == ""
+	if params.Count != nil {
+		countTotal = *params.Count
+	}
+	params.SkipCount = !countTotal
+
 	tasks, total, err := s.taskRepo.GetByUserID(userID, params)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	taskResponses := make([]models.TaskResponse, len(tasks))
+	// Наличие соседних страниц
+	var hasNext, hasPrev bool
+	if params.Cursor != "" {
+		more := len(tasks) > limit
+		if more {
+			tasks = tasks[:limit]
+		}
+		if params.CursorBackward {
+			for i, j := 0, len(tasks)-1; i < j; i, j = i+1, j-1 {
+				tasks[i], tasks[j] = tasks[j], tasks[i]
+			}
+			hasNext, hasPrev = true, more
+		} else {
+			hasNext, hasPrev = more, true
+		}
+	} else {
+		hasPrev = params.Page > 1
+		if params.SkipCount {
+			hasNext = len(tasks) == limit
+		} else {
+			hasNext = int64(params.Page*limit) < total
+		}
+	}
+
+	page := &models.TaskPage{Tasks: make([]models.TaskResponse, len(tasks))}
 	for i, task := range tasks {
-		taskResponses[i] = task.ToResponse()
+		page.Tasks[i] = task.ToResponse()
+	}
+	if !params.SkipCount {
+		page.Total = &total
 	}
 
-	return taskResponses, total, nil
+	if len(tasks) > 0 {
+		if hasNext {
+			page.NextCursor, err = s.encodeTaskCursor(tasks[len(tasks)-1].ID, params, query, false)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if hasPrev {
+			page.PrevCursor, err = s.encodeTaskCursor(tasks[0].ID, params, query, true)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.attachTotals(page.Tasks); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// SearchTasks выполняет полнотекстовый поиск по названию и описанию задач пользователя
+func (s *taskService) SearchTasks(userID uint, params models.TaskSearchParams) ([]models.TaskSearchResult, int64, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 10
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	match := fts.Query(params.Query)
+	if match == "" {
+		return nil, 0, errors.New("invalid search query: no words to search for")
+	}
+
+	hits, total, err := s.taskRepo.Search(userID, match, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	taskIDs := make([]uint, len(hits))
+	for i, hit := range hits {
+		taskIDs[i] = hit.TaskID
+	}
+
+	results := []models.TaskSearchResult{}
+	if len(taskIDs) == 0 {
+		return results, total, nil
+	}
+
+	tasks, err := s.taskRepo.GetByIDs(taskIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	taskResponses := make(map[uint]models.TaskResponse, len(tasks))
+	for _, task := range tasks {
+		taskResponses[task.ID] = task.ToResponse()
+	}
+
+	// Результаты сохраняют порядок релевантности, полученный из индекса
+	for _, hit := range hits {
+		taskResponse, ok := taskResponses[hit.TaskID]
+		if !ok {
+			continue
+		}
+		results = append(results, models.TaskSearchResult{
+			Task:  taskResponse,
+			Score: hit.Score,
+			Highlights: models.TaskSearchHighlights{
+				Title:       hit.Title,
+				Description: hit.Description,
+			},
+		})
+	}
+
+	resultTasks := make([]models.TaskResponse, len(results))
+	for i, result := range results {
+		resultTasks[i] = result.Task
+	}
+	if err := s.attachTotals(resultTasks); err != nil {
+		return nil, 0, err
+	}
+	for i := range results {
+		results[i].Task = resultTasks[i]
+	}
+
+	return results, total, nil
 }
 
 // GetTaskByID получает задачу по ID
@@ -95,12 +504,83 @@ func (s *taskService) GetTaskByID(userID, taskID uint) (*models.TaskResponse, er
 		return nil, errors.New("access denied")
 	}
 
-	taskResponse := task.ToResponse()
-	return &taskResponse, nil
+	taskResponses := []models.TaskResponse{task.ToResponse()}
+	if err := s.attachTotals(taskResponses); err != nil {
+		return nil, err
+	}
+	return &taskResponses[0], nil
 }
 
 // UpdateTask обновляет задачу
 func (s *taskService) UpdateTask(userID, taskID uint, req models.UpdateTaskRequest) (*models.TaskResponse, error) {
+	return s.updateTask(userID, taskID, req, models.TaskActionUpdated, nil)
+}
+
+// PatchTask применяет к задаче JSON Merge Patch (RFC 7396) или JSON Patch (RFC 6902).
+// Патч применяется к документу задачи в прочитанной версии, изменения проходят те же
+// проверки, что и в UpdateTask, а запись выполняется только при неизменной версии.
+func (s *taskService) PatchTask(userID, taskID uint, patchType models.PatchType, patch []byte, expectedVersion *int) (*models.TaskResponse, error) {
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("task not found")
+		}
+		return nil, err
+	}
+
+	// Проверяем, что задача принадлежит пользователю
+	if task.UserID != userID {
+		return nil, errors.New("access denied")
+	}
+
+	if expectedVersion != nil && *expectedVersion != task.Version {
+		return nil, repository.ErrVersionConflict
+	}
+
+	doc, err := json.Marshal(task.PatchDocument())
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch patchType {
+	case models.PatchTypeMerge:
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	case models.PatchTypeJSON:
+		patched, err = jsonpatch.Apply(doc, patch)
+	default:
+		return nil, errors.New("unsupported patch type")
+	}
+	if err != nil {
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return nil, errors.New("patch test failed")
+		}
+		return nil, errors.New("invalid patch: " + err.Error())
+	}
+
+	// Исходный документ проходит через JSON так же, как результат,
+	// чтобы значения сравнивались в одинаковом представлении
+	var before, after models.TaskPatchDocument
+	if err := json.Unmarshal(doc, &before); err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&after); err != nil {
+		return nil, errors.New("invalid patch: " + err.Error())
+	}
+
+	req, err := buildPatchRequest(&before, &after)
+	if err != nil {
+		return nil, err
+	}
+	req.ExpectedVersion = &task.Version
+
+	return s.updateTask(userID, taskID, *req, models.TaskActionUpdated, nil)
+}
+
+// updateTask применяет изменения к задаче и записывает их в историю как action
+func (s *taskService) updateTask(userID, taskID uint, req models.UpdateTaskRequest, action models.TaskAction, revertedFrom *uint) (*models.TaskResponse, error) {
 	task, err := s.taskRepo.GetByID(taskID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -114,6 +594,13 @@ func (s *taskService) UpdateTask(userID, taskID uint, req models.UpdateTaskReque
 		return nil, errors.New("access denied")
 	}
 
+	// Клиент изменял задачу, прочитанную в другой версии
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != task.Version {
+		return nil, repository.ErrVersionConflict
+	}
+
+	before := task.Snapshot()
+
 	// Обновляем поля, если они предоставлены
 	if req.Title != nil {
 		task.Title = *req.Title
@@ -122,16 +609,45 @@ func (s *taskService) UpdateTask(userID, taskID uint, req models.UpdateTaskReque
 		task.Description = *req.Description
 	}
 	if req.Status != nil {
-		if !req.Status.IsValid() {
-			return nil, errors.New("invalid status")
+		workflow, err := s.getTaskWorkflow(task)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkTransition(workflow, task.Status, *req.Status); err != nil {
+			return nil, err
+		}
+		if err := s.checkChecklist(task, workflow, *req.Status); err != nil {
+			return nil, err
+		}
+		// При смене статуса задача переезжает в конец новой колонки
+		if *req.Status != task.Status {
+			last, err := s.taskRepo.GetLastPosition(userID, task.ProjectID, *req.Status)
+			if err != nil {
+				return nil, err
+			}
+			task.Position = rank.After(last)
 		}
 		task.Status = *req.Status
+		applyStatusCategory(task, workflow)
+	}
+	if req.Priority != nil {
+		if !req.Priority.IsValid() {
+			return nil, errors.New("invalid priority")
+		}
+		task.Priority = *req.Priority
 	}
 	if req.StartDate != nil {
-		task.StartDate = *req.StartDate
+		task.StartDate = req.StartDate.UTC()
 	}
 	if req.EndDate != nil {
-		task.EndDate = *req.EndDate
+		task.EndDate = req.EndDate.UTC()
+	}
+	if req.Tags != nil {
+		tags, err := normalizeTags(*req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		task.Tags = tags
 	}
 
 	// Проверяем, что дата окончания не раньше даты начала
@@ -139,16 +655,34 @@ func (s *taskService) UpdateTask(userID, taskID uint, req models.UpdateTaskReque
 		return nil, errors.New("end date cannot be before start date")
 	}
 
-	if err := s.taskRepo.Update(task); err != nil {
+	var fieldIDs []uint
+	var values []models.CustomFieldValue
+	if req.CustomFields != nil {
+		fieldIDs, values, err = s.customFieldService.BuildValues(task.ProjectID, req.CustomFields, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.transactor.Transaction(func(tx *gorm.DB) error {
+		if err := s.taskRepo.WithTx(tx).Update(task); err != nil {
+			return err
+		}
+		if err := s.customFieldRepo.WithTx(tx).ReplaceValues(task.ID, fieldIDs, values); err != nil {
+			return err
+		}
+		return s.recordRevision(tx, userID, task.ID, action, &before, revertedFrom)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	taskResponse := task.ToResponse()
-	return &taskResponse, nil
+	return s.reloadTask(task.ID)
 }
 
-// DeleteTask удаляет задачу
-func (s *taskService) DeleteTask(userID, taskID uint) error {
+// DeleteTask перемещает задачу в корзину. Значения полей и записи времени
+// сохраняются до окончательного удаления, чтобы задачу можно было восстановить.
+func (s *taskService) DeleteTask(userID, taskID uint, expectedVersion *int) error {
 	task, err := s.taskRepo.GetByID(taskID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -162,5 +696,680 @@ func (s *taskService) DeleteTask(userID, taskID uint) error {
 		return errors.New("access denied")
 	}
 
-	return s.taskRepo.Delete(taskID)
-}
\ No newline at end of file
+	if expectedVersion != nil && *expectedVersion != task.Version {
+		return repository.ErrVersionConflict
+	}
+
+	before := task.Snapshot()
+	return s.transactor.Transaction(func(tx *gorm.DB) error {
+		if err := s.taskRepo.WithTx(tx).Delete(taskID, task.Version); err != nil {
+			return err
+		}
+		return s.recordRevision(tx, userID, taskID, models.TaskActionDeleted, &before, nil)
+	})
+}
+
+// GetTrash получает задачи пользователя в корзине
+func (s *taskService) GetTrash(userID uint, params models.TaskQueryParams) ([]models.TaskResponse, int64, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 10
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	tasks, total, err := s.taskRepo.GetTrash(userID, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	taskResponses := make([]models.TaskResponse, len(tasks))
+	for i, task := range tasks {
+		taskResponses[i] = task.ToResponse()
+	}
+
+	if err := s.attachTotals(taskResponses); err != nil {
+		return nil, 0, err
+	}
+
+	return taskResponses, total, nil
+}
+
+// RestoreTask возвращает задачу из корзины
+func (s *taskService) RestoreTask(userID, taskID uint) (*models.TaskResponse, error) {
+	task, err := s.getTaskUnscoped(userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !task.DeletedAt.Valid {
+		return nil, errors.New("task is not in trash")
+	}
+
+	before := task.Snapshot()
+	err = s.transactor.Transaction(func(tx *gorm.DB) error {
+		if err := s.taskRepo.WithTx(tx).Restore(taskID); err != nil {
+			return err
+		}
+		return s.recordRevision(tx, userID, taskID, models.TaskActionRestored, &before, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reloadTask(taskID)
+}
+
+// PurgeTask удаляет задачу безвозвратно вместе со значениями полей и записями времени.
+// Задача может находиться как в корзине, так и в общем списке.
+func (s *taskService) PurgeTask(userID, taskID uint) error {
+	if _, err := s.getTaskUnscoped(userID, taskID); err != nil {
+		return err
+	}
+
+	return s.purgeTasks([]uint{taskID})
+}
+
+// EmptyTrash безвозвратно удаляет все задачи пользователя из корзины
+func (s *taskService) EmptyTrash(userID uint) (int, error) {
+	taskIDs, err := s.taskRepo.GetTrashIDs(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.purgeTasks(taskIDs); err != nil {
+		return 0, err
+	}
+	return len(taskIDs), nil
+}
+
+// PurgeExpired безвозвратно удаляет задачи, попавшие в корзину раньше before.
+// Задачи удаляются порциями, каждая в своей транзакции.
+func (s *taskService) PurgeExpired(before time.Time) (int, error) {
+	purged := 0
+	for {
+		taskIDs, err := s.taskRepo.GetTrashedBefore(before, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		if err := s.purgeTasks(taskIDs); err != nil {
+			return purged, err
+		}
+		purged += len(taskIDs)
+
+		if len(taskIDs) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// purgeTasks удаляет задачи и их связанные данные в одной транзакции
+func (s *taskService) purgeTasks(taskIDs []uint) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	return s.transactor.Transaction(func(tx *gorm.DB) error {
+		for _, taskID := range taskIDs {
+			if err := s.customFieldRepo.WithTx(tx).DeleteValuesByTaskID(taskID); err != nil {
+				return err
+			}
+			if err := s.timeEntryRepo.WithTx(tx).DeleteByTaskID(taskID); err != nil {
+				return err
+			}
+			if err := s.checklistRepo.WithTx(tx).DeleteByTaskID(taskID); err != nil {
+				return err
+			}
+			if err := s.watcherRepo.WithTx(tx).DeleteByTaskID(taskID); err != nil {
+				return err
+			}
+			if err := s.notificationRepo.WithTx(tx).DeleteByTaskID(taskID); err != nil {
+				return err
+			}
+			if err := s.revisionRepo.WithTx(tx).DeleteByTaskID(taskID); err != nil {
+				return err
+			}
+			if err := s.calendarObjectRepo.WithTx(tx).DeleteByTaskID(taskID); err != nil {
+				return err
+			}
+			if err := s.dependencyRepo.WithTx(tx).DeleteByTaskID(taskID); err != nil {
+				return err
+			}
+			if err := s.taskRepo.WithTx(tx).DetachChildren(taskID); err != nil {
+				return err
+			}
+			if err := s.taskRepo.WithTx(tx).Purge(taskID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// getTaskUnscoped получает задачу пользователя, включая задачи в корзине
+func (s *taskService) getTaskUnscoped(userID, taskID uint) (*models.Task, error) {
+	task, err := s.taskRepo.GetByIDUnscoped(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("task not found")
+		}
+		return nil, err
+	}
+
+	// Проверяем, что задача принадлежит пользователю
+	if task.UserID != userID {
+		return nil, errors.New("access denied")
+	}
+
+	return task, nil
+}
+
+// MoveTask перемещает задачу в колонку и позицию на доске
+func (s *taskService) MoveTask(userID, taskID uint, req models.MoveTaskRequest) (*models.TaskResponse, error) {
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("task not found")
+		}
+		return nil, err
+	}
+
+	// Проверяем, что задача принадлежит пользователю
+	if task.UserID != userID {
+		return nil, errors.New("access denied")
+	}
+
+	workflow, err := s.getTaskWorkflow(task)
+	if err != nil {
+		return nil, err
+	}
+	before := task.Snapshot()
+	if err := checkTransition(workflow, task.Status, req.Status); err != nil {
+		return nil, err
+	}
+	if err := s.checkChecklist(task, workflow, req.Status); err != nil {
+		return nil, err
+	}
+
+	// Определяем границы, между которыми встанет задача
+	var lower, upper string
+	switch {
+	case req.AfterID != nil && req.BeforeID != nil:
+		after, err := s.getNeighbor(userID, task, *req.AfterID, req.Status)
+		if err != nil {
+			return nil, err
+		}
+		before, err := s.getNeighbor(userID, task, *req.BeforeID, req.Status)
+		if err != nil {
+			return nil, err
+		}
+		lower, upper = after.Position, before.Position
+	case req.AfterID != nil:
+		after, err := s.getNeighbor(userID, task, *req.AfterID, req.Status)
+		if err != nil {
+			return nil, err
+		}
+		lower = after.Position
+		if upper, err = s.taskRepo.GetNextPosition(userID, task.ProjectID, req.Status, lower); err != nil {
+			return nil, err
+		}
+	case req.BeforeID != nil:
+		before, err := s.getNeighbor(userID, task, *req.BeforeID, req.Status)
+		if err != nil {
+			return nil, err
+		}
+		upper = before.Position
+		if lower, err = s.taskRepo.GetPrevPosition(userID, task.ProjectID, req.Status, upper); err != nil {
+			return nil, err
+		}
+	default:
+		if lower, err = s.taskRepo.GetLastPosition(userID, task.ProjectID, req.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	// Если задача уже стоит между границами, ее позиция не меняется
+	position := task.Position
+	inPlace := task.Status == req.Status && position > lower && (upper == "" || position < upper)
+	if !inPlace {
+		position, err = rank.Between(lower, upper)
+		if err != nil {
+			return nil, errors.New("invalid neighbor order")
+		}
+	}
+
+	task.Status = req.Status
+	task.Position = position
+	applyStatusCategory(task, workflow)
+
+	// Перестановка внутри колонки не меняет полей задачи и в историю не попадает
+	err = s.transactor.Transaction(func(tx *gorm.DB) error {
+		if err := s.taskRepo.WithTx(tx).UpdatePosition(task.ID, task.Version, task.Status, task.Position, task.CompletedAt); err != nil {
+			return err
+		}
+		return s.recordRevision(tx, userID, task.ID, models.TaskActionMoved, &before, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reloadTask(task.ID)
+}
+
+// GetBoard получает задачи, сгруппированные по колонкам статусов процесса.
+// Без проекта доска строится по процессу по умолчанию для задач вне проектов.
+func (s *taskService) GetBoard(userID uint, projectID *uint) ([]models.BoardColumn, error) {
+	workflow, err := s.resolveWorkflow(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskRepo.GetBoard(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]models.BoardColumn, len(workflow.Statuses))
+	index := make(map[models.TaskStatus]int, len(workflow.Statuses))
+	for i, status := range workflow.Statuses {
+		columns[i] = models.BoardColumn{
+			Status:   status.Key,
+			Name:     status.Name,
+			Category: status.Category,
+			Tasks:    []models.TaskResponse{},
+		}
+		index[status.Key] = i
+	}
+
+	for _, task := range tasks {
+		if i, ok := index[task.Status]; ok {
+			columns[i].Tasks = append(columns[i].Tasks, task.ToResponse())
+		}
+	}
+
+	return columns, nil
+}
+
+// GetTaskHistory получает историю изменений задачи, включая задачи в корзине
+func (s *taskService) GetTaskHistory(userID, taskID uint, params models.TaskHistoryParams) ([]models.TaskRevisionResponse, int64, error) {
+	if _, err := s.getTaskUnscoped(userID, taskID); err != nil {
+		return nil, 0, err
+	}
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	revisions, total, err := s.revisionRepo.GetByTaskID(taskID, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	revisionResponses := make([]models.TaskRevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		revisionResponses[i] = revision.ToResponse()
+	}
+
+	return revisionResponses, total, nil
+}
+
+// RevertTask возвращает поля задачи к состоянию после указанной записи истории.
+// Возврат выполняется как обычное обновление: действуют правила переходов процесса
+// и проверки пользовательских полей, а сам возврат тоже попадает в историю.
+func (s *taskService) RevertTask(userID, taskID, revisionID uint) (*models.TaskResponse, error) {
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("task not found")
+		}
+		return nil, err
+	}
+
+	// Проверяем, что задача принадлежит пользователю
+	if task.UserID != userID {
+		return nil, errors.New("access denied")
+	}
+
+	revision, err := s.revisionRepo.GetByID(revisionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("revision not found")
+		}
+		return nil, err
+	}
+	if revision.TaskID != taskID {
+		return nil, errors.New("revision not found")
+	}
+
+	snapshot := revision.Snapshot
+	// Снимки, записанные до появления меток, соответствуют задаче без меток
+	tags := snapshot.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	req := models.UpdateTaskRequest{
+		Title:       &snapshot.Title,
+		Description: &snapshot.Description,
+		Priority:    &snapshot.Priority,
+		StartDate:   &snapshot.StartDate,
+		EndDate:     &snapshot.EndDate,
+		Tags:        &tags,
+	}
+	if snapshot.Status != task.Status {
+		req.Status = &snapshot.Status
+	}
+
+	// Значения возвращаются только для существующих полей проекта;
+	// обязательные поля без значения в снимке остаются как есть
+	if task.ProjectID != nil {
+		fields, err := s.customFieldRepo.GetByProjectID(*task.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		req.CustomFields = make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			value, ok := snapshot.CustomFields[field.Key]
+			if !ok && field.Required {
+				continue
+			}
+			req.CustomFields[field.Key] = value
+		}
+	}
+
+	return s.updateTask(userID, taskID, req, models.TaskActionReverted, &revision.ID)
+}
+
+// recordRevision записывает изменение задачи в историю в транзакции tx, обновляет
+// подписчиков задачи и рассылает им уведомления. Состояние после изменения
+// перечитывается из базы; before == nil означает создание задачи.
+// Изменения без отличий от предыдущего состояния не записываются.
+func (s *taskService) recordRevision(tx *gorm.DB, userID, taskID uint, action models.TaskAction, before *models.TaskSnapshot, revertedFrom *uint) error {
+	task, err := s.taskRepo.WithTx(tx).GetByIDUnscoped(taskID)
+	if err != nil {
+		return err
+	}
+
+	snapshot := task.Snapshot()
+	changes := snapshot.Diff(before)
+	if len(changes) == 0 && action != models.TaskActionReverted {
+		return nil
+	}
+
+	revision := &models.TaskRevision{
+		TaskID:       taskID,
+		UserID:       userID,
+		Action:       action,
+		Changes:      changes,
+		Snapshot:     snapshot,
+		RevertedFrom: revertedFrom,
+	}
+	if err := s.revisionRepo.WithTx(tx).Create(revision); err != nil {
+		return err
+	}
+
+	if err := s.syncWatchers(tx, task); err != nil {
+		return err
+	}
+	return s.notifyWatchers(tx, revision)
+}
+
+// reloadTask перечитывает задачу вместе со связанными данными для ответа
+func (s *taskService) reloadTask(taskID uint) (*models.TaskResponse, error) {
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	taskResponses := []models.TaskResponse{task.ToResponse()}
+	if err := s.attachTotals(taskResponses); err != nil {
+		return nil, err
+	}
+	return &taskResponses[0], nil
+}
+
+// attachTotals заполняет суммарное учтенное время задач, счетчики их чек-листов,
+// признаки просрочки и сроки SLA
+func (s *taskService) attachTotals(taskResponses []models.TaskResponse) error {
+	if len(taskResponses) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]uint, len(taskResponses))
+	for i, taskResponse := range taskResponses {
+		taskIDs[i] = taskResponse.ID
+	}
+
+	totals, err := s.timeEntryRepo.GetTotals(taskIDs, time.Now())
+	if err != nil {
+		return err
+	}
+
+	counts, err := s.checklistRepo.GetCounts(taskIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range taskResponses {
+		taskResponses[i].TotalTime = totals[taskResponses[i].ID]
+		taskResponses[i].ChecklistCompleted = counts[taskResponses[i].ID].Completed
+		taskResponses[i].ChecklistTotal = counts[taskResponses[i].ID].Total
+	}
+	return s.attachDeadlines(taskResponses, time.Now())
+}
+
+// resolveWorkflow определяет процесс для задач проекта или процесс пользователя по умолчанию
+func (s *taskService) resolveWorkflow(userID uint, projectID *uint) (*models.Workflow, error) {
+	if projectID == nil {
+		return s.workflowService.GetDefaultWorkflow(userID)
+	}
+
+	project, err := s.projectRepo.GetByID(*projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("project not found")
+		}
+		return nil, err
+	}
+	if project.UserID != userID {
+		return nil, errors.New("project not found")
+	}
+
+	return s.workflowService.GetWorkflowByID(userID, project.WorkflowID)
+}
+
+// getTaskWorkflow получает процесс, которому следует задача
+func (s *taskService) getTaskWorkflow(task *models.Task) (*models.Workflow, error) {
+	if task.WorkflowID == nil {
+		return s.workflowService.GetDefaultWorkflow(task.UserID)
+	}
+	return s.workflowService.GetWorkflowByID(task.UserID, *task.WorkflowID)
+}
+
+// checkTransition проверяет, что статус есть в процессе и переход в него разрешен
+func checkTransition(workflow *models.Workflow, from, to models.TaskStatus) error {
+	if _, ok := workflow.FindStatus(to); !ok {
+		return errors.New("invalid status")
+	}
+	if !workflow.CanTransition(from, to) {
+		return errors.New("status transition not allowed")
+	}
+	return nil
+}
+
+// buildPatchRequest превращает результат PATCH в запрос на обновление, содержащий
+// только измененные поля. Удаленное описание очищается, удаленные пользовательские
+// поля сбрасываются, а обязательные поля задачи удалить нельзя.
+func buildPatchRequest(before, after *models.TaskPatchDocument) (*models.UpdateTaskRequest, error) {
+	if after.Title == nil {
+		return nil, errors.New("invalid patch: title is required")
+	}
+	if length := utf8.RuneCountInString(*after.Title); length < 1 || length > 255 {
+		return nil, errors.New("invalid patch: title must be between 1 and 255 characters")
+	}
+	if after.Status == nil {
+		return nil, errors.New("invalid patch: status is required")
+	}
+	if after.Priority == nil {
+		return nil, errors.New("invalid patch: priority is required")
+	}
+	if after.StartDate == nil || after.EndDate == nil {
+		return nil, errors.New("invalid patch: start_date and end_date are required")
+	}
+
+	req := &models.UpdateTaskRequest{}
+	if *after.Title != *before.Title {
+		req.Title = after.Title
+	}
+	description := ""
+	if after.Description != nil {
+		description = *after.Description
+	}
+	if description != *before.Description {
+		req.Description = &description
+	}
+	if *after.Status != *before.Status {
+		req.Status = after.Status
+	}
+	if *after.Priority != *before.Priority {
+		req.Priority = after.Priority
+	}
+	if !after.StartDate.Equal(*before.StartDate) {
+		req.StartDate = after.StartDate
+	}
+	if !after.EndDate.Equal(*before.EndDate) {
+		req.EndDate = after.EndDate
+	}
+	// Удаленный список меток очищает метки задачи
+	tags := after.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	if !reflect.DeepEqual(tags, before.Tags) {
+		req.Tags = &tags
+	}
+
+	for key, value := range after.CustomFields {
+		if !reflect.DeepEqual(before.CustomFields[key], value) {
+			if req.CustomFields == nil {
+				req.CustomFields = make(map[string]interface{})
+			}
+			req.CustomFields[key] = value
+		}
+	}
+	for key := range before.CustomFields {
+		if _, ok := after.CustomFields[key]; !ok {
+			if req.CustomFields == nil {
+				req.CustomFields = make(map[string]interface{})
+			}
+			req.CustomFields[key] = nil
+		}
+	}
+
+	return req, nil
+}
+
+// checkChecklist запрещает переводить задачу в статус категории done, пока в ее
+// чек-листе есть невыполненные обязательные пункты, если это включено в процессе
+func (s *taskService) checkChecklist(task *models.Task, workflow *models.Workflow, to models.TaskStatus) error {
+	if !workflow.RequireChecklist || task.Status == to {
+		return nil
+	}
+	status, ok := workflow.FindStatus(to)
+	if !ok || status.Category != models.StatusCategoryDone {
+		return nil
+	}
+
+	open, err := s.checklistRepo.CountOpenRequired(task.ID)
+	if err != nil {
+		return err
+	}
+	if open > 0 {
+		return errors.New("required checklist items are not completed")
+	}
+	return nil
+}
+
+// normalizeTags убирает пробелы по краям меток, пустые метки и повторы
+// (без учета регистра) и проверяет ограничения на число и длину меток
+func normalizeTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, fmt.Errorf("invalid tags: tag %q is longer than %d characters", tag, maxTagLength)
+		}
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, tag)
+	}
+	if len(result) > maxTags {
+		return nil, fmt.Errorf("invalid tags: a task can have at most %d tags", maxTags)
+	}
+	return result, nil
+}
+
+// sameProject проверяет, что обе задачи относятся к одному проекту или обе вне проектов
+func sameProject(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// applyStatusCategory отмечает момент завершения при входе в статус категории done
+// и сбрасывает его при выходе из нее
+func applyStatusCategory(task *models.Task, workflow *models.Workflow) {
+	status, ok := workflow.FindStatus(task.Status)
+	if !ok {
+		return
+	}
+	if status.Category == models.StatusCategoryDone {
+		if task.CompletedAt == nil {
+			now := time.Now().UTC()
+			task.CompletedAt = &now
+		}
+		return
+	}
+	task.CompletedAt = nil
+}
+
+// getNeighbor получает соседнюю задачу из целевой колонки
+func (s *taskService) getNeighbor(userID uint, task *models.Task, neighborID uint, status models.TaskStatus) (*models.Task, error) {
+	if neighborID == task.ID {
+		return nil, errors.New("task cannot be positioned relative to itself")
+	}
+
+	neighbor, err := s.taskRepo.GetByID(neighborID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("neighbor task not found")
+		}
+		return nil, err
+	}
+
+	if neighbor.UserID != userID {
+		return nil, errors.New("neighbor task not found")
+	}
+	if neighbor.Status != status || !sameProject(neighbor.ProjectID, task.ProjectID) {
+		return nil, errors.New("neighbor task is not in the target column")
+	}
+
+	return neighbor, nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// archiveBatchSize число задач, выбираемых для архивирования за один запрос
+const archiveBatchSize = 100
+
+// ArchiveTask помещает задачу в архив. В отличие от удаления задача остается
+// в отчетах и истории, но по умолчанию не показывается в списке задач и на доске.
+func (s *taskService) ArchiveTask(userID, taskID uint) (*models.TaskResponse, error) {
+	task, err := s.getOwnTask(userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.ArchivedAt != nil {
+		return nil, errors.New("task is already archived")
+	}
+
+	now := time.Now().UTC()
+	if err := s.setArchived(userID, task, &now); err != nil {
+		return nil, err
+	}
+
+	return s.reloadTask(taskID)
+}
+
+// UnarchiveTask возвращает задачу из архива
+func (s *taskService) UnarchiveTask(userID, taskID uint) (*models.TaskResponse, error) {
+	task, err := s.getOwnTask(userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.ArchivedAt == nil {
+		return nil, errors.New("task is not archived")
+	}
+
+	if err := s.setArchived(userID, task, nil); err != nil {
+		return nil, err
+	}
+
+	return s.reloadTask(taskID)
+}
+
+// ArchiveCompleted помещает в архив задачи пользователя, завершенные больше
+// OlderThanDays дней назад
+func (s *taskService) ArchiveCompleted(userID uint, req models.ArchiveCompletedRequest) (*models.ArchiveResult, error) {
+	if req.ProjectID != nil {
+		project, err := s.projectRepo.GetByID(*req.ProjectID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("project not found")
+			}
+			return nil, err
+		}
+		if project.UserID != userID {
+			return nil, errors.New("project not found")
+		}
+	}
+
+	before := time.Now().AddDate(0, 0, -req.OlderThanDays)
+	return s.archiveCompletedBefore(&userID, req.ProjectID, before)
+}
+
+// ArchiveExpired помещает в архив задачи всех пользователей, завершенные раньше before.
+// Изменение записывается в историю от имени владельца задачи.
+func (s *taskService) ArchiveExpired(before time.Time) (*models.ArchiveResult, error) {
+	return s.archiveCompletedBefore(nil, nil, before)
+}
+
+// archiveCompletedBefore помещает в архив задачи, завершенные раньше before. Задачи
+// выбираются порциями, и каждая архивируется в своей транзакции, чтобы не держать
+// блокировку записи на все время работы. Задачу, измененную после выборки, архивирование
+// пропускает: ее поместит в архив следующий запуск.
+func (s *taskService) archiveCompletedBefore(userID, projectID *uint, before time.Time) (*models.ArchiveResult, error) {
+	result := &models.ArchiveResult{}
+	now := time.Now().UTC()
+
+	var afterID uint
+	for {
+		tasks, err := s.taskRepo.GetCompletedBefore(userID, projectID, before, afterID, archiveBatchSize)
+		if err != nil {
+			return result, err
+		}
+
+		for _, completed := range tasks {
+			afterID = completed.ID
+			err := s.archiveCompletedTask(completed, now)
+			if errors.Is(err, repository.ErrVersionConflict) || errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("Archive: task %d skipped: %v", completed.ID, err)
+				result.Skipped++
+				continue
+			}
+			if err != nil {
+				return result, err
+			}
+			result.Archived++
+		}
+
+		if len(tasks) < archiveBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// archiveCompletedTask помещает в архив задачу, выбранную для архивирования,
+// если она не изменилась с момента выборки
+func (s *taskService) archiveCompletedTask(completed models.Task, archivedAt time.Time) error {
+	return s.transactor.Transaction(func(tx *gorm.DB) error {
+		txService := s.withTx(tx)
+		task, err := txService.taskRepo.GetByID(completed.ID)
+		if err != nil {
+			return err
+		}
+		if task.Version != completed.Version {
+			return repository.ErrVersionConflict
+		}
+		return txService.setArchived(task.UserID, task, &archivedAt)
+	})
+}
+
+// setArchived изменяет отметку архива задачи и записывает изменение в историю
+func (s *taskService) setArchived(userID uint, task *models.Task, archivedAt *time.Time) error {
+	action := models.TaskActionArchived
+	if archivedAt == nil {
+		action = models.TaskActionUnarchived
+	}
+
+	before := task.Snapshot()
+	return s.transactor.Transaction(func(tx *gorm.DB) error {
+		if err := s.taskRepo.WithTx(tx).SetArchived(task.ID, task.Version, archivedAt); err != nil {
+			return err
+		}
+		return s.recordRevision(tx, userID, task.ID, action, &before, nil)
+	})
+}
+
+// getOwnTask получает задачу пользователя, не находящуюся в корзине
+func (s *taskService) getOwnTask(userID, taskID uint) (*models.Task, error) {
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("task not found")
+		}
+		return nil, err
+	}
+
+	// Проверяем, что задача принадлежит пользователю
+	if task.UserID != userID {
+		return nil, errors.New("access denied")
+	}
+
+	return task, nil
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// concurrentEditTaskRepo изменяет задачу editID сразу после выборки задач для архивирования,
+// как если бы ее изменил другой запрос
+type concurrentEditTaskRepo struct {
+	repository.TaskRepository
+	db     *gorm.DB
+	editID uint
+}
+
+func (r *concurrentEditTaskRepo) GetCompletedBefore(userID *uint, projectID *uint, before time.Time, afterID uint, limit int) ([]models.Task, error) {
+	tasks, err := r.TaskRepository.GetCompletedBefore(userID, projectID, before, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Model(&models.Task{}).Where("id = ?", r.editID).
+		UpdateColumn("version", gorm.Expr("version + 1")).Error
+	return tasks, err
+}
+
+// completeTask отмечает задачу завершенной в момент completedAt
+func (e *testEnv) completeTask(t *testing.T, taskID uint, completedAt time.Time) {
+	t.Helper()
+
+	err := e.db.Model(&models.Task{}).Where("id = ?", taskID).
+		UpdateColumn("completed_at", completedAt.UTC()).Error
+	if err != nil {
+		t.Fatalf("complete task: %v", err)
+	}
+}
+
+func TestArchiveExpiredSkipsConflictingTask(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	var ids []uint
+	for _, title := range []string{"First", "Conflicting", "Third"} {
+		task := env.createTask(t, userID, title, start, start)
+		env.completeTask(t, task.ID, completedAt)
+		ids = append(ids, task.ID)
+	}
+	recent := env.createTask(t, userID, "Recent", start, start)
+	env.completeTask(t, recent.ID, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	env.taskService.taskRepo = &concurrentEditTaskRepo{TaskRepository: env.taskRepo, db: env.db, editID: ids[1]}
+
+	result, err := env.taskService.ArchiveExpired(time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("archive expired: %v", err)
+	}
+	if result.Archived != 2 || result.Skipped != 1 {
+		t.Fatalf("result = %+v, want 2 archived and 1 skipped", result)
+	}
+
+	archived := map[uint]bool{ids[0]: true, ids[1]: false, ids[2]: true, recent.ID: false}
+	for id, want := range archived {
+		task, err := env.taskRepo.GetByID(id)
+		if err != nil {
+			t.Fatalf("get task %d: %v", id, err)
+		}
+		if got := task.ArchivedAt != nil; got != want {
+			t.Errorf("task %d archived = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestArchiveCompletedProcessesAllBatches(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	total := archiveBatchSize*2 + 5
+	completedAt := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	tasks := make([]models.Task, total)
+	for i := range tasks {
+		tasks[i] = models.Task{
+			Title:       "Done",
+			StartDate:   completedAt,
+			EndDate:     completedAt,
+			Status:      models.TaskStatus("completed"),
+			Priority:    models.TaskPriorityMedium,
+			UserID:      userID,
+			CompletedAt: &completedAt,
+		}
+	}
+	if err := env.db.Omit("CustomFieldValues").CreateInBatches(&tasks, 100).Error; err != nil {
+		t.Fatalf("create tasks: %v", err)
+	}
+
+	result, err := env.taskService.ArchiveCompleted(userID, models.ArchiveCompletedRequest{OlderThanDays: 1})
+	if err != nil {
+		t.Fatalf("archive completed: %v", err)
+	}
+	if result.Archived != total || result.Skipped != 0 {
+		t.Fatalf("result = %+v, want %d archived", result, total)
+	}
+
+	var left int64
+	env.db.Model(&models.Task{}).Where("archived_at IS NULL").Count(&left)
+	if left != 0 {
+		t.Errorf("%d tasks left unarchived", left)
+	}
+}
+
+func TestSearchSkipsArchivedTasks(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	active := env.createTask(t, userID, "Release notes", start, start)
+	archived := env.createTask(t, userID, "Release checklist", start, start)
+	env.completeTask(t, archived.ID, start)
+	if _, err := env.taskService.ArchiveTask(userID, archived.ID); err != nil {
+		t.Fatalf("archive task: %v", err)
+	}
+
+	results, total, err := env.taskService.SearchTasks(userID, models.TaskSearchParams{Query: "release", Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("search tasks: %v", err)
+	}
+	if total != 1 || len(results) != 1 || results[0].Task.ID != active.ID {
+		t.Errorf("found %d of %d tasks, want only task %d", len(results), total, active.ID)
+	}
+}
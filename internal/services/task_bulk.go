@@ -0,0 +1,188 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// BulkTasks применяет операцию к списку задач или к задачам, выбранным фильтром.
+// В режиме atomic все изменения выполняются в одной транзакции и откатываются
+// при первой ошибке; в режиме best_effort каждая задача обрабатывается отдельно.
+// Проверки владельца и правил выполняются теми же методами, что и для одной задачи.
+func (s *taskService) BulkTasks(userID uint, req models.BulkTaskRequest) (*models.BulkTaskResult, error) {
+	if req.Mode == "" {
+		req.Mode = models.BulkModeAtomic
+	}
+
+	switch req.Action {
+	case models.BulkActionUpdate:
+		if req.Update == nil {
+			return nil, errors.New("invalid bulk request: update is required for action update")
+		}
+	case models.BulkActionMove:
+		if req.Move == nil {
+			return nil, errors.New("invalid bulk request: move is required for action move")
+		}
+	case models.BulkActionDelete:
+	default:
+		return nil, errors.New("invalid bulk request: unknown action")
+	}
+
+	taskIDs, err := s.resolveBulkTaskIDs(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.BulkTaskResult{
+		Action:  req.Action,
+		Mode:    req.Mode,
+		Total:   len(taskIDs),
+		Results: make([]models.BulkTaskItemResult, 0, len(taskIDs)),
+	}
+
+	if req.Mode == models.BulkModeBestEffort {
+		for _, taskID := range taskIDs {
+			item := s.applyBulkAction(s, userID, taskID, req)
+			if item.Success {
+				result.Succeeded++
+			} else {
+				result.Failed++
+			}
+			result.Results = append(result.Results, item)
+		}
+		return result, nil
+	}
+
+	// Атомарный режим: первая ошибка откатывает всю транзакцию
+	var failed *models.BulkTaskItemResult
+	err = s.transactor.Transaction(func(tx *gorm.DB) error {
+		txService := s.withTx(tx)
+		for _, taskID := range taskIDs {
+			item := s.applyBulkAction(txService, userID, taskID, req)
+			if !item.Success {
+				failed = &item
+				return errors.New(item.Error)
+			}
+			result.Results = append(result.Results, item)
+		}
+		return nil
+	})
+	if err != nil {
+		if failed == nil {
+			return nil, err
+		}
+		result.Failed = 1
+		result.Results = []models.BulkTaskItemResult{*failed}
+		return result, fmt.Errorf("bulk operation failed: task %d: %s", failed.ID, failed.Error)
+	}
+
+	result.Succeeded = len(result.Results)
+	return result, nil
+}
+
+// applyBulkAction выполняет операцию над одной задачей сервисом target
+func (s *taskService) applyBulkAction(target *taskService, userID, taskID uint, req models.BulkTaskRequest) models.BulkTaskItemResult {
+	item := models.BulkTaskItemResult{ID: taskID}
+
+	var err error
+	switch req.Action {
+	case models.BulkActionUpdate:
+		item.Task, err = target.UpdateTask(userID, taskID, *req.Update)
+	case models.BulkActionMove:
+		item.Task, err = target.MoveTask(userID, taskID, models.MoveTaskRequest{Status: req.Move.Status})
+	case models.BulkActionDelete:
+		err = target.DeleteTask(userID, taskID, nil)
+	}
+
+	if err != nil {
+		item.Error = err.Error()
+		item.Task = nil
+		return item
+	}
+	item.Success = true
+	return item
+}
+
+// resolveBulkTaskIDs определяет задачи массовой операции и проверяет ограничение на их число
+func (s *taskService) resolveBulkTaskIDs(userID uint, req models.BulkTaskRequest) ([]uint, error) {
+	if len(req.IDs) > 0 && req.Filter != nil {
+		return nil, errors.New("invalid bulk request: specify either ids or filter")
+	}
+
+	if req.Filter == nil {
+		if len(req.IDs) == 0 {
+			return nil, errors.New("invalid bulk request: ids or filter is required")
+		}
+
+		// Повторяющиеся ID обрабатываются один раз в порядке первого упоминания
+		seen := make(map[uint]bool, len(req.IDs))
+		taskIDs := make([]uint, 0, len(req.IDs))
+		for _, taskID := range req.IDs {
+			if !seen[taskID] {
+				seen[taskID] = true
+				taskIDs = append(taskIDs, taskID)
+			}
+		}
+		if len(taskIDs) > s.bulkMaxItems {
+			return nil, fmt.Errorf("too many tasks: %d requested, maximum is %d", len(taskIDs), s.bulkMaxItems)
+		}
+		return taskIDs, nil
+	}
+
+	params := models.TaskQueryParams{
+		Status:       req.Filter.Status,
+		ProjectID:    req.Filter.ProjectID,
+		Search:       req.Filter.Search,
+		CustomFields: req.Filter.CustomFields,
+		Filter:       req.Filter.Query,
+		Overdue:      req.Filter.Overdue,
+		Sort:         "created_at",
+		Order:        "asc",
+		Page:         1,
+		Limit:        s.bulkMaxItems + 1,
+	}
+	if err := s.prepareTaskQuery(userID, &params); err != nil {
+		return nil, err
+	}
+
+	tasks, total, err := s.taskRepo.GetByUserID(userID, params)
+	if err != nil {
+		return nil, err
+	}
+	if total > int64(s.bulkMaxItems) {
+		return nil, fmt.Errorf("too many tasks: %d matched, maximum is %d", total, s.bulkMaxItems)
+	}
+
+	taskIDs := make([]uint, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+	return taskIDs, nil
+}
+
+// withTx возвращает копию сервиса, репозитории и зависимые сервисы которой работают
+// в транзакции tx. Вложенные транзакции методов сервиса выполняются через точки сохранения.
+func (s *taskService) withTx(tx *gorm.DB) *taskService {
+	txService := *s
+	txService.transactor = s.transactor.WithTx(tx)
+	txService.taskRepo = s.taskRepo.WithTx(tx)
+	txService.customFieldRepo = s.customFieldRepo.WithTx(tx)
+	txService.timeEntryRepo = s.timeEntryRepo.WithTx(tx)
+	txService.revisionRepo = s.revisionRepo.WithTx(tx)
+	txService.checklistRepo = s.checklistRepo.WithTx(tx)
+	txService.watcherRepo = s.watcherRepo.WithTx(tx)
+	txService.notificationRepo = s.notificationRepo.WithTx(tx)
+	txService.calendarObjectRepo = s.calendarObjectRepo.WithTx(tx)
+	txService.dependencyRepo = s.dependencyRepo.WithTx(tx)
+	txService.projectRepo = s.projectRepo.WithTx(tx)
+	txService.userRepo = s.userRepo.WithTx(tx)
+	txService.slaPolicyRepo = s.slaPolicyRepo.WithTx(tx)
+	txService.workflowService = s.workflowService.WithTx(tx)
+	txService.customFieldService = s.customFieldService.WithTx(tx)
+	txService.workingCalendarService = s.workingCalendarService.WithTx(tx)
+	return &txService
+}
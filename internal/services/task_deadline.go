@@ -0,0 +1,200 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+	"golang_server/pkg/workdays"
+
+	"gorm.io/gorm"
+)
+
+// slaCheckBatchSize число задач, выбираемых для проверки SLA за один запрос
+const slaCheckBatchSize = 100
+
+// Срок задачи — календарный день end_date (дата берется в UTC, как ее передают клиенты).
+// Задача просрочена, когда этот день закончился в часовом поясе ее владельца.
+
+// dueDeadline возвращает момент окончания дня endDate в часовом поясе location
+func dueDeadline(endDate time.Time, location *time.Location) time.Time {
+	date := endDate.UTC()
+	return time.Date(date.Year(), date.Month(), date.Day()+1, 0, 0, 0, 0, location)
+}
+
+// overdueBefore возвращает границу для выборки просроченных задач: задача просрочена,
+// если ее end_date раньше начала текущего дня пользователя, записанного как дата в UTC
+func overdueBefore(now time.Time, location *time.Location) time.Time {
+	today := now.In(location)
+	return time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// userLocation возвращает часовой пояс пользователя
+func (s *taskService) userLocation(userID uint) (*time.Location, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.Location(), nil
+}
+
+// deadlineRules правила сроков задач одного владельца
+type deadlineRules struct {
+	location *time.Location
+	policies []models.SLAPolicy
+	// calendar календарь рабочих дней владельца; загружается, только если
+	// есть политики SLA, которые считают рабочие дни
+	calendar *workdays.Calendar
+}
+
+// deadlineRules читает часовой пояс, политики SLA и календарь рабочих дней владельца задач
+func (s *taskService) deadlineRules(userID uint) (*deadlineRules, error) {
+	location, err := s.userLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := s.slaPolicyRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := &deadlineRules{location: location, policies: policies}
+	for _, policy := range policies {
+		if policy.BusinessDays {
+			rules.calendar, err = s.workingCalendar(userID)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return rules, nil
+}
+
+// slaDueAt возвращает срок выполнения задачи, созданной в createdAt, по политике policy.
+// Для политики по рабочим дням учитывается только время рабочих дней в часовом поясе владельца.
+func (r *deadlineRules) slaDueAt(createdAt time.Time, policy *models.SLAPolicy) time.Time {
+	resolution := time.Duration(policy.ResolutionHours) * time.Hour
+	if policy.BusinessDays && r.calendar != nil {
+		return r.calendar.AddWorkingTime(createdAt.In(r.location), resolution).In(createdAt.Location())
+	}
+	return createdAt.Add(resolution)
+}
+
+// err возвращает ошибку загрузки нерабочих дней, из-за которой сроки SLA могли быть посчитаны неверно
+func (r *deadlineRules) err() error {
+	if r.calendar == nil {
+		return nil
+	}
+	return r.calendar.Err()
+}
+
+// attachDeadlines заполняет признаки просрочки незавершенных задач и сроки по политикам SLA.
+// Правила сроков читаются один раз для каждого владельца задач.
+func (s *taskService) attachDeadlines(taskResponses []models.TaskResponse, now time.Time) error {
+	owners := make(map[uint]*deadlineRules)
+
+	for i := range taskResponses {
+		taskResponse := &taskResponses[i]
+
+		rules, ok := owners[taskResponse.UserID]
+		if !ok {
+			var err error
+			rules, err = s.deadlineRules(taskResponse.UserID)
+			if err != nil {
+				return err
+			}
+			owners[taskResponse.UserID] = rules
+		}
+
+		if taskResponse.CompletedAt == nil {
+			deadline := dueDeadline(taskResponse.EndDate, rules.location)
+			if now.After(deadline) {
+				taskResponse.IsOverdue = true
+				taskResponse.OverdueBy = int64(now.Sub(deadline) / time.Second)
+			}
+		}
+
+		policy := models.MatchSLAPolicy(rules.policies, taskResponse.ProjectID, taskResponse.Priority)
+		if policy != nil {
+			dueAt := rules.slaDueAt(taskResponse.CreatedAt, policy)
+			if err := rules.err(); err != nil {
+				return err
+			}
+			taskResponse.SLADueAt = &dueAt
+		}
+	}
+	return nil
+}
+
+// CheckSLA отмечает нарушение SLA у незавершенных задач, срок которых по действующей
+// политике прошел к моменту now, и возвращает число отмеченных задач. Отметка записывается
+// в историю как событие sla_breached и рассылается подписчикам задачи.
+func (s *taskService) CheckSLA(now time.Time) (int, error) {
+	owners := make(map[uint]*deadlineRules)
+	breached := 0
+	var afterID uint
+	for {
+		tasks, err := s.taskRepo.GetOpenWithSLA(now, afterID, slaCheckBatchSize)
+		if err != nil {
+			return breached, err
+		}
+
+		for _, open := range tasks {
+			afterID = open.ID
+
+			rules, ok := owners[open.UserID]
+			if !ok {
+				rules, err = s.deadlineRules(open.UserID)
+				if err != nil {
+					return breached, err
+				}
+				owners[open.UserID] = rules
+			}
+
+			policy := models.MatchSLAPolicy(rules.policies, open.ProjectID, open.Priority)
+			if policy == nil {
+				continue
+			}
+			dueAt := rules.slaDueAt(open.CreatedAt, policy)
+			if err := rules.err(); err != nil {
+				return breached, err
+			}
+			if !now.After(dueAt) {
+				continue
+			}
+
+			// Задачу, измененную во время проверки, отметит следующая проверка
+			if err := s.markSLABreached(open.ID, dueAt.UTC()); err != nil {
+				if errors.Is(err, repository.ErrVersionConflict) || errors.Is(err, gorm.ErrRecordNotFound) {
+					log.Printf("SLA check: task %d skipped: %v", open.ID, err)
+					continue
+				}
+				return breached, err
+			}
+			breached++
+		}
+
+		if len(tasks) < slaCheckBatchSize {
+			return breached, nil
+		}
+	}
+}
+
+// markSLABreached отмечает нарушение SLA задачи и записывает его в историю
+func (s *taskService) markSLABreached(taskID uint, breachedAt time.Time) error {
+	return s.transactor.Transaction(func(tx *gorm.DB) error {
+		task, err := s.taskRepo.WithTx(tx).GetByID(taskID)
+		if err != nil {
+			return err
+		}
+
+		before := task.Snapshot()
+		if err := s.taskRepo.WithTx(tx).SetSLABreached(task.ID, task.Version, breachedAt); err != nil {
+			return err
+		}
+		return s.recordRevision(tx, task.UserID, task.ID, models.TaskActionSLABreached, &before, nil)
+	})
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"golang_server/internal/models"
+)
+
+func TestCheckSLAProcessesAllBatches(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	policy := models.SLAPolicy{UserID: userID, Name: "Default", ResolutionHours: 4}
+	if err := env.db.Create(&policy).Error; err != nil {
+		t.Fatalf("create policy: %v", err)
+	}
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	createTasks := func(n int, createdAt time.Time) []uint {
+		ids := make([]uint, n)
+		for i := range ids {
+			task := env.createTask(t, userID, "Task", now, now)
+			if err := env.db.Model(&models.Task{}).Where("id = ?", task.ID).UpdateColumn("created_at", createdAt).Error; err != nil {
+				t.Fatalf("set created_at: %v", err)
+			}
+			ids[i] = task.ID
+		}
+		return ids
+	}
+	late := createTasks(slaCheckBatchSize+5, now.Add(-5*time.Hour))
+	fresh := createTasks(3, now.Add(-time.Hour))
+
+	// Задачи, срок которых еще не мог пройти, не выбираются
+	candidates, err := env.taskRepo.GetOpenWithSLA(now, 0, len(late)+len(fresh))
+	if err != nil {
+		t.Fatalf("get open tasks: %v", err)
+	}
+	if len(candidates) != len(late) {
+		t.Errorf("candidates = %d, want %d late tasks", len(candidates), len(late))
+	}
+
+	breached, err := env.taskService.CheckSLA(now)
+	if err != nil {
+		t.Fatalf("check SLA: %v", err)
+	}
+	if breached != len(late) {
+		t.Fatalf("breached = %d, want %d", breached, len(late))
+	}
+
+	for _, id := range []uint{late[0], fresh[0]} {
+		task, err := env.taskRepo.GetByID(id)
+		if err != nil {
+			t.Fatalf("get task %d: %v", id, err)
+		}
+		if got, want := task.SLABreachedAt != nil, id != fresh[0]; got != want {
+			t.Errorf("task %d breached = %v, want %v", id, got, want)
+		}
+	}
+}
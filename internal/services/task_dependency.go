@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// GetDependencies получает зависимости задачи. Зависимости от задач в корзине
+// не показываются, но сохраняются до их окончательного удаления.
+func (s *taskService) GetDependencies(userID, taskID uint) (*models.TaskDependenciesResponse, error) {
+	if _, err := s.getOwnTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	dependsOn, err := s.dependencyRepo.GetByTaskID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	dependents, err := s.dependencyRepo.GetDependents(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	taskIDs := make([]uint, 0, len(dependsOn)+len(dependents))
+	for _, dependency := range dependsOn {
+		taskIDs = append(taskIDs, dependency.DependsOnID)
+	}
+	for _, dependency := range dependents {
+		taskIDs = append(taskIDs, dependency.TaskID)
+	}
+	tasks, err := s.taskRepo.GetByIDs(taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	live := make(map[uint]bool, len(tasks))
+	for _, task := range tasks {
+		live[task.ID] = true
+	}
+
+	response := &models.TaskDependenciesResponse{
+		DependsOn:  []models.TaskDependency{},
+		Dependents: []models.TaskDependency{},
+	}
+	for _, dependency := range dependsOn {
+		if live[dependency.DependsOnID] {
+			response.DependsOn = append(response.DependsOn, dependency)
+		}
+	}
+	for _, dependency := range dependents {
+		if live[dependency.TaskID] {
+			response.Dependents = append(response.Dependents, dependency)
+		}
+	}
+	return response, nil
+}
+
+// AddDependency добавляет зависимость задачи от другой задачи пользователя.
+// Зависимость, которая замкнула бы цепочку зависимостей в цикл, отклоняется.
+func (s *taskService) AddDependency(userID, taskID uint, req models.CreateTaskDependencyRequest) (*models.TaskDependency, error) {
+	if _, err := s.getOwnTask(userID, taskID); err != nil {
+		return nil, err
+	}
+	if req.DependsOnID == taskID {
+		return nil, errors.New("invalid dependency: task cannot depend on itself")
+	}
+
+	dependsOn, err := s.taskRepo.GetByID(req.DependsOnID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("dependency task not found")
+		}
+		return nil, err
+	}
+	if dependsOn.UserID != userID {
+		return nil, errors.New("dependency task not found")
+	}
+
+	var dependency *models.TaskDependency
+	err = s.transactor.Transaction(func(tx *gorm.DB) error {
+		dependencyRepo := s.dependencyRepo.WithTx(tx)
+
+		if _, err := dependencyRepo.Get(taskID, req.DependsOnID); err == nil {
+			return errors.New("dependency already exists")
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		dependencies, err := dependencyRepo.GetByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if dependsOnTransitively(dependencies, req.DependsOnID, taskID) {
+			return errors.New("invalid dependency: dependency would create a cycle")
+		}
+
+		dependency = &models.TaskDependency{
+			UserID:      userID,
+			TaskID:      taskID,
+			DependsOnID: req.DependsOnID,
+		}
+		return dependencyRepo.Create(dependency)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dependency, nil
+}
+
+// RemoveDependency удаляет зависимость задачи от другой задачи
+func (s *taskService) RemoveDependency(userID, taskID, dependsOnID uint) error {
+	if _, err := s.getOwnTask(userID, taskID); err != nil {
+		return err
+	}
+
+	deleted, err := s.dependencyRepo.Delete(taskID, dependsOnID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("dependency not found")
+	}
+	return nil
+}
+
+// dependsOnTransitively проверяет, зависит ли задача taskID от задачи targetID
+// напрямую или через цепочку других задач
+func dependsOnTransitively(dependencies []models.TaskDependency, taskID, targetID uint) bool {
+	edges := make(map[uint][]uint)
+	for _, dependency := range dependencies {
+		edges[dependency.TaskID] = append(edges[dependency.TaskID], dependency.DependsOnID)
+	}
+
+	visited := map[uint]bool{taskID: true}
+	queue := []uint{taskID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == targetID {
+			return true
+		}
+		for _, next := range edges[current] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"golang_server/internal/models"
+)
+
+// exportBatchSize число задач, читаемых из базы за один запрос при выгрузке
+const exportBatchSize = 500
+
+// TaskExporter принимает задачи выгрузки по мере их чтения из базы
+type TaskExporter interface {
+	// Begin вызывается перед первой задачей; customFieldKeys — ключи пользовательских
+	// полей в проектах выгрузки по алфавиту
+	Begin(customFieldKeys []string) error
+	Write(task models.TaskResponse) error
+	End() error
+}
+
+// ExportTasks выгружает все задачи пользователя, подходящие под фильтры и сортировку
+// списка задач. Задачи читаются частями по курсору, поэтому выгрузка не держит
+// в памяти весь список. Ошибки в параметрах возвращаются до вызова Begin.
+func (s *taskService) ExportTasks(userID uint, params models.TaskQueryParams, exporter TaskExporter) error {
+	params.Cursor = ""
+	params.Page = 1
+	params.Limit = exportBatchSize
+	params.SkipCount = true
+	if err := s.prepareTaskQuery(userID, &params); err != nil {
+		return err
+	}
+
+	keys, err := s.customFieldRepo.GetKeys(userID, params.ProjectID)
+	if err != nil {
+		return err
+	}
+	if err := exporter.Begin(keys); err != nil {
+		return err
+	}
+
+	for {
+		tasks, _, err := s.taskRepo.GetByUserID(userID, params)
+		if err != nil {
+			return err
+		}
+
+		taskResponses := make([]models.TaskResponse, len(tasks))
+		for i, task := range tasks {
+			taskResponses[i] = task.ToResponse()
+		}
+		if err := s.attachTotals(taskResponses); err != nil {
+			return err
+		}
+		for _, taskResponse := range taskResponses {
+			if err := exporter.Write(taskResponse); err != nil {
+				return err
+			}
+		}
+
+		if len(tasks) < exportBatchSize {
+			break
+		}
+		params.CursorValues, err = s.taskRepo.GetSortValues(tasks[len(tasks)-1].ID, params)
+		if err != nil {
+			return err
+		}
+	}
+
+	return exporter.End()
+}
@@ -0,0 +1,402 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// importProgressInterval число задач между отметками хода загрузки
+const importProgressInterval = 200
+
+// importFields поля задачи, которые можно загрузить из файла (кроме cf.<ключ>)
+var importFields = map[string]bool{
+	"title":       true,
+	"description": true,
+	"status":      true,
+	"priority":    true,
+	"start_date":  true,
+	"end_date":    true,
+	"project_id":  true,
+	"tags":        true,
+}
+
+// isImportField проверяет, что name — загружаемое поле задачи или cf.<ключ>
+func isImportField(name string) bool {
+	return importFields[name] || (strings.HasPrefix(name, "cf.") && len(name) > len("cf."))
+}
+
+// importCache кэширует проверенные проекты, их процессы и поля на время проверки файла
+type importCache struct {
+	projects  map[uint]bool
+	workflows map[uint]*models.Workflow
+	fields    map[uint][]models.CustomField
+}
+
+// PrepareImport проверяет записи загружаемого файла и готовит по ним запросы создания задач.
+// Ошибки в записях возвращаются списком; ошибка функции означает, что проверка не выполнена.
+// progress вызывается с числом проверенных записей.
+func (s *taskService) PrepareImport(userID uint, req models.TaskImportRequest, records []models.ImportRecord, progress func(int)) ([]models.TaskImportItem, []models.ImportRowError, error) {
+	cache := &importCache{
+		projects:  make(map[uint]bool),
+		workflows: make(map[uint]*models.Workflow),
+		fields:    make(map[uint][]models.CustomField),
+	}
+
+	if req.ProjectID != nil {
+		owned, err := s.checkImportProject(userID, *req.ProjectID, cache)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !owned {
+			return nil, nil, errors.New("project not found")
+		}
+	}
+
+	items := make([]models.TaskImportItem, 0, len(records))
+	var rowErrors []models.ImportRowError
+	for i, record := range records {
+		item, errs, err := s.prepareImportRecord(userID, req, record, cache)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+		} else {
+			items = append(items, *item)
+		}
+		progress(i + 1)
+	}
+
+	return items, rowErrors, nil
+}
+
+// ImportTasks создает проверенные задачи в одной транзакции: загрузка создает все задачи
+// или ни одной, и другие запросы не видят ее частично. progress вызывается с числом
+// созданных задач через каждые importProgressInterval задач и не должен писать в базу,
+// пока транзакция удерживает блокировку записи.
+func (s *taskService) ImportTasks(userID uint, items []models.TaskImportItem, progress func(int)) (int, error) {
+	// Процесс по умолчанию создается при первом обращении вне транзакции
+	if _, err := s.resolveWorkflow(userID, nil); err != nil {
+		return 0, err
+	}
+
+	err := s.transactor.Transaction(func(tx *gorm.DB) error {
+		txService := s.withTx(tx)
+		for i, item := range items {
+			if _, err := txService.createTask(userID, item.Request, item.Status); err != nil {
+				return fmt.Errorf("record %d: %w", item.Row, err)
+			}
+			if created := i + 1; created%importProgressInterval == 0 || created == len(items) {
+				progress(created)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(items), nil
+}
+
+// prepareImportRecord проверяет одну запись и собирает все ошибки в ней
+func (s *taskService) prepareImportRecord(userID uint, req models.TaskImportRequest, record models.ImportRecord, cache *importCache) (*models.TaskImportItem, []models.ImportRowError, error) {
+	var errs []models.ImportRowError
+	addError := func(field, message string) {
+		errs = append(errs, models.ImportRowError{Row: record.Row, Field: field, Message: message})
+	}
+
+	if record.Error != "" {
+		addError("", record.Error)
+		return nil, errs, nil
+	}
+
+	values := record.Values
+	item := &models.TaskImportItem{
+		Row:     record.Row,
+		Request: models.CreateTaskRequest{ProjectID: req.ProjectID},
+	}
+
+	title, ok := importString(values["title"])
+	title = strings.TrimSpace(title)
+	switch {
+	case !ok:
+		addError("title", "expected a string")
+	case title == "":
+		addError("title", "title is required")
+	case utf8.RuneCountInString(title) > 255:
+		addError("title", "title must be at most 255 characters")
+	}
+	item.Request.Title = title
+
+	if description, ok := importString(values["description"]); ok {
+		item.Request.Description = description
+	} else {
+		addError("description", "expected a string")
+	}
+
+	if priority, ok := importString(values["priority"]); !ok {
+		addError("priority", "expected a string")
+	} else if priority = strings.ToLower(strings.TrimSpace(priority)); priority != "" {
+		item.Request.Priority = models.TaskPriority(priority)
+		if !item.Request.Priority.IsValid() {
+			addError("priority", fmt.Sprintf("unknown priority %q, expected low, medium, high or critical", priority))
+		}
+	}
+
+	startDate, startErr := importDate(values["start_date"], "start_date")
+	if startErr != nil {
+		addError("start_date", startErr.Error())
+	}
+	endDate, endErr := importDate(values["end_date"], "end_date")
+	if endErr != nil {
+		addError("end_date", endErr.Error())
+	}
+	if startErr == nil && endErr == nil && endDate.Before(startDate) {
+		addError("end_date", "end date cannot be before start date")
+	}
+	item.Request.StartDate = startDate
+	item.Request.EndDate = endDate
+
+	projectOK := true
+	if projectID, present, err := importID(values["project_id"]); err != nil {
+		addError("project_id", err.Error())
+		projectOK = false
+	} else if present {
+		owned, err := s.checkImportProject(userID, projectID, cache)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !owned {
+			addError("project_id", "project not found")
+			projectOK = false
+		}
+		item.Request.ProjectID = &projectID
+	}
+
+	if tags, ok := importList(values["tags"]); !ok {
+		addError("tags", "expected a list of tags")
+	} else if tags, err := normalizeTags(tags); err != nil {
+		addError("tags", err.Error())
+	} else {
+		item.Request.Tags = tags
+	}
+
+	status, ok := importString(values["status"])
+	if !ok {
+		addError("status", "expected a string")
+	}
+	item.Status = models.TaskStatus(strings.TrimSpace(status))
+
+	if !projectOK {
+		return nil, errs, nil
+	}
+
+	workflow, err := s.importWorkflow(userID, item.Request.ProjectID, cache)
+	if err != nil {
+		return nil, nil, err
+	}
+	if item.Status != "" {
+		if _, ok := workflow.FindStatus(item.Status); !ok {
+			addError("status", fmt.Sprintf("unknown status %q", item.Status))
+		}
+	}
+
+	customFields, err := s.importCustomFields(values, item.Request.ProjectID, cache)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, _, err := s.customFieldService.BuildValues(item.Request.ProjectID, customFields, true); err != nil {
+		addError("custom_fields", err.Error())
+	}
+	item.Request.CustomFields = customFields
+
+	if len(errs) > 0 {
+		return nil, errs, nil
+	}
+	return item, nil, nil
+}
+
+// checkImportProject проверяет, что проект существует и принадлежит пользователю
+func (s *taskService) checkImportProject(userID, projectID uint, cache *importCache) (bool, error) {
+	if owned, ok := cache.projects[projectID]; ok {
+		return owned, nil
+	}
+
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	owned := err == nil && project.UserID == userID
+	cache.projects[projectID] = owned
+	return owned, nil
+}
+
+// importWorkflow определяет процесс задач проекта; задачи вне проектов хранятся под ключом 0
+func (s *taskService) importWorkflow(userID uint, projectID *uint, cache *importCache) (*models.Workflow, error) {
+	var key uint
+	if projectID != nil {
+		key = *projectID
+	}
+	if workflow, ok := cache.workflows[key]; ok {
+		return workflow, nil
+	}
+
+	workflow, err := s.resolveWorkflow(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	cache.workflows[key] = workflow
+	return workflow, nil
+}
+
+// importCustomFields собирает значения полей cf.<ключ> записи. Строки из CSV
+// приводятся к типу поля: числа разбираются, списки вариантов разделяются запятыми.
+// Пустые значения пропускаются.
+func (s *taskService) importCustomFields(values map[string]interface{}, projectID *uint, cache *importCache) (map[string]interface{}, error) {
+	var fields []models.CustomField
+	if projectID != nil {
+		var ok bool
+		if fields, ok = cache.fields[*projectID]; !ok {
+			var err error
+			fields, err = s.customFieldRepo.GetByProjectID(*projectID)
+			if err != nil {
+				return nil, err
+			}
+			cache.fields[*projectID] = fields
+		}
+	}
+
+	byKey := make(map[string]*models.CustomField, len(fields))
+	for i := range fields {
+		byKey[fields[i].Key] = &fields[i]
+	}
+
+	customFields := make(map[string]interface{})
+	for name, raw := range values {
+		key, ok := strings.CutPrefix(name, "cf.")
+		if !ok || raw == nil {
+			continue
+		}
+
+		text, isText := raw.(string)
+		if !isText {
+			customFields[key] = raw
+			continue
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		customFields[key] = text
+		field, known := byKey[key]
+		if !known {
+			continue
+		}
+		switch field.Type {
+		case models.CustomFieldTypeNumber, models.CustomFieldTypeUser:
+			if number, err := strconv.ParseFloat(text, 64); err == nil {
+				customFields[key] = number
+			}
+		case models.CustomFieldTypeMultiSelect:
+			options := []interface{}{}
+			for _, option := range splitImportList(text) {
+				options = append(options, option)
+			}
+			customFields[key] = options
+		}
+	}
+	return customFields, nil
+}
+
+// importString приводит значение записи к строке; отсутствующее значение — пустая строка
+func importString(raw interface{}) (string, bool) {
+	switch value := raw.(type) {
+	case nil:
+		return "", true
+	case string:
+		return value, true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// importDate разбирает обязательную дату записи в формате YYYY-MM-DD или RFC 3339
+func importDate(raw interface{}, field string) (time.Time, error) {
+	text, ok := raw.(string)
+	if raw != nil && !ok {
+		return time.Time{}, errors.New("expected a date string")
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return time.Time{}, errors.New(field + " is required")
+	}
+	date, err := parseDateValue(text)
+	if err != nil {
+		return time.Time{}, errors.New("expected a date in YYYY-MM-DD or RFC 3339 format")
+	}
+	return date, nil
+}
+
+// importID разбирает необязательный идентификатор записи
+func importID(raw interface{}) (uint, bool, error) {
+	switch value := raw.(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		if value > 0 && value == float64(uint(value)) {
+			return uint(value), true, nil
+		}
+	case string:
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return 0, false, nil
+		}
+		if id, err := strconv.ParseUint(value, 10, 32); err == nil && id > 0 {
+			return uint(id), true, nil
+		}
+	}
+	return 0, false, errors.New("expected a positive integer ID")
+}
+
+// importList приводит значение записи к списку строк: JSON-массив строк
+// или строка с элементами через запятую
+func importList(raw interface{}) ([]string, bool) {
+	switch value := raw.(type) {
+	case nil:
+		return nil, true
+	case string:
+		return splitImportList(value), true
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			text, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			list = append(list, text)
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+// splitImportList разделяет строку со списком через запятую, убирая пустые элементы
+func splitImportList(text string) []string {
+	var list []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
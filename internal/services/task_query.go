@@ -0,0 +1,176 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/pkg/cursor"
+	"golang_server/pkg/filter"
+)
+
+// taskFilterSchema поля задач, доступные в выражении фильтра
+var taskFilterSchema = filter.Schema{
+	"id":           {Column: "id", Type: filter.Number},
+	"title":        {Column: "title", Type: filter.String},
+	"description":  {Column: "description", Type: filter.String},
+	"status":       {Column: "status", Type: filter.String},
+	"priority":     {Column: "priority", Type: filter.Enum, Values: []string{"low", "medium", "high", "critical"}},
+	"project_id":   {Column: "project_id", Type: filter.Number, Nullable: true},
+	"workflow_id":  {Column: "workflow_id", Type: filter.Number, Nullable: true},
+	"parent_id":    {Column: "parent_id", Type: filter.Number, Nullable: true},
+	"start_date":   {Column: "start_date", Type: filter.Date},
+	"end_date":     {Column: "end_date", Type: filter.Date},
+	"created_at":   {Column: "created_at", Type: filter.Date},
+	"updated_at":   {Column: "updated_at", Type: filter.Date},
+	"completed_at": {Column: "completed_at", Type: filter.Date, Nullable: true},
+	"archived_at":  {Column: "archived_at", Type: filter.Date, Nullable: true},
+}
+
+// taskSortFields поля, по которым можно сортировать задачи (кроме cf.<ключ>)
+var taskSortFields = map[string]bool{
+	"created_at":   true,
+	"updated_at":   true,
+	"start_date":   true,
+	"end_date":     true,
+	"completed_at": true,
+	"status":       true,
+	"title":        true,
+	"priority":     true,
+	"position":     true,
+}
+
+// prepareTaskQuery проверяет параметры списка задач и готовит для репозитория
+// условие фильтра, сортировку и условия по пользовательским полям
+func (s *taskService) prepareTaskQuery(userID uint, params *models.TaskQueryParams) error {
+	// Выражения представления и запроса компилируются отдельно,
+	// чтобы позиция в сообщении об ошибке указывала на текст запроса
+	var conditions []string
+	for _, expression := range []string{params.ViewFilter, params.Filter} {
+		if expression == "" {
+			continue
+		}
+		condition, args, err := filter.Compile(expression, taskFilterSchema, time.Now())
+		if err != nil {
+			return err
+		}
+		conditions = append(conditions, condition)
+		params.FilterArgs = append(params.FilterArgs, args...)
+	}
+	params.FilterCondition = strings.Join(conditions, " AND ")
+
+	if params.Overdue {
+		location, err := s.userLocation(userID)
+		if err != nil {
+			return err
+		}
+		before := overdueBefore(time.Now(), location)
+		params.OverdueBefore = &before
+	}
+
+	sortFields, err := parseTaskSort(params.Sort, params.Order)
+	if err != nil {
+		return err
+	}
+	params.SortFields = sortFields
+
+	return s.customFieldService.BuildQuery(userID, params)
+}
+
+// parseTaskSort разбирает список полей сортировки вида "priority:desc,end_date".
+// Поля без направления сортируются в направлении order (по умолчанию по убыванию).
+func parseTaskSort(sort, order string) ([]models.TaskSortField, error) {
+	if sort == "" {
+		return nil, nil
+	}
+
+	defaultDesc := order != "asc"
+	var fields []models.TaskSortField
+	seen := make(map[string]bool)
+
+	for _, item := range strings.Split(sort, ",") {
+		item = strings.TrimSpace(item)
+		name, direction, hasDirection := strings.Cut(item, ":")
+
+		desc := defaultDesc
+		if hasDirection {
+			switch direction {
+			case "asc":
+				desc = false
+			case "desc":
+				desc = true
+			default:
+				return nil, errors.New("invalid sort: unknown direction \"" + direction + "\" for " + name + ", expected asc or desc")
+			}
+		}
+
+		if name == "" {
+			return nil, errors.New("invalid sort: empty field name")
+		}
+		if !taskSortFields[name] && !strings.HasPrefix(name, "cf.") {
+			return nil, errors.New("invalid sort: unknown field \"" + name + "\"")
+		}
+		if seen[name] {
+			return nil, errors.New("invalid sort: duplicate field \"" + name + "\"")
+		}
+		seen[name] = true
+
+		fields = append(fields, models.TaskSortField{Field: name, Desc: desc})
+	}
+
+	return fields, nil
+}
+
+// taskCursor содержимое курсора пагинации: значения ключей сортировки граничной задачи
+// (последним идет ее ID), направление и отпечаток запроса, для которого выдан курсор
+type taskCursor struct {
+	Values   []interface{} `json:"v"`
+	Backward bool          `json:"b,omitempty"`
+	Query    string        `json:"q"`
+}
+
+// encodeTaskCursor строит курсор страницы до (backward) или после задачи taskID
+func (s *taskService) encodeTaskCursor(taskID uint, params models.TaskQueryParams, query string, backward bool) (string, error) {
+	values, err := s.taskRepo.GetSortValues(taskID, params)
+	if err != nil {
+		return "", err
+	}
+	return cursor.Encode(s.cursorSecret, taskCursor{Values: values, Backward: backward, Query: query})
+}
+
+// taskQuerySignature вычисляет отпечаток фильтров и сортировки: курсор действителен
+// только для запроса с теми же условиями
+func taskQuerySignature(params models.TaskQueryParams) string {
+	data, _ := json.Marshal(struct {
+		Status       string
+		ProjectID    *uint
+		Search       string
+		Filter       string
+		ViewFilter   string
+		CustomFields map[string]string
+		Sort         string
+		Order        string
+		// Archived и Overdue не пустые только вне режима по умолчанию, чтобы подпись
+		// ранее выданных курсоров не изменилась
+		Archived string `json:",omitempty"`
+		Overdue  bool   `json:",omitempty"`
+	}{params.Status, params.ProjectID, params.Search, params.Filter, params.ViewFilter, params.CustomFields, params.Sort, params.Order, archivedMode(params), params.Overdue})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// archivedMode возвращает режим показа задач из архива для подписи запроса
+func archivedMode(params models.TaskQueryParams) string {
+	switch {
+	case params.ArchivedOnly:
+		return "only"
+	case params.IncludeArchived:
+		return "include"
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang_server/internal/models"
+)
+
+// collectPages проходит список задач по курсорам next_cursor до последней страницы,
+// затем по prev_cursor обратно к первой и возвращает ID задач обоих обходов в порядке списка
+func (e *testEnv) collectPages(t *testing.T, userID uint, params models.TaskQueryParams) (forward, backward []uint) {
+	t.Helper()
+
+	var last *models.TaskPage
+	var lastIDs []uint
+	for page := 0; ; page++ {
+		if page > 20 {
+			t.Fatal("forward pagination does not end")
+		}
+		result, err := e.taskService.GetTasks(userID, params)
+		if err != nil {
+			t.Fatalf("get tasks: %v", err)
+		}
+		lastIDs = lastIDs[:0]
+		for _, task := range result.Tasks {
+			forward = append(forward, task.ID)
+			lastIDs = append(lastIDs, task.ID)
+		}
+		last = result
+		if result.NextCursor == "" {
+			break
+		}
+		params.Cursor = result.NextCursor
+	}
+
+	pages := [][]uint{lastIDs}
+	for page := 0; last.PrevCursor != ""; page++ {
+		if page > 20 {
+			t.Fatal("backward pagination does not end")
+		}
+		params.Cursor = last.PrevCursor
+		result, err := e.taskService.GetTasks(userID, params)
+		if err != nil {
+			t.Fatalf("get tasks: %v", err)
+		}
+		var ids []uint
+		for _, task := range result.Tasks {
+			ids = append(ids, task.ID)
+		}
+		pages = append([][]uint{ids}, pages...)
+		last = result
+	}
+	for _, ids := range pages {
+		backward = append(backward, ids...)
+	}
+	return forward, backward
+}
+
+func TestCursorPaginationVisitsEveryTaskOnce(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	// Задачи с одинаковыми датами окончания и с пустым completed_at
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var ids []uint
+	for i := 0; i < 7; i++ {
+		task := env.createTask(t, userID, "Task", day, day.AddDate(0, 0, i/3))
+		if i%2 == 0 {
+			env.completeTask(t, task.ID, day.AddDate(0, 0, i%3))
+		}
+		ids = append(ids, task.ID)
+	}
+
+	for _, sort := range []string{"", "end_date:asc", "completed_at:asc", "completed_at:desc,title"} {
+		t.Run(sort, func(t *testing.T) {
+			first, err := env.taskService.GetTasks(userID, models.TaskQueryParams{Sort: sort, Limit: 3})
+			if err != nil {
+				t.Fatalf("get tasks: %v", err)
+			}
+			if first.NextCursor == "" {
+				t.Fatal("first page has no next cursor")
+			}
+
+			forward, backward := env.collectPages(t, userID, models.TaskQueryParams{Sort: sort, Limit: 3})
+			if len(forward) != len(ids) {
+				t.Fatalf("forward = %v, want %d tasks", forward, len(ids))
+			}
+			seen := make(map[uint]bool)
+			for _, id := range forward {
+				if seen[id] {
+					t.Fatalf("task %d is visited twice: %v", id, forward)
+				}
+				seen[id] = true
+			}
+			if !reflect.DeepEqual(backward, forward) {
+				t.Errorf("backward = %v, want %v", backward, forward)
+			}
+		})
+	}
+}
+
+func TestCursorRejectsChangedQuery(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		env.createTask(t, userID, "Task", day, day)
+	}
+
+	page, err := env.taskService.GetTasks(userID, models.TaskQueryParams{Sort: "end_date:asc", Limit: 2})
+	if err != nil {
+		t.Fatalf("get tasks: %v", err)
+	}
+
+	_, err = env.taskService.GetTasks(userID, models.TaskQueryParams{Sort: "title", Limit: 2, Cursor: page.NextCursor})
+	if err == nil || !strings.Contains(err.Error(), "invalid cursor") {
+		t.Errorf("error = %v, want invalid cursor for a different sort", err)
+	}
+
+	_, err = env.taskService.GetTasks(userID, models.TaskQueryParams{Sort: "end_date:asc", Limit: 2, Cursor: page.NextCursor + "x"})
+	if err == nil || !strings.Contains(err.Error(), "invalid cursor") {
+		t.Errorf("error = %v, want invalid cursor for a tampered token", err)
+	}
+}
@@ -0,0 +1,539 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+	"golang_server/pkg/workdays"
+
+	"gorm.io/gorm"
+)
+
+// Расписание строится по дням: день задачи — дата в UTC, как и для сроков задач;
+// рабочие дни определяются календарем владельца задач.
+// Зависимая задача может начаться не раньше дня, следующего за окончанием задачи,
+// от которой она зависит. Завершенные задачи не переносятся и не ограничивают другие.
+
+const (
+	// timelineDefaultDays длина периода временной шкалы по умолчанию
+	timelineDefaultDays = 30
+	// timelineMaxDays наибольшая длина периода временной шкалы
+	timelineMaxDays = 366
+	// timelineDateFormat формат дат периода временной шкалы
+	timelineDateFormat = "2006-01-02"
+	// shiftMaxDays наибольший сдвиг задачи в днях
+	shiftMaxDays = 3660
+)
+
+// workingCalendar возвращает календарь рабочих дней пользователя
+func (s *taskService) workingCalendar(userID uint) (*workdays.Calendar, error) {
+	return s.workingCalendarService.Calendar(userID)
+}
+
+// GetTimeline собирает задачи пользователя, пересекающие период, их зависимости
+// и конфликты расписания. По умолчанию период — 30 дней с текущей даты пользователя.
+func (s *taskService) GetTimeline(userID uint, params models.TimelineParams) (*models.TimelineResponse, error) {
+	location, err := s.userLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := overdueBefore(time.Now(), location)
+	if params.From != nil {
+		from = params.From.UTC()
+	}
+	to := from.AddDate(0, 0, timelineDefaultDays-1)
+	if params.To != nil {
+		to = params.To.UTC()
+	}
+	if from.After(to) {
+		return nil, errors.New("invalid range: from is after to")
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > timelineMaxDays {
+		return nil, fmt.Errorf("invalid range: period is longer than %d days", timelineMaxDays)
+	}
+
+	if params.ProjectID != nil {
+		project, err := s.projectRepo.GetByID(*params.ProjectID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("project not found")
+			}
+			return nil, err
+		}
+		if project.UserID != userID {
+			return nil, errors.New("project not found")
+		}
+	}
+
+	calendar, err := s.workingCalendar(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskRepo.GetForTimeline(userID, models.TimelineFilter{From: from, To: to, ProjectID: params.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+
+	taskIDs := make([]uint, len(tasks))
+	shown := make(map[uint]*models.Task, len(tasks))
+	for i := range tasks {
+		taskIDs[i] = tasks[i].ID
+		shown[tasks[i].ID] = &tasks[i]
+	}
+	dependencies, err := s.dependencyRepo.GetByTaskIDs(taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Задачи, от которых зависят задачи периода, могут лежать вне периода
+	known := make(map[uint]*models.Task, len(tasks))
+	for id, task := range shown {
+		known[id] = task
+	}
+	var outside []uint
+	for _, dependency := range dependencies {
+		if known[dependency.DependsOnID] == nil {
+			outside = append(outside, dependency.DependsOnID)
+		}
+	}
+	if len(outside) > 0 {
+		predecessors, err := s.taskRepo.GetByIDs(outside)
+		if err != nil {
+			return nil, err
+		}
+		for i := range predecessors {
+			known[predecessors[i].ID] = &predecessors[i]
+		}
+	}
+
+	workflows := newWorkflowCache(s.workflowService, userID)
+	done := make(map[uint]bool, len(known))
+	categories := make(map[uint]models.StatusCategory, len(known))
+	for id, task := range known {
+		category, err := workflows.category(task)
+		if err != nil {
+			return nil, err
+		}
+		categories[id] = category
+		done[id] = category == models.StatusCategoryDone
+	}
+
+	timeline := &models.TimelineResponse{
+		From:         from.Format(timelineDateFormat),
+		To:           to.Format(timelineDateFormat),
+		ProjectID:    params.ProjectID,
+		Tasks:        make([]models.TimelineTask, 0, len(tasks)),
+		Dependencies: []models.TaskDependency{},
+		Conflicts:    []models.ScheduleConflict{},
+	}
+
+	dependsOn := make(map[uint][]uint)
+	for _, dependency := range dependencies {
+		if known[dependency.DependsOnID] == nil {
+			continue
+		}
+		dependsOn[dependency.TaskID] = append(dependsOn[dependency.TaskID], dependency.DependsOnID)
+		if shown[dependency.DependsOnID] != nil {
+			timeline.Dependencies = append(timeline.Dependencies, dependency)
+		}
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+		item := models.TimelineTask{
+			ID:          task.ID,
+			Title:       task.Title,
+			Status:      task.Status,
+			Category:    categories[task.ID],
+			Priority:    task.Priority,
+			StartDate:   task.StartDate,
+			EndDate:     task.EndDate,
+			ProjectID:   task.ProjectID,
+			ParentID:    task.ParentID,
+			Version:     task.Version,
+			Assignees:   taskAssignees(task),
+			WorkingDays: calendar.Count(scheduleDay(task.StartDate), scheduleDay(task.EndDate)),
+			DependsOn:   dependsOn[task.ID],
+		}
+		if item.DependsOn == nil {
+			item.DependsOn = []uint{}
+		}
+		timeline.Tasks = append(timeline.Tasks, item)
+	}
+
+	timeline.Conflicts = append(timeline.Conflicts, overlapConflicts(tasks, done)...)
+	for _, dependency := range dependencies {
+		predecessor, dependent := known[dependency.DependsOnID], shown[dependency.TaskID]
+		if predecessor == nil || done[predecessor.ID] || done[dependent.ID] {
+			continue
+		}
+		if scheduleDay(dependent.StartDate).Before(earliestStart(predecessor, nil)) {
+			timeline.Conflicts = append(timeline.Conflicts, models.ScheduleConflict{
+				Type:    models.ConflictTypeDependency,
+				TaskIDs: []uint{predecessor.ID, dependent.ID},
+			})
+		}
+	}
+	for i := range tasks {
+		task := &tasks[i]
+		if done[task.ID] {
+			continue
+		}
+		days := []time.Time{scheduleDay(task.StartDate)}
+		if end := scheduleDay(task.EndDate); !end.Equal(days[0]) {
+			days = append(days, end)
+		}
+		for _, day := range days {
+			if !calendar.IsWorkingDay(day) {
+				timeline.Conflicts = append(timeline.Conflicts, models.ScheduleConflict{
+					Type:    models.ConflictTypeNonWorkingDay,
+					TaskIDs: []uint{task.ID},
+					Date:    day.Format(timelineDateFormat),
+				})
+			}
+		}
+	}
+	if err := calendar.Err(); err != nil {
+		return nil, err
+	}
+
+	return timeline, nil
+}
+
+// ShiftTask переносит задачу с сохранением длительности и переносит вперед задачи,
+// которые зависят от нее напрямую или через другие задачи, если они начинаются раньше,
+// чем позволяют зависимости. Все переносы сохраняются в одной транзакции.
+func (s *taskService) ShiftTask(userID, taskID uint, req models.ShiftTaskRequest) (*models.ShiftTaskResult, error) {
+	if (req.Days == nil) == (req.StartDate == nil) {
+		return nil, errors.New("invalid shift: specify either days or start_date")
+	}
+	if req.Days != nil && (*req.Days > shiftMaxDays || *req.Days < -shiftMaxDays) {
+		return nil, fmt.Errorf("invalid shift: days must be between %d and %d", -shiftMaxDays, shiftMaxDays)
+	}
+	workingDays := req.WorkingDays == nil || *req.WorkingDays
+
+	task, err := s.getOwnTask(userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != task.Version {
+		return nil, repository.ErrVersionConflict
+	}
+
+	var calendar *workdays.Calendar
+	if workingDays {
+		calendar, err = s.workingCalendar(userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var start time.Time
+	switch {
+	case req.StartDate != nil:
+		start = req.StartDate.UTC()
+	case workingDays:
+		start = calendar.AddWorkingDays(task.StartDate.UTC(), *req.Days)
+	default:
+		start = task.StartDate.UTC().AddDate(0, 0, *req.Days)
+	}
+	if workingDays {
+		start = calendar.NextWorkingDay(start)
+	}
+
+	result := &models.ShiftTaskResult{DryRun: req.DryRun, Changes: []models.ScheduleChange{}}
+	planned := map[uint]*models.Task{task.ID: task}
+	plan := func(task *models.Task, start time.Time) {
+		end := shiftedEnd(task, start, calendar)
+		if start.Equal(task.StartDate) && end.Equal(task.EndDate) {
+			return
+		}
+		result.Changes = append(result.Changes, models.ScheduleChange{
+			TaskID:       task.ID,
+			Title:        task.Title,
+			OldStartDate: task.StartDate,
+			OldEndDate:   task.EndDate,
+			StartDate:    start,
+			EndDate:      end,
+			Version:      task.Version,
+		})
+		moved := *task
+		moved.StartDate, moved.EndDate = start, end
+		planned[task.ID] = &moved
+	}
+	plan(task, start)
+
+	dependents, err := s.dependentsInOrder(userID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(dependents.order) > 0 {
+		workflows := newWorkflowCache(s.workflowService, userID)
+		done := make(map[uint]bool, len(dependents.tasks))
+		for id, dependent := range dependents.tasks {
+			category, err := workflows.category(dependent)
+			if err != nil {
+				return nil, err
+			}
+			done[id] = category == models.StatusCategoryDone || dependent.ArchivedAt != nil
+		}
+
+		for _, id := range dependents.order {
+			dependent := dependents.tasks[id]
+			if dependent == nil || done[id] {
+				continue
+			}
+
+			var required time.Time
+			for _, predecessorID := range dependents.dependsOn[id] {
+				predecessor := planned[predecessorID]
+				if predecessor == nil {
+					predecessor = dependents.tasks[predecessorID]
+				}
+				if predecessor == nil || done[predecessorID] {
+					continue
+				}
+				if earliest := earliestStart(predecessor, calendar); earliest.After(required) {
+					required = earliest
+				}
+			}
+			if scheduleDay(dependent.StartDate).Before(required) {
+				plan(dependent, withClock(required, dependent.StartDate))
+			}
+		}
+	}
+
+	if calendar != nil {
+		if err := calendar.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if req.DryRun || len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	err = s.transactor.Transaction(func(tx *gorm.DB) error {
+		txService := s.withTx(tx)
+		for i := range result.Changes {
+			change := &result.Changes[i]
+			version := change.Version
+			updated, err := txService.UpdateTask(userID, change.TaskID, models.UpdateTaskRequest{
+				StartDate:       &change.StartDate,
+				EndDate:         &change.EndDate,
+				ExpectedVersion: &version,
+			})
+			if err != nil {
+				return err
+			}
+			change.Version = updated.Version
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// scheduleDependents зависимые задачи в порядке, в котором их нужно переносить
+type scheduleDependents struct {
+	// order зависимые задачи так, что задача идет после всех задач, от которых она зависит
+	order []uint
+	// tasks зависимые задачи и задачи, от которых они зависят; задач в корзине нет
+	tasks map[uint]*models.Task
+	// dependsOn задачи, от которых зависит каждая зависимая задача
+	dependsOn map[uint][]uint
+}
+
+// dependentsInOrder находит задачи, зависящие от задачи taskID напрямую или через
+// другие задачи, и упорядочивает их по зависимостям
+func (s *taskService) dependentsInOrder(userID, taskID uint) (*scheduleDependents, error) {
+	dependencies, err := s.dependencyRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dependentsOf := make(map[uint][]uint)
+	for _, dependency := range dependencies {
+		dependentsOf[dependency.DependsOnID] = append(dependentsOf[dependency.DependsOnID], dependency.TaskID)
+	}
+
+	affected := map[uint]bool{taskID: true}
+	queue := []uint{taskID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range dependentsOf[current] {
+			if !affected[next] {
+				affected[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	result := &scheduleDependents{
+		tasks:     make(map[uint]*models.Task),
+		dependsOn: make(map[uint][]uint),
+	}
+	if len(affected) == 1 {
+		return result, nil
+	}
+
+	// Число еще не упорядоченных задач, от которых зависит каждая затронутая задача
+	pending := make(map[uint]int)
+	var ids []uint
+	for _, dependency := range dependencies {
+		if !affected[dependency.TaskID] {
+			continue
+		}
+		result.dependsOn[dependency.TaskID] = append(result.dependsOn[dependency.TaskID], dependency.DependsOnID)
+		ids = append(ids, dependency.TaskID, dependency.DependsOnID)
+		if affected[dependency.DependsOnID] {
+			pending[dependency.TaskID]++
+		}
+	}
+
+	tasks, err := s.taskRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		result.tasks[tasks[i].ID] = &tasks[i]
+	}
+
+	queue = []uint{taskID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current != taskID {
+			result.order = append(result.order, current)
+		}
+		for _, next := range dependentsOf[current] {
+			pending[next]--
+			if pending[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	return result, nil
+}
+
+// shiftedEnd возвращает окончание задачи, перенесенной на start. С календарем
+// сохраняется число рабочих дней задачи, без него — длительность во времени.
+func shiftedEnd(task *models.Task, start time.Time, calendar *workdays.Calendar) time.Time {
+	duration := task.EndDate.Sub(task.StartDate)
+	if calendar == nil {
+		return start.Add(duration)
+	}
+
+	days := calendar.Count(scheduleDay(task.StartDate), scheduleDay(task.EndDate))
+	if days == 0 {
+		return start.Add(duration)
+	}
+	end := withClock(calendar.AddWorkingDays(scheduleDay(start), days-1), task.EndDate)
+	if end.Before(start) {
+		return start.Add(duration)
+	}
+	return end
+}
+
+// earliestStart возвращает день, с которого может начаться задача, зависящая от task:
+// следующий за окончанием task, а с календарем — ближайший рабочий
+func earliestStart(task *models.Task, calendar *workdays.Calendar) time.Time {
+	day := scheduleDay(task.EndDate).AddDate(0, 0, 1)
+	if calendar != nil {
+		day = calendar.NextWorkingDay(day)
+	}
+	return day
+}
+
+// overlapConflicts находит пересечения незавершенных задач у каждого исполнителя.
+// Задачи должны быть упорядочены по началу.
+func overlapConflicts(tasks []models.Task, done map[uint]bool) []models.ScheduleConflict {
+	byUser := make(map[uint][]*models.Task)
+	var users []uint
+	for i := range tasks {
+		task := &tasks[i]
+		if done[task.ID] {
+			continue
+		}
+		for _, userID := range taskAssignees(task) {
+			if _, ok := byUser[userID]; !ok {
+				users = append(users, userID)
+			}
+			byUser[userID] = append(byUser[userID], task)
+		}
+	}
+
+	var conflicts []models.ScheduleConflict
+	for _, userID := range users {
+		userTasks := byUser[userID]
+		for i, first := range userTasks {
+			_, firstEnd := taskInterval(first)
+			for _, second := range userTasks[i+1:] {
+				secondStart, secondEnd := taskInterval(second)
+				if !secondStart.Before(firstEnd) {
+					break
+				}
+				if !secondEnd.After(secondStart) {
+					continue
+				}
+				from, to := second.StartDate, first.EndDate
+				if second.EndDate.Before(to) {
+					to = second.EndDate
+				}
+				conflicts = append(conflicts, models.ScheduleConflict{
+					Type:    models.ConflictTypeOverlap,
+					TaskIDs: []uint{first.ID, second.ID},
+					UserID:  &userID,
+					From:    &from,
+					To:      &to,
+				})
+			}
+		}
+	}
+	return conflicts
+}
+
+// taskAssignees возвращает назначенных пользователей задачи (значения полей типа user),
+// а если их нет — владельца задачи
+func taskAssignees(task *models.Task) []uint {
+	var assignees []uint
+	seen := make(map[uint]bool)
+	for _, value := range task.CustomFieldValues {
+		if value.Field.Type == models.CustomFieldTypeUser && value.UserValue != nil && !seen[*value.UserValue] {
+			seen[*value.UserValue] = true
+			assignees = append(assignees, *value.UserValue)
+		}
+	}
+	if len(assignees) == 0 {
+		assignees = []uint{task.UserID}
+	}
+	return assignees
+}
+
+// taskInterval возвращает интервал, который задача занимает в расписании: задача
+// с датами без времени занимает дни целиком, поэтому ее окончание — конец последнего дня
+func taskInterval(task *models.Task) (time.Time, time.Time) {
+	if isAllDay(task) {
+		return task.StartDate, task.EndDate.AddDate(0, 0, 1)
+	}
+	return task.StartDate, task.EndDate
+}
+
+// scheduleDay возвращает начало дня момента t в UTC
+func scheduleDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// withClock возвращает момент дня day с тем же временем суток в UTC, что и у clock
+func withClock(day, clock time.Time) time.Time {
+	day, clock = day.UTC(), clock.UTC()
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), time.UTC)
+}
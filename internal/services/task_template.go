@@ -0,0 +1,411 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxTemplateSubtasks максимальное число подзадач в шаблоне
+	maxTemplateSubtasks = 50
+	// maxTemplateChecklist максимальное число пунктов чек-листа одной задачи шаблона
+	maxTemplateChecklist = 100
+	// maxTemplateOffsetDays максимальное по модулю смещение дат от даты привязки
+	maxTemplateOffsetDays = 3650
+)
+
+// templateAnchorVariable переменная, которая заполняется датой привязки в формате YYYY-MM-DD
+const templateAnchorVariable = "anchor_date"
+
+// templatePlaceholder переменная шаблона вида {{имя}}; пробелы внутри скобок допускаются
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// TaskTemplateService интерфейс для сервиса шаблонов задач
+type TaskTemplateService interface {
+	CreateTemplate(userID uint, req models.CreateTaskTemplateRequest) (*models.TaskTemplateResponse, error)
+	GetTemplates(userID uint, projectID *uint) ([]models.TaskTemplateResponse, error)
+	GetTemplate(userID, templateID uint) (*models.TaskTemplateResponse, error)
+	UpdateTemplate(userID, templateID uint, req models.UpdateTaskTemplateRequest) (*models.TaskTemplateResponse, error)
+	DeleteTemplate(userID, templateID uint) error
+	InstantiateTemplate(userID, templateID uint, req models.InstantiateTaskTemplateRequest) (*models.TaskTemplateInstance, error)
+}
+
+// taskTemplateService реализация сервиса шаблонов задач
+type taskTemplateService struct {
+	templateRepo repository.TaskTemplateRepository
+	projectRepo  repository.ProjectRepository
+	taskService  TaskService
+}
+
+// NewTaskTemplateService создает новый сервис шаблонов задач
+func NewTaskTemplateService(templateRepo repository.TaskTemplateRepository, projectRepo repository.ProjectRepository, taskService TaskService) TaskTemplateService {
+	return &taskTemplateService{
+		templateRepo: templateRepo,
+		projectRepo:  projectRepo,
+		taskService:  taskService,
+	}
+}
+
+// CreateTemplate создает шаблон
+func (s *taskTemplateService) CreateTemplate(userID uint, req models.CreateTaskTemplateRequest) (*models.TaskTemplateResponse, error) {
+	template := &models.TaskTemplate{
+		UserID:          userID,
+		ProjectID:       req.ProjectID,
+		Name:            req.Name,
+		Title:           req.Title,
+		Description:     req.Description,
+		Priority:        req.Priority,
+		StartOffsetDays: req.StartOffsetDays,
+		EndOffsetDays:   req.EndOffsetDays,
+		Tags:            req.Tags,
+		Checklist:       req.Checklist,
+		Subtasks:        req.Subtasks,
+	}
+	if err := s.validateTemplate(userID, template); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Create(template); err != nil {
+		return nil, err
+	}
+
+	return templateResponse(template), nil
+}
+
+// GetTemplates получает шаблоны пользователя
+func (s *taskTemplateService) GetTemplates(userID uint, projectID *uint) ([]models.TaskTemplateResponse, error) {
+	templates, err := s.templateRepo.GetByUserID(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	templateResponses := make([]models.TaskTemplateResponse, len(templates))
+	for i := range templates {
+		templateResponses[i] = *templateResponse(&templates[i])
+	}
+	return templateResponses, nil
+}
+
+// GetTemplate получает шаблон по ID
+func (s *taskTemplateService) GetTemplate(userID, templateID uint) (*models.TaskTemplateResponse, error) {
+	template, err := s.getTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return templateResponse(template), nil
+}
+
+// UpdateTemplate обновляет шаблон
+func (s *taskTemplateService) UpdateTemplate(userID, templateID uint, req models.UpdateTaskTemplateRequest) (*models.TaskTemplateResponse, error) {
+	template, err := s.getTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		template.Name = *req.Name
+	}
+	if req.Title != nil {
+		template.Title = *req.Title
+	}
+	if req.Description != nil {
+		template.Description = *req.Description
+	}
+	if req.Priority != nil {
+		template.Priority = *req.Priority
+	}
+	if req.StartOffsetDays != nil {
+		template.StartOffsetDays = *req.StartOffsetDays
+	}
+	if req.EndOffsetDays != nil {
+		template.EndOffsetDays = *req.EndOffsetDays
+	}
+	if req.Tags != nil {
+		template.Tags = *req.Tags
+	}
+	if req.Checklist != nil {
+		template.Checklist = *req.Checklist
+	}
+	if req.Subtasks != nil {
+		template.Subtasks = *req.Subtasks
+	}
+
+	if err := s.validateTemplate(userID, template); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Update(template); err != nil {
+		return nil, err
+	}
+
+	return templateResponse(template), nil
+}
+
+// DeleteTemplate удаляет шаблон; задачи, созданные по нему, остаются
+func (s *taskTemplateService) DeleteTemplate(userID, templateID uint) error {
+	template, err := s.getTemplate(userID, templateID)
+	if err != nil {
+		return err
+	}
+	return s.templateRepo.Delete(template.ID)
+}
+
+// InstantiateTemplate создает по шаблону задачу и ее подзадачи. Даты задач
+// отсчитываются от даты привязки, переменные {{имя}} заполняются значениями
+// из запроса. Задачи создаются атомарно: при ошибке не создается ни одна.
+func (s *taskTemplateService) InstantiateTemplate(userID, templateID uint, req models.InstantiateTaskTemplateRequest) (*models.TaskTemplateInstance, error) {
+	template, err := s.getTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	anchor, err := parseDateValue(req.AnchorDate)
+	if err != nil {
+		return nil, errors.New("invalid anchor date: expected YYYY-MM-DD or RFC 3339")
+	}
+
+	variables := map[string]string{
+		templateAnchorVariable: anchor.Format("2006-01-02"),
+	}
+	for name, value := range req.Variables {
+		variables[name] = value
+	}
+	var missing []string
+	for _, name := range templateVariables(template) {
+		if _, ok := variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("invalid variables: missing values for %s", strings.Join(missing, ", "))
+	}
+
+	projectID := template.ProjectID
+	if req.ProjectID != nil {
+		projectID = req.ProjectID
+	}
+
+	taskReq := models.CreateTaskRequest{
+		Title:       fillPlaceholders(template.Title, variables),
+		Description: fillPlaceholders(template.Description, variables),
+		Priority:    template.Priority,
+		StartDate:   anchor.AddDate(0, 0, template.StartOffsetDays),
+		EndDate:     anchor.AddDate(0, 0, template.EndOffsetDays),
+		ProjectID:   projectID,
+		Tags:        fillAll(template.Tags, variables),
+		Checklist:   checklistRequests(fillAll(template.Checklist, variables)),
+	}
+	subtaskReqs := make([]models.CreateTaskRequest, len(template.Subtasks))
+	for i, subtask := range template.Subtasks {
+		subtaskReqs[i] = models.CreateTaskRequest{
+			Title:       fillPlaceholders(subtask.Title, variables),
+			Description: fillPlaceholders(subtask.Description, variables),
+			Priority:    subtask.Priority,
+			StartDate:   anchor.AddDate(0, 0, subtask.StartOffsetDays),
+			EndDate:     anchor.AddDate(0, 0, subtask.EndOffsetDays),
+			Tags:        fillAll(subtask.Tags, variables),
+			Checklist:   checklistRequests(fillAll(subtask.Checklist, variables)),
+		}
+	}
+
+	// После подстановки переменных название должно остаться непустым и не длиннее 255 символов
+	for _, title := range append([]string{taskReq.Title}, subtaskTitles(subtaskReqs)...) {
+		if length := utf8.RuneCountInString(strings.TrimSpace(title)); length < 1 || length > 255 {
+			return nil, errors.New("invalid variables: task title must be between 1 and 255 characters")
+		}
+	}
+
+	task, subtasks, err := s.taskService.CreateTaskTree(userID, taskReq, subtaskReqs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TaskTemplateInstance{
+		Task:     *task,
+		Subtasks: subtasks,
+	}, nil
+}
+
+// getTemplate получает шаблон и проверяет, что он принадлежит пользователю
+func (s *taskTemplateService) getTemplate(userID, templateID uint) (*models.TaskTemplate, error) {
+	template, err := s.templateRepo.GetByID(templateID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("template not found")
+		}
+		return nil, err
+	}
+
+	// Проверяем, что шаблон принадлежит пользователю
+	if template.UserID != userID {
+		return nil, errors.New("access denied")
+	}
+
+	return template, nil
+}
+
+// validateTemplate проверяет проект, приоритеты, смещения дат, метки и чек-листы шаблона
+func (s *taskTemplateService) validateTemplate(userID uint, template *models.TaskTemplate) error {
+	if template.ProjectID != nil {
+		project, err := s.projectRepo.GetByID(*template.ProjectID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("project not found")
+			}
+			return err
+		}
+		if project.UserID != userID {
+			return errors.New("project not found")
+		}
+	}
+
+	if template.Priority == "" {
+		template.Priority = models.TaskPriorityMedium
+	}
+	if err := validateTemplateTask("task", template.Priority, template.StartOffsetDays, template.EndOffsetDays, template.Checklist); err != nil {
+		return err
+	}
+	tags, err := normalizeTags(template.Tags)
+	if err != nil {
+		return err
+	}
+	template.Tags = tags
+
+	if len(template.Subtasks) > maxTemplateSubtasks {
+		return fmt.Errorf("invalid template: a template can have at most %d subtasks", maxTemplateSubtasks)
+	}
+	for i := range template.Subtasks {
+		subtask := &template.Subtasks[i]
+		if subtask.Priority == "" {
+			subtask.Priority = models.TaskPriorityMedium
+		}
+		name := fmt.Sprintf("subtask %d", i+1)
+		if err := validateTemplateTask(name, subtask.Priority, subtask.StartOffsetDays, subtask.EndOffsetDays, subtask.Checklist); err != nil {
+			return err
+		}
+		tags, err := normalizeTags(subtask.Tags)
+		if err != nil {
+			return err
+		}
+		subtask.Tags = tags
+	}
+
+	return nil
+}
+
+// validateTemplateTask проверяет приоритет, смещения дат и чек-лист задачи шаблона
+func validateTemplateTask(name string, priority models.TaskPriority, startOffset, endOffset int, checklist []string) error {
+	if !priority.IsValid() {
+		return fmt.Errorf("invalid template: %s has invalid priority", name)
+	}
+	if abs(startOffset) > maxTemplateOffsetDays || abs(endOffset) > maxTemplateOffsetDays {
+		return fmt.Errorf("invalid template: %s date offsets must be within %d days", name, maxTemplateOffsetDays)
+	}
+	if endOffset < startOffset {
+		return fmt.Errorf("invalid template: %s end_offset_days cannot be before start_offset_days", name)
+	}
+	if len(checklist) > maxTemplateChecklist {
+		return fmt.Errorf("invalid template: %s checklist can have at most %d items", name, maxTemplateChecklist)
+	}
+	for _, item := range checklist {
+		if length := utf8.RuneCountInString(strings.TrimSpace(item)); length < 1 || length > 255 {
+			return fmt.Errorf("invalid template: %s checklist items must be between 1 and 255 characters", name)
+		}
+	}
+	return nil
+}
+
+// templateResponse конвертирует шаблон в ответ вместе со списком его переменных
+func templateResponse(template *models.TaskTemplate) *models.TaskTemplateResponse {
+	response := template.ToResponse()
+	response.Variables = []string{}
+	for _, name := range templateVariables(template) {
+		if name != templateAnchorVariable {
+			response.Variables = append(response.Variables, name)
+		}
+	}
+	return &response
+}
+
+// templateVariables возвращает отсортированные имена переменных, используемых в шаблоне
+func templateVariables(template *models.TaskTemplate) []string {
+	texts := []string{template.Title, template.Description}
+	texts = append(texts, template.Tags...)
+	texts = append(texts, template.Checklist...)
+	for _, subtask := range template.Subtasks {
+		texts = append(texts, subtask.Title, subtask.Description)
+		texts = append(texts, subtask.Tags...)
+		texts = append(texts, subtask.Checklist...)
+	}
+
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, text := range texts {
+		for _, match := range templatePlaceholder.FindAllStringSubmatch(text, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fillPlaceholders заменяет переменные {{имя}} их значениями
+func fillPlaceholders(text string, variables map[string]string) string {
+	return templatePlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		return variables[name]
+	})
+}
+
+// fillAll заменяет переменные в каждой строке списка
+func fillAll(texts []string, variables map[string]string) []string {
+	if texts == nil {
+		return nil
+	}
+	filled := make([]string, len(texts))
+	for i, text := range texts {
+		filled[i] = fillPlaceholders(text, variables)
+	}
+	return filled
+}
+
+// checklistRequests превращает пункты чек-листа шаблона в пункты создаваемой задачи
+func checklistRequests(items []string) []models.CreateChecklistItemRequest {
+	if len(items) == 0 {
+		return nil
+	}
+	reqs := make([]models.CreateChecklistItemRequest, len(items))
+	for i, item := range items {
+		reqs[i] = models.CreateChecklistItemRequest{Title: strings.TrimSpace(item)}
+	}
+	return reqs
+}
+
+// subtaskTitles возвращает названия подзадач
+func subtaskTitles(reqs []models.CreateTaskRequest) []string {
+	titles := make([]string, len(reqs))
+	for i, req := range reqs {
+		titles[i] = req.Title
+	}
+	return titles
+}
+
+// abs возвращает модуль числа
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
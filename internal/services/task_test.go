@@ -0,0 +1,237 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+func TestTaskDatesAreStoredInUTC(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	moscow := time.FixedZone("", 3*60*60)
+	// 01:00 по Москве 19 октября — 22:00 UTC 18 октября
+	end := time.Date(2026, 10, 19, 1, 0, 0, 0, moscow)
+	created := env.createTask(t, userID, "Early", end.Add(-time.Hour), end)
+	env.createTask(t, userID, "Late", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+
+	task, err := env.taskService.GetTaskByID(userID, created.ID)
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if !task.EndDate.Equal(end) || task.EndDate.Location() != time.UTC {
+		t.Fatalf("end date = %v, want %v in UTC", task.EndDate, end.UTC())
+	}
+
+	page, err := env.taskService.GetTasks(userID, models.TaskQueryParams{
+		Filter: `end_date < "2026-10-19T00:00:00+00:00"`,
+	})
+	if err != nil {
+		t.Fatalf("get tasks: %v", err)
+	}
+	if len(page.Tasks) != 1 || page.Tasks[0].ID != created.ID {
+		t.Fatalf("filtered tasks = %+v, want only task %d", page.Tasks, created.ID)
+	}
+
+	// Изменение даты тоже сохраняется в UTC
+	later := time.Date(2026, 10, 20, 2, 0, 0, 0, moscow)
+	if _, err := env.taskService.UpdateTask(userID, created.ID, models.UpdateTaskRequest{EndDate: &later}); err != nil {
+		t.Fatalf("update task: %v", err)
+	}
+	page, err = env.taskService.GetTasks(userID, models.TaskQueryParams{
+		Filter: `end_date >= "2026-10-19T23:00:00+00:00"`,
+	})
+	if err != nil {
+		t.Fatalf("get tasks: %v", err)
+	}
+	if len(page.Tasks) != 1 || page.Tasks[0].ID != created.ID {
+		t.Fatalf("filtered tasks after update = %+v, want only task %d", page.Tasks, created.ID)
+	}
+}
+
+func TestPurgeExpiredRemovesOnlyExpiredTasks(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	day := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	newTrashed := func(deletedAt time.Time) models.Task {
+		return models.Task{
+			Title:     "Trashed",
+			StartDate: day,
+			EndDate:   day,
+			Status:    models.TaskStatus("pending"),
+			Priority:  models.TaskPriorityMedium,
+			UserID:    userID,
+			DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true},
+		}
+	}
+
+	expired := purgeBatchSize + 5
+	tasks := make([]models.Task, 0, expired+1)
+	for i := 0; i < expired; i++ {
+		tasks = append(tasks, newTrashed(day))
+	}
+	tasks = append(tasks, newTrashed(day.AddDate(0, 1, 0)))
+	if err := env.db.Omit("CustomFieldValues").Create(&tasks).Error; err != nil {
+		t.Fatalf("create tasks: %v", err)
+	}
+
+	purged, err := env.taskService.PurgeExpired(day.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("purge expired: %v", err)
+	}
+	if purged != expired {
+		t.Fatalf("purged = %d, want %d", purged, expired)
+	}
+
+	var left []models.Task
+	env.db.Unscoped().Find(&left)
+	if len(left) != 1 || left[0].ID != tasks[len(tasks)-1].ID {
+		t.Errorf("left tasks = %d, want only the recently trashed one", len(left))
+	}
+}
+
+func TestDeleteAndRestoreChangeVersion(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	task := env.createTask(t, userID, "Task", start, start)
+
+	version := task.Version
+	if err := env.taskService.DeleteTask(userID, task.ID, &version); err != nil {
+		t.Fatalf("delete task: %v", err)
+	}
+	restored, err := env.taskService.RestoreTask(userID, task.ID)
+	if err != nil {
+		t.Fatalf("restore task: %v", err)
+	}
+	if restored.Version != task.Version+2 {
+		t.Errorf("version = %d, want %d after delete and restore", restored.Version, task.Version+2)
+	}
+
+	// Запрос с ETag, полученным до удаления, не должен изменить восстановленную задачу
+	if err := env.taskService.DeleteTask(userID, task.ID, &version); err == nil {
+		t.Error("delete with the pre-delete version should fail")
+	}
+}
+
+func TestWithTxReadsProjectsInTransaction(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	workflow, err := env.taskService.workflowService.GetDefaultWorkflow(userID)
+	if err != nil {
+		t.Fatalf("get default workflow: %v", err)
+	}
+
+	err = repository.NewTransactor(env.db).Transaction(func(tx *gorm.DB) error {
+		txService := env.taskService.withTx(tx)
+		project := &models.Project{Name: "Project", UserID: userID, WorkflowID: workflow.ID}
+		if err := repository.NewProjectRepository(tx).Create(project); err != nil {
+			return err
+		}
+
+		// Проект, созданный в транзакции, виден задачам той же транзакции
+		resolved, err := txService.resolveWorkflow(userID, &project.ID)
+		if err != nil {
+			return err
+		}
+		if resolved.ID != workflow.ID {
+			t.Errorf("workflow = %d, want %d", resolved.ID, workflow.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+}
+
+func TestPurgeTaskDetachesSubtasks(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	parent := env.createTask(t, userID, "Parent", start, start)
+	child := env.createTask(t, userID, "Child", start, start)
+	if err := env.db.Model(&models.Task{}).Where("id = ?", child.ID).UpdateColumn("parent_id", parent.ID).Error; err != nil {
+		t.Fatalf("set parent: %v", err)
+	}
+
+	version := parent.Version
+	if err := env.taskService.DeleteTask(userID, parent.ID, &version); err != nil {
+		t.Fatalf("delete task: %v", err)
+	}
+	if err := env.taskService.PurgeTask(userID, parent.ID); err != nil {
+		t.Fatalf("purge task: %v", err)
+	}
+
+	detached, err := env.taskRepo.GetByID(child.ID)
+	if err != nil {
+		t.Fatalf("get child: %v", err)
+	}
+	if detached.ParentID != nil {
+		t.Errorf("parent_id = %d, want null", *detached.ParentID)
+	}
+	if detached.Version != child.Version+1 {
+		t.Errorf("version = %d, want %d", detached.Version, child.Version+1)
+	}
+}
+
+func TestBoardPositionsAreScopedByProject(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	workflow, err := env.taskService.workflowService.GetDefaultWorkflow(userID)
+	if err != nil {
+		t.Fatalf("get default workflow: %v", err)
+	}
+	projectRepo := repository.NewProjectRepository(env.db)
+	first := &models.Project{Name: "First", UserID: userID, WorkflowID: workflow.ID}
+	second := &models.Project{Name: "Second", UserID: userID, WorkflowID: workflow.ID}
+	for _, project := range []*models.Project{first, second} {
+		if err := projectRepo.Create(project); err != nil {
+			t.Fatalf("create project: %v", err)
+		}
+	}
+
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	createTask := func(title string, projectID uint) *models.TaskResponse {
+		task, err := env.taskService.CreateTask(userID, models.CreateTaskRequest{
+			Title:     title,
+			StartDate: day,
+			EndDate:   day,
+			ProjectID: &projectID,
+		})
+		if err != nil {
+			t.Fatalf("create task %q: %v", title, err)
+		}
+		return task
+	}
+	other := createTask("Other project", second.ID)
+	task := createTask("First project", first.ID)
+
+	// Задача другого проекта не занимает место в колонке доски
+	prev, err := env.taskRepo.GetPrevPosition(userID, &first.ID, task.Status, task.Position)
+	if err != nil || prev != "" {
+		t.Errorf("previous position = %q, %v, want none in the first project", prev, err)
+	}
+	last, err := env.taskRepo.GetLastPosition(userID, &second.ID, other.Status)
+	if err != nil || last != other.Position {
+		t.Errorf("last position = %q, %v, want %q", last, err, other.Position)
+	}
+	last, err = env.taskRepo.GetLastPosition(userID, nil, task.Status)
+	if err != nil || last != "" {
+		t.Errorf("last position without project = %q, %v, want none", last, err)
+	}
+
+	_, err = env.taskService.MoveTask(userID, task.ID, models.MoveTaskRequest{Status: task.Status, AfterID: &other.ID})
+	if err == nil || err.Error() != "neighbor task is not in the target column" {
+		t.Errorf("move after a task of another project: error = %v", err)
+	}
+}
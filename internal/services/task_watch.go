@@ -0,0 +1,117 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+
+	"golang_server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// mentionPattern упоминание пользователя вида @username в названии или описании задачи
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_@.])@([A-Za-z0-9_][A-Za-z0-9_.-]{2,49})`)
+
+// syncWatchers подписывает на задачу владельца, назначенных пользователей
+// (значения пользовательских полей типа user) и упомянутых пользователей,
+// которым доступен проект задачи. Пользователи, отписавшиеся от задачи, повторно не подписываются.
+func (s *taskService) syncWatchers(tx *gorm.DB, task *models.Task) error {
+	watchers := []models.TaskWatcher{{TaskID: task.ID, UserID: task.UserID, Reason: models.WatchReasonOwner, Watching: true}}
+	seen := map[uint]bool{task.UserID: true}
+	add := func(userID uint, reason models.WatchReason) {
+		if !seen[userID] {
+			seen[userID] = true
+			watchers = append(watchers, models.TaskWatcher{TaskID: task.ID, UserID: userID, Reason: reason, Watching: true})
+		}
+	}
+
+	for _, value := range task.CustomFieldValues {
+		if value.Field.Type == models.CustomFieldTypeUser && value.UserValue != nil {
+			add(*value.UserValue, models.WatchReasonAssignee)
+		}
+	}
+
+	// Упоминание не открывает доступ к задаче: подписываются только пользователи,
+	// которым уже доступен ее проект. Задача вне проекта доступна только владельцу.
+	usernames := mentionedUsernames(task.Title + "\n" + task.Description)
+	if len(usernames) > 0 && task.ProjectID != nil {
+		users, err := s.userRepo.WithTx(tx).GetByUsernames(usernames)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if seen[user.ID] {
+				continue
+			}
+			allowed, err := s.projectRepo.WithTx(tx).HasAccess(*task.ProjectID, user.ID)
+			if err != nil {
+				return err
+			}
+			if allowed {
+				add(user.ID, models.WatchReasonMentioned)
+			}
+		}
+	}
+
+	return s.watcherRepo.WithTx(tx).AddIfMissing(watchers)
+}
+
+// notifyWatchers создает уведомления подписчиков задачи об изменении из записи истории
+// с учетом их настроек: отключенные события и собственные изменения пропускаются
+// (кроме событий, отмеченных сервером)
+func (s *taskService) notifyWatchers(tx *gorm.DB, revision *models.TaskRevision) error {
+	watchers, err := s.watcherRepo.WithTx(tx).GetWatching(revision.TaskID)
+	if err != nil {
+		return err
+	}
+	if len(watchers) == 0 {
+		return nil
+	}
+
+	userIDs := make([]uint, len(watchers))
+	for i, watcher := range watchers {
+		userIDs[i] = watcher.UserID
+	}
+	preferences, err := s.notificationRepo.WithTx(tx).GetPreferences(userIDs)
+	if err != nil {
+		return err
+	}
+
+	var notifications []models.Notification
+	for _, watcher := range watchers {
+		preference := preferences[watcher.UserID]
+		if watcher.UserID == revision.UserID && !preference.OwnChanges && !revision.Action.IsSystem() {
+			continue
+		}
+		if !preference.Enabled(revision.Action) {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserID:     watcher.UserID,
+			TaskID:     revision.TaskID,
+			RevisionID: revision.ID,
+			ActorID:    revision.UserID,
+			Event:      revision.Action,
+			TaskTitle:  revision.Snapshot.Title,
+			Changes:    revision.Changes,
+		})
+	}
+
+	return s.notificationRepo.WithTx(tx).CreateBatch(notifications)
+}
+
+// mentionedUsernames возвращает имена пользователей, упомянутых в тексте, без повторов
+func mentionedUsernames(text string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		// Точка или дефис в конце относятся к тексту, а не к имени
+		username := strings.TrimRight(match[1], ".-")
+		key := strings.ToLower(username)
+		if len(username) >= 3 && !seen[key] {
+			seen[key] = true
+			usernames = append(usernames, username)
+		}
+	}
+	return usernames
+}
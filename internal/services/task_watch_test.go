@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+)
+
+func TestMentionWatchesOnlyUsersWithProjectAccess(t *testing.T) {
+	env := newTestEnv(t)
+	aliceID := env.createUser(t, "alice")
+	bobID := env.createUser(t, "bob")
+	carolID := env.createUser(t, "carol")
+
+	workflow, err := env.taskService.workflowService.GetDefaultWorkflow(aliceID)
+	if err != nil {
+		t.Fatalf("get default workflow: %v", err)
+	}
+	project := &models.Project{Name: "Project", UserID: aliceID, WorkflowID: workflow.ID}
+	if err := repository.NewProjectRepository(env.db).Create(project); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	createTask := func(title string, projectID *uint) *models.TaskResponse {
+		task, err := env.taskService.CreateTask(aliceID, models.CreateTaskRequest{
+			Title:     title,
+			StartDate: day,
+			EndDate:   day,
+			ProjectID: projectID,
+		})
+		if err != nil {
+			t.Fatalf("create task %q: %v", title, err)
+		}
+		return task
+	}
+	watching := func(taskID, userID uint) bool {
+		var count int64
+		err := env.db.Model(&models.TaskWatcher{}).Where("task_id = ? AND user_id = ?", taskID, userID).Count(&count).Error
+		if err != nil {
+			t.Fatalf("count watchers: %v", err)
+		}
+		return count > 0
+	}
+
+	// Carol получает доступ к проекту как подписчица одной из его задач
+	assigned := createTask("Assigned", &project.ID)
+	watcher := models.TaskWatcher{TaskID: assigned.ID, UserID: carolID, Reason: models.WatchReasonAssignee, Watching: true}
+	if err := env.db.Create(&watcher).Error; err != nil {
+		t.Fatalf("add watcher: %v", err)
+	}
+
+	mentioned := createTask("Ask @bob and @carol", &project.ID)
+	if watching(mentioned.ID, bobID) {
+		t.Error("bob has no access to the project and must not watch the task")
+	}
+	if !watching(mentioned.ID, carolID) {
+		t.Error("carol has access to the project and should watch the task")
+	}
+
+	private := createTask("Ask @carol", nil)
+	if watching(private.ID, carolID) {
+		t.Error("a mention in a task outside projects must not subscribe other users")
+	}
+}
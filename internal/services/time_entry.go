@@ -0,0 +1,267 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// TimeEntryService интерфейс для сервиса учета времени
+type TimeEntryService interface {
+	StartTimer(userID, taskID uint, req models.StartTimerRequest) (*models.TimeEntryResponse, error)
+	StopTimer(userID uint) (*models.TimeEntryResponse, error)
+	GetRunningTimer(userID uint) (*models.TimeEntryResponse, error)
+	GetTaskEntries(userID, taskID uint) ([]models.TimeEntryResponse, error)
+	CreateEntry(userID, taskID uint, req models.CreateTimeEntryRequest) (*models.TimeEntryResponse, error)
+	UpdateEntry(userID, entryID uint, req models.UpdateTimeEntryRequest) (*models.TimeEntryResponse, error)
+	DeleteEntry(userID, entryID uint) error
+	GetReport(userID uint, params models.TimeReportParams) ([]models.TimeReportRow, error)
+}
+
+// timeEntryService реализация сервиса учета времени
+type timeEntryService struct {
+	timeEntryRepo repository.TimeEntryRepository
+	taskRepo      repository.TaskRepository
+}
+
+// NewTimeEntryService создает новый сервис учета времени
+func NewTimeEntryService(timeEntryRepo repository.TimeEntryRepository, taskRepo repository.TaskRepository) TimeEntryService {
+	return &timeEntryService{
+		timeEntryRepo: timeEntryRepo,
+		taskRepo:      taskRepo,
+	}
+}
+
+// StartTimer запускает таймер по задаче
+func (s *timeEntryService) StartTimer(userID, taskID uint, req models.StartTimerRequest) (*models.TimeEntryResponse, error) {
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.timeEntryRepo.GetRunning(userID); err == nil {
+		return nil, errors.New("timer already running")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	entry := &models.TimeEntry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: time.Now().UTC(),
+		Note:      req.Note,
+	}
+
+	// Уникальный индекс по запущенным таймерам защищает от параллельного запуска
+	if err := s.timeEntryRepo.Create(entry); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return nil, errors.New("timer already running")
+		}
+		return nil, err
+	}
+
+	entryResponse := entry.ToResponse(time.Now())
+	return &entryResponse, nil
+}
+
+// StopTimer останавливает запущенный таймер пользователя
+func (s *timeEntryService) StopTimer(userID uint) (*models.TimeEntryResponse, error) {
+	entry, err := s.timeEntryRepo.GetRunning(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("no running timer")
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	entry.EndedAt = &now
+	entry.Duration = int64(now.Sub(entry.StartedAt).Seconds())
+
+	if err := s.timeEntryRepo.Update(entry); err != nil {
+		return nil, err
+	}
+
+	entryResponse := entry.ToResponse(now)
+	return &entryResponse, nil
+}
+
+// GetRunningTimer получает запущенный таймер пользователя (nil, если его нет)
+func (s *timeEntryService) GetRunningTimer(userID uint) (*models.TimeEntryResponse, error) {
+	entry, err := s.timeEntryRepo.GetRunning(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	entryResponse := entry.ToResponse(time.Now())
+	return &entryResponse, nil
+}
+
+// GetTaskEntries получает записи времени по задаче
+func (s *timeEntryService) GetTaskEntries(userID, taskID uint) ([]models.TimeEntryResponse, error) {
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.timeEntryRepo.GetByTaskID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entryResponses := make([]models.TimeEntryResponse, len(entries))
+	for i, entry := range entries {
+		entryResponses[i] = entry.ToResponse(now)
+	}
+
+	return entryResponses, nil
+}
+
+// CreateEntry добавляет запись времени вручную
+func (s *timeEntryService) CreateEntry(userID, taskID uint, req models.CreateTimeEntryRequest) (*models.TimeEntryResponse, error) {
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	if !req.EndedAt.After(req.StartedAt) {
+		return nil, errors.New("end time must be after start time")
+	}
+
+	startedAt := req.StartedAt.UTC()
+	endedAt := req.EndedAt.UTC()
+	entry := &models.TimeEntry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: startedAt,
+		EndedAt:   &endedAt,
+		Duration:  int64(endedAt.Sub(startedAt).Seconds()),
+		Note:      req.Note,
+	}
+
+	if err := s.timeEntryRepo.Create(entry); err != nil {
+		return nil, err
+	}
+
+	entryResponse := entry.ToResponse(time.Now())
+	return &entryResponse, nil
+}
+
+// UpdateEntry изменяет запись времени; указание времени окончания останавливает таймер
+func (s *timeEntryService) UpdateEntry(userID, entryID uint, req models.UpdateTimeEntryRequest) (*models.TimeEntryResponse, error) {
+	entry, err := s.getEntry(userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.StartedAt != nil {
+		entry.StartedAt = req.StartedAt.UTC()
+	}
+	if req.EndedAt != nil {
+		endedAt := req.EndedAt.UTC()
+		entry.EndedAt = &endedAt
+	}
+	if req.Note != nil {
+		entry.Note = *req.Note
+	}
+
+	now := time.Now().UTC()
+	end := now
+	if entry.EndedAt != nil {
+		end = *entry.EndedAt
+	}
+	if !end.After(entry.StartedAt) {
+		return nil, errors.New("end time must be after start time")
+	}
+	if entry.EndedAt != nil {
+		entry.Duration = int64(entry.EndedAt.Sub(entry.StartedAt).Seconds())
+	}
+
+	if err := s.timeEntryRepo.Update(entry); err != nil {
+		return nil, err
+	}
+
+	entryResponse := entry.ToResponse(now)
+	return &entryResponse, nil
+}
+
+// DeleteEntry удаляет запись времени
+func (s *timeEntryService) DeleteEntry(userID, entryID uint) error {
+	entry, err := s.getEntry(userID, entryID)
+	if err != nil {
+		return err
+	}
+	return s.timeEntryRepo.Delete(entry.ID)
+}
+
+// GetReport строит отчет по завершенным записям времени.
+// Дата to включается в период целиком.
+func (s *timeEntryService) GetReport(userID uint, params models.TimeReportParams) ([]models.TimeReportRow, error) {
+	groupBy := []string{"task"}
+	if params.GroupBy != "" {
+		groupBy = nil
+		seen := make(map[string]bool)
+		for _, dimension := range strings.Split(params.GroupBy, ",") {
+			dimension = strings.TrimSpace(dimension)
+			switch dimension {
+			case "task", "project", "user":
+			default:
+				return nil, errors.New("invalid group_by: " + dimension)
+			}
+			if !seen[dimension] {
+				seen[dimension] = true
+				groupBy = append(groupBy, dimension)
+			}
+		}
+	}
+
+	if params.From != nil {
+		from := params.From.UTC()
+		params.From = &from
+	}
+	if params.To != nil {
+		to := params.To.UTC().AddDate(0, 0, 1)
+		params.To = &to
+	}
+	if params.From != nil && params.To != nil && !params.To.After(*params.From) {
+		return nil, errors.New("invalid date range")
+	}
+
+	return s.timeEntryRepo.Report(userID, groupBy, params)
+}
+
+// checkTask проверяет, что задача существует и принадлежит пользователю
+func (s *timeEntryService) checkTask(userID, taskID uint) error {
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("task not found")
+		}
+		return err
+	}
+	if task.UserID != userID {
+		return errors.New("access denied")
+	}
+	return nil
+}
+
+// getEntry получает запись времени и проверяет, что она принадлежит пользователю
+func (s *timeEntryService) getEntry(userID, entryID uint) (*models.TimeEntry, error) {
+	entry, err := s.timeEntryRepo.GetByID(entryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("time entry not found")
+		}
+		return nil, err
+	}
+	if entry.UserID != userID {
+		return nil, errors.New("access denied")
+	}
+	return entry, nil
+}
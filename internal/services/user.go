@@ -0,0 +1,79 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// UserService интерфейс для сервиса профиля пользователя
+type UserService interface {
+	GetProfile(userID uint) (*models.UserProfileResponse, error)
+	UpdateProfile(userID uint, req models.UpdateProfileRequest) (*models.UserProfileResponse, error)
+}
+
+// userService реализация сервиса профиля
+type userService struct {
+	userRepo repository.UserRepository
+}
+
+// NewUserService создает новый сервис профиля
+func NewUserService(userRepo repository.UserRepository) UserService {
+	return &userService{
+		userRepo: userRepo,
+	}
+}
+
+// GetProfile получает профиль пользователя
+func (s *userService) GetProfile(userID uint) (*models.UserProfileResponse, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := user.ToProfileResponse()
+	return &profile, nil
+}
+
+// UpdateProfile изменяет профиль пользователя
+func (s *userService) UpdateProfile(userID uint, req models.UpdateProfileRequest) (*models.UserProfileResponse, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Timezone != nil {
+		// Пустое значение возвращает UTC; "Local" зависит от сервера и не принимается
+		if *req.Timezone == "Local" {
+			return nil, fmt.Errorf("invalid timezone: unknown time zone %s", *req.Timezone)
+		}
+		if _, err := time.LoadLocation(*req.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone: %v", err)
+		}
+		user.Timezone = *req.Timezone
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	profile := user.ToProfileResponse()
+	return &profile, nil
+}
+
+// getUser получает пользователя по ID
+func (s *userService) getUser(userID uint) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// WatcherService интерфейс для сервиса подписок на задачи
+type WatcherService interface {
+	Watch(userID, taskID uint) error
+	Unwatch(userID, taskID uint) error
+	GetWatchers(userID, taskID uint) ([]models.TaskWatcherResponse, error)
+}
+
+// watcherService реализация сервиса подписок
+type watcherService struct {
+	watcherRepo repository.TaskWatcherRepository
+	taskRepo    repository.TaskRepository
+}
+
+// NewWatcherService создает новый сервис подписок
+func NewWatcherService(watcherRepo repository.TaskWatcherRepository, taskRepo repository.TaskRepository) WatcherService {
+	return &watcherService{
+		watcherRepo: watcherRepo,
+		taskRepo:    taskRepo,
+	}
+}
+
+// Watch подписывает пользователя на изменения задачи
+func (s *watcherService) Watch(userID, taskID uint) error {
+	task, err := s.checkAccess(userID, taskID)
+	if err != nil {
+		return err
+	}
+
+	reason := models.WatchReasonManual
+	if task.UserID == userID {
+		reason = models.WatchReasonOwner
+	}
+	return s.watcherRepo.SetWatching(taskID, userID, true, reason)
+}
+
+// Unwatch отписывает пользователя от изменений задачи
+func (s *watcherService) Unwatch(userID, taskID uint) error {
+	if _, err := s.checkAccess(userID, taskID); err != nil {
+		return err
+	}
+	return s.watcherRepo.SetWatching(taskID, userID, false, models.WatchReasonManual)
+}
+
+// GetWatchers получает пользователей, подписанных на задачу
+func (s *watcherService) GetWatchers(userID, taskID uint) ([]models.TaskWatcherResponse, error) {
+	if _, err := s.checkAccess(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	watchers, err := s.watcherRepo.GetWatching(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	watcherResponses := make([]models.TaskWatcherResponse, len(watchers))
+	for i, watcher := range watchers {
+		watcherResponses[i] = watcher.ToResponse()
+	}
+	return watcherResponses, nil
+}
+
+// checkAccess проверяет, что пользователь может следить за задачей: он ее владелец
+// или был подписан на нее (как назначенный или упомянутый пользователь)
+func (s *watcherService) checkAccess(userID, taskID uint) (*models.Task, error) {
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("task not found")
+		}
+		return nil, err
+	}
+	if task.UserID == userID {
+		return task, nil
+	}
+
+	if _, err := s.watcherRepo.Get(taskID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("access denied")
+		}
+		return nil, err
+	}
+	return task, nil
+}
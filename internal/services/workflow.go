@@ -0,0 +1,318 @@
+package services
+
+import (
+	"errors"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+	"golang_server/pkg/validator"
+
+	"gorm.io/gorm"
+)
+
+// WorkflowService интерфейс для сервиса рабочих процессов
+type WorkflowService interface {
+	CreateWorkflow(userID uint, req models.CreateWorkflowRequest) (*models.Workflow, error)
+	GetWorkflows(userID uint) ([]models.Workflow, error)
+	GetWorkflowByID(userID, workflowID uint) (*models.Workflow, error)
+	GetDefaultWorkflow(userID uint) (*models.Workflow, error)
+	UpdateWorkflow(userID, workflowID uint, req models.UpdateWorkflowRequest) (*models.Workflow, error)
+	DeleteWorkflow(userID, workflowID uint) error
+	WithTx(tx *gorm.DB) WorkflowService
+}
+
+// workflowService реализация сервиса рабочих процессов
+type workflowService struct {
+	transactor   repository.Transactor
+	workflowRepo repository.WorkflowRepository
+}
+
+// NewWorkflowService создает новый сервис рабочих процессов
+func NewWorkflowService(transactor repository.Transactor, workflowRepo repository.WorkflowRepository) WorkflowService {
+	return &workflowService{
+		transactor:   transactor,
+		workflowRepo: workflowRepo,
+	}
+}
+
+// WithTx возвращает сервис, работающий в транзакции tx
+func (s *workflowService) WithTx(tx *gorm.DB) WorkflowService {
+	txService := *s
+	txService.transactor = s.transactor.WithTx(tx)
+	txService.workflowRepo = s.workflowRepo.WithTx(tx)
+	return &txService
+}
+
+// CreateWorkflow создает новый рабочий процесс
+func (s *workflowService) CreateWorkflow(userID uint, req models.CreateWorkflowRequest) (*models.Workflow, error) {
+	statuses, transitions, err := buildWorkflowStatuses(req.Statuses, req.Transitions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Гарантируем, что у пользователя уже есть процесс по умолчанию
+	if _, err := s.GetDefaultWorkflow(userID); err != nil {
+		return nil, err
+	}
+
+	workflow := &models.Workflow{
+		Name:             req.Name,
+		UserID:           userID,
+		RequireChecklist: req.RequireChecklist,
+		Statuses:         statuses,
+		Transitions:      transitions,
+	}
+
+	if err := s.workflowRepo.Create(workflow); err != nil {
+		return nil, err
+	}
+
+	if req.IsDefault {
+		if err := s.workflowRepo.SetDefault(userID, workflow.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.workflowRepo.GetByID(workflow.ID)
+}
+
+// GetWorkflows получает рабочие процессы пользователя
+func (s *workflowService) GetWorkflows(userID uint) ([]models.Workflow, error) {
+	if _, err := s.GetDefaultWorkflow(userID); err != nil {
+		return nil, err
+	}
+	return s.workflowRepo.GetByUserID(userID)
+}
+
+// GetWorkflowByID получает рабочий процесс по ID
+func (s *workflowService) GetWorkflowByID(userID, workflowID uint) (*models.Workflow, error) {
+	workflow, err := s.workflowRepo.GetByID(workflowID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("workflow not found")
+		}
+		return nil, err
+	}
+
+	// Проверяем, что процесс принадлежит пользователю
+	if workflow.UserID != userID {
+		return nil, errors.New("access denied")
+	}
+
+	return workflow, nil
+}
+
+// GetDefaultWorkflow получает процесс пользователя по умолчанию, создавая его при необходимости.
+// Второй процесс по умолчанию не дает создать уникальный индекс: если параллельный запрос
+// успел создать процесс первым, возвращается его процесс.
+func (s *workflowService) GetDefaultWorkflow(userID uint) (*models.Workflow, error) {
+	workflow, err := s.workflowRepo.GetDefault(userID)
+	if err == nil {
+		return workflow, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	workflow = models.DefaultWorkflow(userID)
+	if err := s.workflowRepo.Create(workflow); err != nil {
+		if isUniqueViolation(err) {
+			return s.workflowRepo.GetDefault(userID)
+		}
+		return nil, err
+	}
+	return workflow, nil
+}
+
+// UpdateWorkflow обновляет рабочий процесс. Проверка, что удаляемые статусы не заняты
+// задачами, и изменение процесса выполняются в одной транзакции.
+func (s *workflowService) UpdateWorkflow(userID, workflowID uint, req models.UpdateWorkflowRequest) (*models.Workflow, error) {
+	var workflow *models.Workflow
+	err := s.transactor.Transaction(func(tx *gorm.DB) error {
+		txService := &workflowService{
+			transactor:   s.transactor.WithTx(tx),
+			workflowRepo: s.workflowRepo.WithTx(tx),
+		}
+
+		var err error
+		workflow, err = txService.updateWorkflow(userID, workflowID, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return workflow, nil
+}
+
+// updateWorkflow обновляет рабочий процесс и возвращает его новое состояние
+func (s *workflowService) updateWorkflow(userID, workflowID uint, req models.UpdateWorkflowRequest) (*models.Workflow, error) {
+	workflow, err := s.GetWorkflowByID(userID, workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Процесс по умолчанию перестает быть таким, только когда по умолчанию назначают другой
+	if req.IsDefault != nil && !*req.IsDefault && workflow.IsDefault {
+		return nil, errors.New("default workflow cannot be unset, make another workflow default instead")
+	}
+
+	if req.Name != nil {
+		workflow.Name = *req.Name
+	}
+	if req.RequireChecklist != nil {
+		workflow.RequireChecklist = *req.RequireChecklist
+	}
+
+	replace := req.Statuses != nil || req.Transitions != nil
+	if replace {
+		statusRequests := req.Statuses
+		if statusRequests == nil {
+			for _, status := range workflow.Statuses {
+				statusRequests = append(statusRequests, models.WorkflowStatusRequest{
+					Key:      status.Key,
+					Name:     status.Name,
+					Category: status.Category,
+				})
+			}
+		}
+
+		// Без новых переходов сохраняем прежние, если их статусы остались в процессе
+		transitionRequests := req.Transitions
+		if transitionRequests == nil {
+			keys := make(map[models.TaskStatus]bool, len(statusRequests))
+			for _, status := range statusRequests {
+				keys[status.Key] = true
+			}
+			for _, transition := range workflow.Transitions {
+				if keys[transition.FromStatus] && keys[transition.ToStatus] {
+					transitionRequests = append(transitionRequests, models.WorkflowTransitionRequest{
+						From: transition.FromStatus,
+						To:   transition.ToStatus,
+					})
+				}
+			}
+			// Пустой список переходов разрешает любые переходы, поэтому процесс
+			// с ограничениями не должен терять их незаметно вместе со статусами
+			if len(workflow.Transitions) > 0 && len(transitionRequests) == 0 {
+				return nil, errors.New("invalid workflow: transitions must be specified when none of the current transitions remain")
+			}
+		}
+
+		statuses, transitions, err := buildWorkflowStatuses(statusRequests, transitionRequests)
+		if err != nil {
+			return nil, err
+		}
+
+		// Нельзя удалить статус, в котором еще находятся задачи
+		var removed []models.TaskStatus
+		for _, old := range workflow.Statuses {
+			kept := false
+			for _, status := range statuses {
+				if status.Key == old.Key {
+					kept = true
+					break
+				}
+			}
+			if !kept {
+				removed = append(removed, old.Key)
+			}
+		}
+		if len(removed) > 0 {
+			count, err := s.workflowRepo.CountTasksInStatuses(workflow.ID, removed)
+			if err != nil {
+				return nil, err
+			}
+			if count > 0 {
+				return nil, errors.New("status is in use by tasks")
+			}
+		}
+
+		workflow.Statuses = statuses
+		workflow.Transitions = transitions
+	}
+
+	if err := s.workflowRepo.Update(workflow, replace); err != nil {
+		return nil, err
+	}
+
+	if req.IsDefault != nil && *req.IsDefault && !workflow.IsDefault {
+		if err := s.workflowRepo.SetDefault(userID, workflow.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.workflowRepo.GetByID(workflow.ID)
+}
+
+// DeleteWorkflow удаляет рабочий процесс
+func (s *workflowService) DeleteWorkflow(userID, workflowID uint) error {
+	workflow, err := s.GetWorkflowByID(userID, workflowID)
+	if err != nil {
+		return err
+	}
+
+	if workflow.IsDefault {
+		return errors.New("workflow is in use")
+	}
+
+	projects, err := s.workflowRepo.CountProjects(workflow.ID)
+	if err != nil {
+		return err
+	}
+	tasks, err := s.workflowRepo.CountTasksInStatuses(workflow.ID, nil)
+	if err != nil {
+		return err
+	}
+	if projects > 0 || tasks > 0 {
+		return errors.New("workflow is in use")
+	}
+
+	return s.workflowRepo.Delete(workflow.ID)
+}
+
+// buildWorkflowStatuses проверяет статусы и переходы запроса и строит из них модели
+func buildWorkflowStatuses(statusRequests []models.WorkflowStatusRequest, transitionRequests []models.WorkflowTransitionRequest) ([]models.WorkflowStatus, []models.WorkflowTransition, error) {
+	if len(statusRequests) == 0 {
+		return nil, nil, errors.New("invalid workflow: at least one status is required")
+	}
+
+	keys := make(map[models.TaskStatus]bool, len(statusRequests))
+	statuses := make([]models.WorkflowStatus, 0, len(statusRequests))
+	for i, req := range statusRequests {
+		if !validator.IsValidStatusKey(string(req.Key)) {
+			return nil, nil, errors.New("invalid workflow: malformed status key " + string(req.Key))
+		}
+		if keys[req.Key] {
+			return nil, nil, errors.New("invalid workflow: duplicate status key " + string(req.Key))
+		}
+		if !req.Category.IsValid() {
+			return nil, nil, errors.New("invalid workflow: unknown category " + string(req.Category))
+		}
+		keys[req.Key] = true
+		statuses = append(statuses, models.WorkflowStatus{
+			Key:      req.Key,
+			Name:     req.Name,
+			Category: req.Category,
+			Position: i,
+		})
+	}
+
+	type pair struct{ from, to models.TaskStatus }
+	seen := make(map[pair]bool, len(transitionRequests))
+	transitions := make([]models.WorkflowTransition, 0, len(transitionRequests))
+	for _, req := range transitionRequests {
+		if !keys[req.From] || !keys[req.To] {
+			return nil, nil, errors.New("invalid workflow: transition references unknown status")
+		}
+		if req.From == req.To || seen[pair{req.From, req.To}] {
+			continue
+		}
+		seen[pair{req.From, req.To}] = true
+		transitions = append(transitions, models.WorkflowTransition{
+			FromStatus: req.From,
+			ToStatus:   req.To,
+		})
+	}
+
+	return statuses, transitions, nil
+}
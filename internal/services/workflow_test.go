@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+func TestUpdateWorkflowRejectsUnsetDefaultWithoutChanges(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+	workflows := NewWorkflowService(repository.NewTransactor(env.db), repository.NewWorkflowRepository(env.db))
+
+	workflow, err := workflows.GetDefaultWorkflow(userID)
+	if err != nil {
+		t.Fatalf("get default workflow: %v", err)
+	}
+
+	name := "Renamed"
+	unset := false
+	_, err = workflows.UpdateWorkflow(userID, workflow.ID, models.UpdateWorkflowRequest{Name: &name, IsDefault: &unset})
+	if err == nil {
+		t.Fatal("expected an error when unsetting the default workflow")
+	}
+
+	stored, err := workflows.GetWorkflowByID(userID, workflow.ID)
+	if err != nil {
+		t.Fatalf("get workflow: %v", err)
+	}
+	if stored.Name != workflow.Name || !stored.IsDefault {
+		t.Errorf("workflow = %q (default %v), want it unchanged", stored.Name, stored.IsDefault)
+	}
+}
+
+func TestUpdateWorkflowKeepsStatusInUse(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+	workflows := NewWorkflowService(repository.NewTransactor(env.db), repository.NewWorkflowRepository(env.db))
+
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	task := env.createTask(t, userID, "Pending task", day, day)
+	workflow, err := workflows.GetDefaultWorkflow(userID)
+	if err != nil {
+		t.Fatalf("get default workflow: %v", err)
+	}
+
+	name := "Renamed"
+	_, err = workflows.UpdateWorkflow(userID, workflow.ID, models.UpdateWorkflowRequest{
+		Name: &name,
+		Statuses: []models.WorkflowStatusRequest{
+			{Key: models.TaskStatusInProgress, Name: "In progress", Category: models.StatusCategoryDoing},
+			{Key: models.TaskStatusCompleted, Name: "Completed", Category: models.StatusCategoryDone},
+		},
+	})
+	if err == nil || err.Error() != "status is in use by tasks" {
+		t.Fatalf("error = %v, want the status of task %d to be in use", err, task.ID)
+	}
+
+	stored, err := workflows.GetWorkflowByID(userID, workflow.ID)
+	if err != nil {
+		t.Fatalf("get workflow: %v", err)
+	}
+	if stored.Name != workflow.Name || len(stored.Statuses) != len(workflow.Statuses) {
+		t.Errorf("workflow = %q with %d statuses, want it unchanged", stored.Name, len(stored.Statuses))
+	}
+}
+
+func TestTaskServiceTxUsesWorkflowServiceInTransaction(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	rollback := errors.New("rollback")
+	err := env.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := env.taskService.withTx(tx).workflowService.GetDefaultWorkflow(userID); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("transaction error = %v, want rollback", err)
+	}
+
+	// Процесс по умолчанию создавался в транзакции и откатился вместе с ней
+	var count int64
+	if err := env.db.Model(&models.Workflow{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		t.Fatalf("count workflows: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("workflows after rollback = %d, want 0", count)
+	}
+}
+
+func TestDefaultWorkflowIsUniquePerUser(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+	workflowRepo := repository.NewWorkflowRepository(env.db)
+	workflows := NewWorkflowService(repository.NewTransactor(env.db), workflowRepo)
+
+	first, err := workflows.GetDefaultWorkflow(userID)
+	if err != nil {
+		t.Fatalf("get default workflow: %v", err)
+	}
+
+	// Так выглядит проигравший параллельный запрос: процесс по умолчанию уже создан
+	if err := workflowRepo.Create(models.DefaultWorkflow(userID)); err == nil || !isUniqueViolation(err) {
+		t.Fatalf("second default workflow: error = %v, want a unique violation", err)
+	}
+	got, err := workflows.GetDefaultWorkflow(userID)
+	if err != nil || got.ID != first.ID {
+		t.Fatalf("default workflow = %+v, %v, want %d", got, err, first.ID)
+	}
+}
+
+func TestUpdateWorkflowKeepsTransitionsExplicit(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+	workflows := NewWorkflowService(repository.NewTransactor(env.db), repository.NewWorkflowRepository(env.db))
+
+	workflow, err := workflows.CreateWorkflow(userID, models.CreateWorkflowRequest{
+		Name: "Review",
+		Statuses: []models.WorkflowStatusRequest{
+			{Key: "todo", Name: "To do", Category: models.StatusCategoryTodo},
+			{Key: "review", Name: "Review", Category: models.StatusCategoryDoing},
+			{Key: "done", Name: "Done", Category: models.StatusCategoryDone},
+		},
+		Transitions: []models.WorkflowTransitionRequest{{From: "todo", To: "review"}, {From: "review", To: "done"}},
+	})
+	if err != nil {
+		t.Fatalf("create workflow: %v", err)
+	}
+	if workflow.CanTransition("todo", "done") {
+		t.Fatal("todo → done should not be allowed")
+	}
+
+	// Удаление статуса review убрало бы все переходы и молча разрешило любые
+	_, err = workflows.UpdateWorkflow(userID, workflow.ID, models.UpdateWorkflowRequest{
+		Statuses: []models.WorkflowStatusRequest{
+			{Key: "todo", Name: "To do", Category: models.StatusCategoryTodo},
+			{Key: "done", Name: "Done", Category: models.StatusCategoryDone},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error when the remaining statuses keep no transitions")
+	}
+
+	// Пустой список переходов снимает ограничения явно
+	updated, err := workflows.UpdateWorkflow(userID, workflow.ID, models.UpdateWorkflowRequest{
+		Transitions: []models.WorkflowTransitionRequest{},
+	})
+	if err != nil {
+		t.Fatalf("clear transitions: %v", err)
+	}
+	if len(updated.Transitions) != 0 || !updated.CanTransition("todo", "done") {
+		t.Errorf("transitions = %+v, want any transition allowed", updated.Transitions)
+	}
+}
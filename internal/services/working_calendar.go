@@ -0,0 +1,434 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+	"golang_server/pkg/ical"
+	"golang_server/pkg/workdays"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// holidayDateFormat формат дат нерабочих дней
+	holidayDateFormat = "2006-01-02"
+	// holidayMaxNameLength максимальная длина названия нерабочего дня в символах
+	holidayMaxNameLength = 255
+	// holidayImportMaxDays наибольшее число нерабочих дней в одном файле
+	holidayImportMaxDays = 5000
+	// holidayMaxEventDays наибольшая длина одного события в днях
+	holidayMaxEventDays = 366
+	// holidayRecurrenceYears на сколько лет назад и вперед от текущего года загружаются нерабочие дни
+	holidayRecurrenceYears = 10
+)
+
+// errTooManyHolidays возвращается, если события файла занимают больше holidayImportMaxDays дней
+var errTooManyHolidays = fmt.Errorf("invalid calendar data: more than %d holidays", holidayImportMaxDays)
+
+// defaultWeekdays рабочая неделя по умолчанию: с понедельника по пятницу (ISO 8601)
+var defaultWeekdays = []int{1, 2, 3, 4, 5}
+
+// WorkingCalendarService интерфейс для сервиса календарей рабочих дней
+type WorkingCalendarService interface {
+	GetCalendar(userID uint, params models.WorkingCalendarParams) (*models.WorkingCalendarResponse, error)
+	UpdateCalendar(userID uint, req models.UpdateWorkingCalendarRequest) (*models.WorkingCalendarResponse, error)
+	AddHoliday(userID uint, req models.CreateHolidayRequest) (*models.Holiday, error)
+	DeleteHoliday(userID uint, date string) error
+	ImportHolidays(userID uint, params models.HolidayImportParams, r io.Reader) (*models.HolidayImportResult, error)
+	Calendar(userID uint) (*workdays.Calendar, error)
+	WithTx(tx *gorm.DB) WorkingCalendarService
+}
+
+// workingCalendarService реализация сервиса календарей рабочих дней
+type workingCalendarService struct {
+	transactor          repository.Transactor
+	workingCalendarRepo repository.WorkingCalendarRepository
+	userRepo            repository.UserRepository
+}
+
+// NewWorkingCalendarService создает новый сервис календарей рабочих дней
+func NewWorkingCalendarService(transactor repository.Transactor, workingCalendarRepo repository.WorkingCalendarRepository, userRepo repository.UserRepository) WorkingCalendarService {
+	return &workingCalendarService{
+		transactor:          transactor,
+		workingCalendarRepo: workingCalendarRepo,
+		userRepo:            userRepo,
+	}
+}
+
+// WithTx возвращает сервис, работающий в транзакции tx
+func (s *workingCalendarService) WithTx(tx *gorm.DB) WorkingCalendarService {
+	txService := *s
+	txService.transactor = s.transactor.WithTx(tx)
+	txService.workingCalendarRepo = s.workingCalendarRepo.WithTx(tx)
+	txService.userRepo = s.userRepo.WithTx(tx)
+	return &txService
+}
+
+// GetCalendar получает рабочую неделю и нерабочие дни пользователя
+func (s *workingCalendarService) GetCalendar(userID uint, params models.WorkingCalendarParams) (*models.WorkingCalendarResponse, error) {
+	weekdays, err := s.weekdays(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var from, to string
+	if params.Year != 0 {
+		from = fmt.Sprintf("%04d-01-01", params.Year)
+		to = fmt.Sprintf("%04d-12-31", params.Year)
+	}
+	holidays, err := s.workingCalendarRepo.GetHolidays(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WorkingCalendarResponse{Weekdays: weekdays, Holidays: holidays}, nil
+}
+
+// UpdateCalendar задает рабочие дни недели пользователя
+func (s *workingCalendarService) UpdateCalendar(userID uint, req models.UpdateWorkingCalendarRequest) (*models.WorkingCalendarResponse, error) {
+	var week [8]bool
+	for _, day := range req.Weekdays {
+		if day < 1 || day > 7 {
+			return nil, errors.New("invalid weekdays: days must be from 1 (Monday) to 7 (Sunday)")
+		}
+		week[day] = true
+	}
+	weekdays := make([]int, 0, len(req.Weekdays))
+	for day := 1; day <= 7; day++ {
+		if week[day] {
+			weekdays = append(weekdays, day)
+		}
+	}
+	if len(weekdays) == 0 {
+		return nil, errors.New("invalid weekdays: at least one working day is required")
+	}
+
+	calendar := &models.WorkingCalendar{UserID: userID, Weekdays: weekdays}
+	if err := s.workingCalendarRepo.Save(calendar); err != nil {
+		return nil, err
+	}
+	return s.GetCalendar(userID, models.WorkingCalendarParams{})
+}
+
+// AddHoliday добавляет нерабочий день
+func (s *workingCalendarService) AddHoliday(userID uint, req models.CreateHolidayRequest) (*models.Holiday, error) {
+	date, err := time.Parse(holidayDateFormat, req.Date)
+	if err != nil {
+		return nil, errors.New("invalid date: expected YYYY-MM-DD")
+	}
+
+	holiday := models.Holiday{
+		UserID: userID,
+		Date:   date.Format(holidayDateFormat),
+		Name:   strings.TrimSpace(req.Name),
+	}
+	added, err := s.workingCalendarRepo.AddHolidays([]models.Holiday{holiday})
+	if err != nil {
+		return nil, err
+	}
+	if added == 0 {
+		return nil, errors.New("holiday already exists")
+	}
+
+	holidays, err := s.workingCalendarRepo.GetHolidays(userID, holiday.Date, holiday.Date)
+	if err != nil {
+		return nil, err
+	}
+	if len(holidays) == 0 {
+		return nil, errors.New("holiday not found")
+	}
+	return &holidays[0], nil
+}
+
+// DeleteHoliday удаляет нерабочий день
+func (s *workingCalendarService) DeleteHoliday(userID uint, date string) error {
+	deleted, err := s.workingCalendarRepo.DeleteHoliday(userID, date)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("holiday not found")
+	}
+	return nil
+}
+
+// ImportHolidays загружает нерабочие дни из событий VEVENT файла iCalendar. Событие
+// занимает дни с DTSTART до DTEND, не включая его; ежегодные события (RRULE:FREQ=YEARLY)
+// разворачиваются. Загружаются только дни за 10 лет назад и вперед от текущего года.
+// Даты событий со временем берутся в часовом поясе пользователя.
+func (s *workingCalendarService) ImportHolidays(userID uint, params models.HolidayImportParams, r io.Reader) (*models.HolidayImportResult, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar, err := ical.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar data: %w", err)
+	}
+	if !strings.EqualFold(calendar.Name, "VCALENDAR") {
+		return nil, errors.New("invalid calendar data: expected VCALENDAR")
+	}
+
+	year := time.Now().Year()
+	earliest := time.Date(year-holidayRecurrenceYears, time.January, 1, 0, 0, 0, 0, time.UTC)
+	horizon := time.Date(year+holidayRecurrenceYears, time.December, 31, 0, 0, 0, 0, time.UTC)
+	result := &models.HolidayImportResult{}
+	seen := make(map[string]bool)
+	var holidays []models.Holiday
+	for i := range calendar.Components {
+		event := &calendar.Components[i]
+		if !strings.EqualFold(event.Name, "VEVENT") {
+			continue
+		}
+
+		dates, err := holidayDates(event, user.Location(), earliest, horizon, holidayImportMaxDays-len(holidays))
+		if err != nil {
+			if errors.Is(err, errTooManyHolidays) {
+				return nil, err
+			}
+			result.Skipped++
+			continue
+		}
+
+		var name string
+		if summary := event.Get("SUMMARY"); summary != nil {
+			name = truncateRunes(strings.TrimSpace(ical.UnescapeText(summary.Value)), holidayMaxNameLength)
+		}
+		for _, date := range dates {
+			key := date.Format(holidayDateFormat)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			holidays = append(holidays, models.Holiday{UserID: userID, Date: key, Name: name, Imported: true})
+		}
+	}
+
+	err = s.transactor.Transaction(func(tx *gorm.DB) error {
+		workingCalendarRepo := s.workingCalendarRepo.WithTx(tx)
+		if params.Replace {
+			if err := workingCalendarRepo.DeleteImportedHolidays(userID); err != nil {
+				return err
+			}
+		}
+		added, err := workingCalendarRepo.AddHolidays(holidays)
+		if err != nil {
+			return err
+		}
+		result.Imported = int(added)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Calendar возвращает календарь рабочих дней пользователя для расчетов.
+// Нерабочие дни читаются по годам, когда расчет до них доходит.
+func (s *workingCalendarService) Calendar(userID uint) (*workdays.Calendar, error) {
+	weekdays, err := s.weekdays(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &workdays.Calendar{
+		LoadHolidays: func(year int) ([]string, error) {
+			holidays, err := s.workingCalendarRepo.GetHolidays(userID, fmt.Sprintf("%04d-01-01", year), fmt.Sprintf("%04d-12-31", year))
+			if err != nil {
+				return nil, err
+			}
+			dates := make([]string, len(holidays))
+			for i, holiday := range holidays {
+				dates[i] = holiday.Date
+			}
+			return dates, nil
+		},
+	}
+	for _, day := range weekdays {
+		// ISO 8601 нумерует дни с понедельника, time.Weekday — с воскресенья
+		calendar.Weekdays[time.Weekday(day%7)] = true
+	}
+	return calendar, nil
+}
+
+// weekdays возвращает рабочие дни недели пользователя
+func (s *workingCalendarService) weekdays(userID uint) ([]int, error) {
+	calendar, err := s.workingCalendarRepo.GetByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return defaultWeekdays, nil
+		}
+		return nil, err
+	}
+	return calendar.Weekdays, nil
+}
+
+// holidayDates возвращает дни с earliest по horizon, которые занимают событие и его повторения.
+// Если дней больше limit, возвращается errTooManyHolidays.
+func holidayDates(event *ical.Component, location *time.Location, earliest, horizon time.Time, limit int) ([]time.Time, error) {
+	dtstart := event.Get("DTSTART")
+	if dtstart == nil {
+		return nil, errors.New("event has no DTSTART")
+	}
+	start, allDay, err := ical.ParseTime(dtstart, location)
+	if err != nil {
+		return nil, err
+	}
+	first := eventDay(start, allDay, location)
+
+	// DTEND не входит в событие, поэтому событие до полуночи заканчивается накануне
+	days := 1
+	if dtend := event.Get("DTEND"); dtend != nil {
+		end, endAllDay, err := ical.ParseTime(dtend, location)
+		if err != nil {
+			return nil, err
+		}
+		last := eventDay(end, endAllDay, location)
+		if endAllDay || end.In(location).Equal(time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, location)) {
+			last = last.AddDate(0, 0, -1)
+		}
+		if span := int(last.Sub(first).Hours()/24) + 1; span > days {
+			days = span
+		}
+	}
+	if days > holidayMaxEventDays {
+		return nil, errors.New("event is too long")
+	}
+
+	occurrences := []time.Time{first}
+	if rrule := event.Get("RRULE"); rrule != nil {
+		// Повторение, начавшееся до earliest, может заканчиваться уже после него
+		occurrences, err = yearlyOccurrences(rrule.Value, first, earliest.AddDate(0, 0, -(days-1)), horizon)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	excluded := make(map[string]bool)
+	for _, exdate := range event.GetAll("EXDATE") {
+		for _, value := range strings.Split(exdate.Value, ",") {
+			property := ical.Property{Name: exdate.Name, Params: exdate.Params, Value: value}
+			t, exAllDay, err := ical.ParseTime(&property, location)
+			if err != nil {
+				return nil, err
+			}
+			excluded[eventDay(t, exAllDay, location).Format(holidayDateFormat)] = true
+		}
+	}
+
+	var dates []time.Time
+	for _, occurrence := range occurrences {
+		if excluded[occurrence.Format(holidayDateFormat)] {
+			continue
+		}
+		for i := 0; i < days; i++ {
+			date := occurrence.AddDate(0, 0, i)
+			if date.Before(earliest) || date.After(horizon) {
+				continue
+			}
+			if len(dates) == limit {
+				return nil, errTooManyHolidays
+			}
+			dates = append(dates, date)
+		}
+	}
+	return dates, nil
+}
+
+// eventDay возвращает день события как полночь UTC: дата без времени берется как есть,
+// время — по дате в часовом поясе location
+func eventDay(t time.Time, allDay bool, location *time.Location) time.Time {
+	if !allDay {
+		t = t.In(location)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// yearlyOccurrences разворачивает правило повторения RRULE события, начинающегося в first,
+// и возвращает повторения с from по until. Поддерживается только ежегодное повторение
+// в тот же день (FREQ=YEARLY с INTERVAL, COUNT и UNTIL); повторения 29 февраля
+// в невисокосные годы пропускаются.
+func yearlyOccurrences(rule string, first, from, until time.Time) ([]time.Time, error) {
+	interval, count := 1, 0
+	var yearly bool
+	for _, part := range strings.Split(rule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			yearly = strings.EqualFold(value, "YEARLY")
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, errors.New("invalid recurrence interval")
+			}
+			interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, errors.New("invalid recurrence count")
+			}
+			count = n
+		case "UNTIL":
+			if len(value) < len("20060102") {
+				return nil, errors.New("invalid recurrence end")
+			}
+			t, err := time.Parse("20060102", value[:len("20060102")])
+			if err != nil {
+				return nil, errors.New("invalid recurrence end")
+			}
+			if t.Before(until) {
+				until = t
+			}
+		case "BYMONTH":
+			if value != strconv.Itoa(int(first.Month())) {
+				return nil, errors.New("unsupported recurrence rule")
+			}
+		case "BYMONTHDAY":
+			if value != strconv.Itoa(first.Day()) {
+				return nil, errors.New("unsupported recurrence rule")
+			}
+		case "WKST":
+		default:
+			return nil, errors.New("unsupported recurrence rule")
+		}
+	}
+	if !yearly {
+		return nil, errors.New("unsupported recurrence rule")
+	}
+
+	// Повторения до from пропускаются сразу, не перебирая годы от DTSTART;
+	// COUNT при этом считается от DTSTART
+	n := 0
+	if first.Before(from) {
+		n = (from.Year() - first.Year()) / interval
+	}
+	var occurrences []time.Time
+	for ; count == 0 || n < count; n++ {
+		occurrence := time.Date(first.Year()+n*interval, first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+		if occurrence.After(until) {
+			break
+		}
+		if occurrence.Month() == first.Month() && !occurrence.Before(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+	return occurrences, nil
+}
+
+// truncateRunes обрезает строку до limit символов
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"golang_server/internal/models"
+	"golang_server/internal/repository"
+	"golang_server/pkg/ical"
+)
+
+// holidayEvent разбирает событие VEVENT из строк содержимого
+func holidayEvent(t *testing.T, lines ...string) *ical.Component {
+	t.Helper()
+
+	data := "BEGIN:VEVENT\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VEVENT\r\n"
+	event, err := ical.Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("parse event: %v", err)
+	}
+	return event
+}
+
+func TestHolidayDatesClampsAncientYearlyEvent(t *testing.T) {
+	earliest := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	horizon := time.Date(2036, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	event := holidayEvent(t,
+		"DTSTART;VALUE=DATE:00010101",
+		"DTEND;VALUE=DATE:00010102",
+		"RRULE:FREQ=YEARLY",
+	)
+	dates, err := holidayDates(event, time.UTC, earliest, horizon, holidayImportMaxDays)
+	if err != nil {
+		t.Fatalf("holiday dates: %v", err)
+	}
+	if len(dates) != 21 || !dates[0].Equal(earliest) || dates[len(dates)-1].Year() != 2036 {
+		t.Fatalf("dates = %d from %v, want 21 New Year days from 2016 to 2036", len(dates), dates)
+	}
+
+	// Длинное ежегодное событие с древним началом превышает ограничение, не разворачивая все годы
+	event = holidayEvent(t,
+		"DTSTART;VALUE=DATE:00010101",
+		"DTEND;VALUE=DATE:00020102",
+		"RRULE:FREQ=YEARLY",
+	)
+	if _, err := holidayDates(event, time.UTC, earliest, horizon, holidayImportMaxDays); !errors.Is(err, errTooManyHolidays) {
+		t.Fatalf("error = %v, want errTooManyHolidays", err)
+	}
+}
+
+func TestHolidayDatesKeepsCountFromStart(t *testing.T) {
+	earliest := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	horizon := time.Date(2036, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	// Пять повторений с 2014 года: 2014 и 2015 раньше периода, остаются 2016–2018
+	event := holidayEvent(t,
+		"DTSTART;VALUE=DATE:20140501",
+		"RRULE:FREQ=YEARLY;COUNT=5",
+		"EXDATE;VALUE=DATE:20170501",
+	)
+	dates, err := holidayDates(event, time.UTC, earliest, horizon, holidayImportMaxDays)
+	if err != nil {
+		t.Fatalf("holiday dates: %v", err)
+	}
+	var got []string
+	for _, date := range dates {
+		got = append(got, date.Format(holidayDateFormat))
+	}
+	if strings.Join(got, ",") != "2016-05-01,2018-05-01" {
+		t.Fatalf("dates = %v, want 2016-05-01 and 2018-05-01", got)
+	}
+}
+
+// countingWorkingCalendarRepo считает запросы нерабочих дней
+type countingWorkingCalendarRepo struct {
+	repository.WorkingCalendarRepository
+	ranges []string
+}
+
+func (r *countingWorkingCalendarRepo) GetHolidays(userID uint, from, to string) ([]models.Holiday, error) {
+	r.ranges = append(r.ranges, from+".."+to)
+	return r.WorkingCalendarRepository.GetHolidays(userID, from, to)
+}
+
+func TestCalendarLoadsHolidaysOfUsedYears(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "alice")
+
+	repo := &countingWorkingCalendarRepo{WorkingCalendarRepository: repository.NewWorkingCalendarRepository(env.db)}
+	service := NewWorkingCalendarService(repository.NewTransactor(env.db), repo, env.userRepo)
+	for _, date := range []string{"2001-01-01", "2026-10-20"} {
+		if _, err := service.AddHoliday(userID, models.CreateHolidayRequest{Date: date}); err != nil {
+			t.Fatalf("add holiday: %v", err)
+		}
+	}
+	repo.ranges = nil
+
+	calendar, err := service.Calendar(userID)
+	if err != nil {
+		t.Fatalf("calendar: %v", err)
+	}
+	if len(repo.ranges) != 0 {
+		t.Fatalf("holidays loaded before use: %v", repo.ranges)
+	}
+
+	// Вторник 20 октября — праздник, поэтому следующий рабочий день после понедельника — среда
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	if next := calendar.AddWorkingDays(monday, 1); next.Day() != 21 {
+		t.Errorf("next working day = %v, want 2026-10-21", next)
+	}
+	if err := calendar.Err(); err != nil {
+		t.Fatalf("calendar error: %v", err)
+	}
+	if len(repo.ranges) != 1 || repo.ranges[0] != "2026-01-01..2026-12-31" {
+		t.Errorf("loaded ranges = %v, want only 2026", repo.ranges)
+	}
+}
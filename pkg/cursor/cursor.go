@@ -0,0 +1,49 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalid возвращается для поврежденного или подделанного курсора
+var ErrInvalid = errors.New("cursor: invalid token")
+
+// Encode сериализует payload в непрозрачный токен, подписанный HMAC-SHA256.
+// Токен не шифруется: клиент может прочитать содержимое, но не изменить его.
+func Encode(secret []byte, payload interface{}) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	body := base64.RawURLEncoding.EncodeToString(data)
+	return body + "." + sign(secret, body), nil
+}
+
+// Decode проверяет подпись токена и восстанавливает payload
+func Decode(secret []byte, token string, payload interface{}) error {
+	body, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(sign(secret, body))) {
+		return ErrInvalid
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return ErrInvalid
+	}
+	if err := json.Unmarshal(data, payload); err != nil {
+		return ErrInvalid
+	}
+	return nil
+}
+
+// sign возвращает подпись тела токена
+func sign(secret []byte, body string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(body))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
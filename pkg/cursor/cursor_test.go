@@ -0,0 +1,72 @@
+package cursor
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type payload struct {
+	Values   []interface{} `json:"v"`
+	Backward bool          `json:"b"`
+}
+
+func TestEncodeDecode(t *testing.T) {
+	secret := []byte("secret")
+	want := payload{Values: []interface{}{"2026-10-19", float64(42)}, Backward: true}
+
+	token, err := Encode(secret, want)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if strings.ContainsAny(token, "+/=") {
+		t.Errorf("token %q is not URL-safe", token)
+	}
+
+	var got payload
+	if err := Decode(secret, token, &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("payload = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeRejectsInvalidTokens(t *testing.T) {
+	secret := []byte("secret")
+	token, err := Encode(secret, payload{Values: []interface{}{float64(1)}})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	body, signature, _ := strings.Cut(token, ".")
+
+	forged, err := Encode([]byte("other"), payload{Values: []interface{}{float64(2)}})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	forgedBody, _, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name   string
+		token  string
+		secret []byte
+	}{
+		{"empty", "", secret},
+		{"no signature", body, secret},
+		{"other secret", token, []byte("other")},
+		{"changed body", forgedBody + "." + signature, secret},
+		{"changed signature", body + "." + strings.Repeat("A", len(signature)), secret},
+		{"signed garbage", "!!!." + sign(secret, "!!!"), secret},
+		{"signed non-json", "bm90IGpzb24." + sign(secret, "bm90IGpzb24"), secret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got payload
+			if err := Decode(tt.secret, tt.token, &got); !errors.Is(err, ErrInvalid) {
+				t.Fatalf("error = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
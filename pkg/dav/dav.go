@@ -0,0 +1,292 @@
+// Package dav разбирает запросы и формирует ответы WebDAV (RFC 4918)
+// и CalDAV (RFC 4791) в формате XML
+package dav
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Пространства имен XML
+const (
+	NamespaceDAV            = "DAV:"
+	NamespaceCalDAV         = "urn:ietf:params:xml:ns:caldav"
+	NamespaceCalendarServer = "http://calendarserver.org/ns/"
+)
+
+// prefixes префиксы пространств имен, объявленные в корне ответа
+var prefixes = map[string]string{
+	NamespaceDAV:            "d",
+	NamespaceCalDAV:         "c",
+	NamespaceCalendarServer: "cs",
+}
+
+// Name возвращает имя элемента в пространстве DAV:
+func Name(local string) xml.Name {
+	return xml.Name{Space: NamespaceDAV, Local: local}
+}
+
+// CalDAVName возвращает имя элемента в пространстве CalDAV
+func CalDAVName(local string) xml.Name {
+	return xml.Name{Space: NamespaceCalDAV, Local: local}
+}
+
+// Propfind представляет тело запроса PROPFIND. Пустое тело равнозначно allprop.
+type Propfind struct {
+	AllProp  bool
+	PropName bool
+	Props    []xml.Name
+}
+
+// propfindBody XML-представление запроса PROPFIND
+type propfindBody struct {
+	XMLName  xml.Name  `xml:"DAV: propfind"`
+	AllProp  *struct{} `xml:"DAV: allprop"`
+	PropName *struct{} `xml:"DAV: propname"`
+	Prop     propNames `xml:"DAV: prop"`
+}
+
+// propNames собирает имена дочерних элементов <prop>
+type propNames []xml.Name
+
+// UnmarshalXML читает имена запрошенных свойств, пропуская их содержимое
+func (p *propNames) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			*p = append(*p, t.Name)
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// ParsePropfind разбирает тело запроса PROPFIND
+func ParsePropfind(r io.Reader) (*Propfind, error) {
+	var body propfindBody
+	if err := xml.NewDecoder(r).Decode(&body); err != nil {
+		if errors.Is(err, io.EOF) {
+			return &Propfind{AllProp: true}, nil
+		}
+		return nil, fmt.Errorf("invalid propfind body: %w", err)
+	}
+
+	propfind := &Propfind{
+		AllProp:  body.AllProp != nil,
+		PropName: body.PropName != nil,
+		Props:    body.Prop,
+	}
+	if !propfind.PropName && len(propfind.Props) == 0 {
+		propfind.AllProp = true
+	}
+	return propfind, nil
+}
+
+// Report представляет тело запроса REPORT: calendar-query или calendar-multiget
+type Report struct {
+	Name    xml.Name
+	AllProp bool
+	Props   []xml.Name
+	// Hrefs адреса ресурсов для calendar-multiget
+	Hrefs []string
+	// Component компонент из фильтра calendar-query (например, VTODO);
+	// Start и End задают необязательный интервал time-range
+	Component string
+	Start     *time.Time
+	End       *time.Time
+}
+
+// reportBody XML-представление запроса REPORT
+type reportBody struct {
+	XMLName xml.Name
+	AllProp *struct{} `xml:"DAV: allprop"`
+	Prop    propNames `xml:"DAV: prop"`
+	Hrefs   []string  `xml:"DAV: href"`
+	Filter  *struct {
+		Comp *compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+// compFilter фильтр компонентов calendar-query
+type compFilter struct {
+	Name      string        `xml:"name,attr"`
+	TimeRange *timeRange    `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	Filters   []*compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// timeRange интервал времени фильтра в UTC
+type timeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+// ParseReport разбирает тело запроса REPORT
+func ParseReport(r io.Reader) (*Report, error) {
+	var body reportBody
+	if err := xml.NewDecoder(r).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid report body: %w", err)
+	}
+
+	report := &Report{
+		Name:    body.XMLName,
+		AllProp: body.AllProp != nil || len(body.Prop) == 0,
+		Props:   body.Prop,
+		Hrefs:   body.Hrefs,
+	}
+
+	// Фильтр calendar-query вкладывает компонент в VCALENDAR
+	if body.Filter == nil {
+		return report, nil
+	}
+	if filter := body.Filter.Comp; filter != nil && strings.EqualFold(filter.Name, "VCALENDAR") && len(filter.Filters) > 0 {
+		inner := filter.Filters[0]
+		report.Component = strings.ToUpper(inner.Name)
+		if inner.TimeRange != nil {
+			var err error
+			if report.Start, err = parseUTC(inner.TimeRange.Start); err != nil {
+				return nil, err
+			}
+			if report.End, err = parseUTC(inner.TimeRange.End); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return report, nil
+}
+
+// parseUTC разбирает границу интервала time-range; пустая граница означает отсутствие ограничения
+func parseUTC(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("20060102T150405Z", value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time-range: %q", value)
+	}
+	return &t, nil
+}
+
+// Property представляет свойство ресурса с готовым XML-содержимым
+type Property struct {
+	Name  xml.Name
+	Inner string
+}
+
+// TextProperty создает свойство с текстовым значением
+func TextProperty(name xml.Name, text string) Property {
+	return Property{Name: name, Inner: escape(text)}
+}
+
+// HrefProperty создает свойство со ссылками <href>
+func HrefProperty(name xml.Name, hrefs ...string) Property {
+	var inner strings.Builder
+	for _, href := range hrefs {
+		inner.WriteString(Element(Name("href"), escape(href)))
+	}
+	return Property{Name: name, Inner: inner.String()}
+}
+
+// Element формирует элемент с пространством имен из объявленных в корне ответа
+func Element(name xml.Name, inner string) string {
+	prefix, ok := prefixes[name.Space]
+	if name.Space == "" {
+		if inner == "" {
+			return fmt.Sprintf("<%s/>", name.Local)
+		}
+		return fmt.Sprintf("<%s>%s</%s>", name.Local, inner, name.Local)
+	}
+	if !ok {
+		// Свойства из других пространств имен приходят только в списке ненайденных,
+		// поэтому их пространство объявляется на самом элементе
+		if inner == "" {
+			return fmt.Sprintf(`<x:%s xmlns:x="%s"/>`, name.Local, escape(name.Space))
+		}
+		return fmt.Sprintf(`<x:%s xmlns:x="%s">%s</x:%s>`, name.Local, escape(name.Space), inner, name.Local)
+	}
+	if inner == "" {
+		return fmt.Sprintf("<%s:%s/>", prefix, name.Local)
+	}
+	return fmt.Sprintf("<%s:%s>%s</%s:%s>", prefix, name.Local, inner, prefix, name.Local)
+}
+
+// Response представляет ответ по одному ресурсу в multistatus. Status, если задан,
+// относится ко всему ресурсу (например, 404 для calendar-multiget), и свойства не выводятся.
+type Response struct {
+	Href     string
+	Status   int
+	Props    []Property
+	NotFound []xml.Name
+}
+
+// WriteMultistatus записывает ответ 207 Multi-Status
+func WriteMultistatus(w io.Writer, responses []Response) error {
+	writer := bufio.NewWriter(w)
+	writer.WriteString(xml.Header)
+	writer.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`)
+	for _, response := range responses {
+		writer.WriteString("<d:response>")
+		writer.WriteString(Element(Name("href"), escape(response.Href)))
+		if response.Status != 0 {
+			writer.WriteString(Element(Name("status"), statusLine(response.Status)))
+		} else {
+			if len(response.Props) > 0 {
+				var props strings.Builder
+				for _, property := range response.Props {
+					props.WriteString(Element(property.Name, property.Inner))
+				}
+				writePropstat(writer, props.String(), http.StatusOK)
+			}
+			if len(response.NotFound) > 0 {
+				var props strings.Builder
+				for _, name := range response.NotFound {
+					props.WriteString(Element(name, ""))
+				}
+				writePropstat(writer, props.String(), http.StatusNotFound)
+			}
+		}
+		writer.WriteString("</d:response>")
+	}
+	writer.WriteString("</d:multistatus>")
+	return writer.Flush()
+}
+
+// writePropstat записывает группу свойств с общим статусом
+func writePropstat(w *bufio.Writer, props string, status int) {
+	w.WriteString("<d:propstat>")
+	w.WriteString(Element(Name("prop"), props))
+	w.WriteString(Element(Name("status"), statusLine(status)))
+	w.WriteString("</d:propstat>")
+}
+
+// WriteError записывает тело ошибки <error> с нарушенным предусловием
+func WriteError(w io.Writer, precondition xml.Name) error {
+	_, err := io.WriteString(w, xml.Header+
+		`<d:error xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`+
+		Element(precondition, "")+"</d:error>")
+	return err
+}
+
+// statusLine формирует строку статуса HTTP для multistatus
+func statusLine(status int) string {
+	return fmt.Sprintf("HTTP/1.1 %d %s", status, http.StatusText(status))
+}
+
+// escape экранирует текст для XML
+func escape(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
@@ -0,0 +1,545 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// FieldType определяет, как разбираются значения поля и какие операторы к нему применимы
+type FieldType int
+
+const (
+	// String строковое поле: =, !=, ~ (содержит), !~ (не содержит), in
+	String FieldType = iota
+	// Number числовое поле: =, !=, <, <=, >, >=, in, between
+	Number
+	// Date поле даты: сравнения и between; значения — даты, RFC 3339, now и today со смещением
+	Date
+	// Enum поле с фиксированным набором упорядоченных значений: сравнения идут по порядку значений
+	Enum
+)
+
+// Field описывает поле, доступное в выражении фильтра
+type Field struct {
+	// Column столбец или SQL-выражение, подставляемое в условие
+	Column string
+	Type   FieldType
+	// Nullable разрешает проверки is null и is not null
+	Nullable bool
+	// Values допустимые значения Enum в порядке возрастания
+	Values []string
+}
+
+// Schema перечисляет поля, доступные в выражении; остальные имена отклоняются
+type Schema map[string]Field
+
+// Error описывает ошибку в выражении фильтра с позицией токена (с 1, в символах)
+type Error struct {
+	Position int
+	Token    string
+	Message  string
+}
+
+func (e *Error) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("invalid filter: %s at position %d", e.Message, e.Position)
+	}
+	return fmt.Sprintf("invalid filter: %s at position %d near %q", e.Message, e.Position, e.Token)
+}
+
+// Ограничения на размер выражения
+const (
+	maxLength = 2000
+	maxDepth  = 32
+)
+
+// Compile разбирает выражение фильтра и переводит его в параметризованное условие SQL.
+// Грамматика:
+//
+//	expr       = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" expr ")" | comparison
+//	comparison = field op value
+//	           | field ["not"] "in" "(" value { "," value } ")"
+//	           | field "between" value "and" value
+//	           | field "is" ["not"] "null"
+//	op         = "=" | "!=" | "<" | "<=" | ">" | ">=" | "~" | "!~"
+//
+// Значения подставляются только через параметры, а имена полей — только из schema.
+// now задает момент, относительно которого вычисляются now и today.
+func Compile(input string, schema Schema, now time.Time) (string, []interface{}, error) {
+	if len([]rune(input)) > maxLength {
+		return "", nil, &Error{Position: maxLength + 1, Message: fmt.Sprintf("expression is longer than %d characters", maxLength)}
+	}
+
+	tokens, err := tokenize(input)
+	if err != nil {
+		return "", nil, err
+	}
+
+	p := &parser{tokens: tokens, schema: schema, now: now.UTC()}
+	sql, err := p.parseOr(0)
+	if err != nil {
+		return "", nil, err
+	}
+	if token := p.peek(); token.kind != tokenEOF {
+		return "", nil, p.errorAt(token, "expected \"and\", \"or\" or end of expression")
+	}
+	return sql, p.args, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token представляет лексему выражения
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+// tokenize разбивает выражение на лексемы
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		position := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", position: position})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", position: position})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", position: position})
+			i++
+		case r == '"':
+			// Строка в двойных кавычках; \" и \\ экранируют символы
+			var text strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					text.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				text.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, &Error{Position: position, Message: "unterminated string"}
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text.String(), position: position})
+		case strings.ContainsRune("=!<>~", r):
+			operator := string(r)
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '!' && runes[i+1] == '~')) {
+				operator += string(runes[i+1])
+			}
+			switch operator {
+			case "=", "!=", "<", "<=", ">", ">=", "~", "!~":
+			default:
+				return nil, &Error{Position: position, Token: operator, Message: "unknown operator"}
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, position: position})
+			i += len([]rune(operator))
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune("(),\"=!<>~", runes[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[i:end]), position: position})
+			i = end
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, position: len(runes) + 1})
+	return tokens, nil
+}
+
+// parser разбирает лексемы рекурсивным спуском и сразу строит условие SQL
+type parser struct {
+	tokens []token
+	pos    int
+	schema Schema
+	now    time.Time
+	args   []interface{}
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	token := p.tokens[p.pos]
+	if token.kind != tokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+// isKeyword проверяет, что лексема — ключевое слово (без учета регистра)
+func isKeyword(t token, keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+func (p *parser) errorAt(t token, message string) error {
+	if t.kind == tokenEOF {
+		return &Error{Position: t.position, Message: message + ", got end of expression"}
+	}
+	return &Error{Position: t.position, Token: t.text, Message: message}
+}
+
+func (p *parser) parseOr(depth int) (string, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return "", err
+	}
+	parts := []string{left}
+	for isKeyword(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, right)
+	}
+	if len(parts) == 1 {
+		return left, nil
+	}
+	return "(" + strings.Join(parts, " OR ") + ")", nil
+}
+
+func (p *parser) parseAnd(depth int) (string, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return "", err
+	}
+	parts := []string{left}
+	for isKeyword(p.peek(), "and") {
+		p.next()
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, right)
+	}
+	if len(parts) == 1 {
+		return left, nil
+	}
+	return "(" + strings.Join(parts, " AND ") + ")", nil
+}
+
+func (p *parser) parseUnary(depth int) (string, error) {
+	if depth > maxDepth {
+		return "", p.errorAt(p.peek(), "expression is nested too deeply")
+	}
+
+	t := p.peek()
+	if isKeyword(t, "not") {
+		p.next()
+		condition, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + condition + ")", nil
+	}
+
+	if t.kind == tokenLParen {
+		p.next()
+		condition, err := p.parseOr(depth + 1)
+		if err != nil {
+			return "", err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return "", p.errorAt(closing, "expected \")\"")
+		}
+		return "(" + condition + ")", nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (string, error) {
+	name := p.next()
+	if name.kind != tokenWord {
+		return "", p.errorAt(name, "expected field name")
+	}
+	field, ok := p.schema[strings.ToLower(name.text)]
+	if !ok {
+		return "", p.errorAt(name, "unknown field")
+	}
+
+	operator := p.next()
+	switch {
+	case operator.kind == tokenOperator:
+		return p.compileOperator(field, operator)
+
+	case isKeyword(operator, "in"):
+		return p.parseIn(field, false)
+
+	case isKeyword(operator, "not"):
+		if in := p.next(); !isKeyword(in, "in") {
+			return "", p.errorAt(in, "expected \"in\" after \"not\"")
+		}
+		return p.parseIn(field, true)
+
+	case isKeyword(operator, "is"):
+		negate := false
+		t := p.next()
+		if isKeyword(t, "not") {
+			negate = true
+			t = p.next()
+		}
+		if !isKeyword(t, "null") {
+			return "", p.errorAt(t, "expected \"null\"")
+		}
+		if !field.Nullable {
+			return "", p.errorAt(name, "field cannot be null")
+		}
+		if negate {
+			return field.Column + " IS NOT NULL", nil
+		}
+		return field.Column + " IS NULL", nil
+
+	case isKeyword(operator, "between"):
+		if field.Type == String {
+			return "", p.errorAt(operator, "operator is not supported for text fields")
+		}
+		from, err := p.parseValue(field)
+		if err != nil {
+			return "", err
+		}
+		if and := p.next(); !isKeyword(and, "and") {
+			return "", p.errorAt(and, "expected \"and\" in between")
+		}
+		to, err := p.parseValue(field)
+		if err != nil {
+			return "", err
+		}
+		column := p.orderedColumn(field)
+		p.args = append(p.args, orderedValue(field, from), orderedValue(field, to))
+		return "(" + column + " >= ? AND " + column + " <= ?)", nil
+	}
+
+	return "", p.errorAt(operator, "expected operator")
+}
+
+// compileOperator строит условие для бинарного оператора
+func (p *parser) compileOperator(field Field, operator token) (string, error) {
+	switch operator.text {
+	case "~", "!~":
+		if field.Type != String {
+			return "", p.errorAt(operator, "operator is supported only for text fields")
+		}
+		t := p.next()
+		if t.kind != tokenWord && t.kind != tokenString {
+			return "", p.errorAt(t, "expected value")
+		}
+		p.args = append(p.args, "%"+escapeLike(t.text)+"%")
+		if operator.text == "~" {
+			return field.Column + " LIKE ? ESCAPE '\\'", nil
+		}
+		return "(" + field.Column + " NOT LIKE ? ESCAPE '\\'" + p.orNull(field) + ")", nil
+
+	case "<", "<=", ">", ">=":
+		if field.Type == String {
+			return "", p.errorAt(operator, "operator is not supported for text fields")
+		}
+		value, err := p.parseValue(field)
+		if err != nil {
+			return "", err
+		}
+		p.args = append(p.args, orderedValue(field, value))
+		return p.orderedColumn(field) + " " + operator.text + " ?", nil
+	}
+
+	value, err := p.parseValue(field)
+	if err != nil {
+		return "", err
+	}
+	p.args = append(p.args, value)
+	if operator.text == "=" {
+		return field.Column + " = ?", nil
+	}
+	// Задачи без значения тоже считаются не равными значению
+	return "(" + field.Column + " <> ?" + p.orNull(field) + ")", nil
+}
+
+// parseIn разбирает список значений в скобках
+func (p *parser) parseIn(field Field, negate bool) (string, error) {
+	if open := p.next(); open.kind != tokenLParen {
+		return "", p.errorAt(open, "expected \"(\"")
+	}
+
+	var values []interface{}
+	for {
+		value, err := p.parseValue(field)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, value)
+
+		t := p.next()
+		if t.kind == tokenRParen {
+			break
+		}
+		if t.kind != tokenComma {
+			return "", p.errorAt(t, "expected \",\" or \")\"")
+		}
+	}
+
+	p.args = append(p.args, values)
+	if negate {
+		return "(" + field.Column + " NOT IN ?" + p.orNull(field) + ")", nil
+	}
+	return field.Column + " IN ?", nil
+}
+
+// parseValue разбирает значение в соответствии с типом поля
+func (p *parser) parseValue(field Field) (interface{}, error) {
+	t := p.next()
+	if t.kind != tokenWord && t.kind != tokenString {
+		return nil, p.errorAt(t, "expected value")
+	}
+
+	switch field.Type {
+	case Number:
+		number, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorAt(t, "expected a number")
+		}
+		return number, nil
+
+	case Date:
+		date, err := parseDate(t.text, p.now)
+		if err != nil {
+			return nil, p.errorAt(t, "expected a date (YYYY-MM-DD, RFC 3339, now or today with an offset like now+7d)")
+		}
+		return date, nil
+
+	case Enum:
+		for _, value := range field.Values {
+			if value == t.text {
+				return t.text, nil
+			}
+		}
+		return nil, p.errorAt(t, "expected one of "+strings.Join(field.Values, ", "))
+	}
+
+	return t.text, nil
+}
+
+// orderedColumn возвращает выражение для сравнения на больше/меньше:
+// значения Enum сравниваются по их порядку, а не по алфавиту
+func (p *parser) orderedColumn(field Field) string {
+	if field.Type != Enum {
+		return field.Column
+	}
+	var builder strings.Builder
+	builder.WriteString("CASE " + field.Column)
+	for i, value := range field.Values {
+		builder.WriteString(fmt.Sprintf(" WHEN '%s' THEN %d", strings.ReplaceAll(value, "'", "''"), i+1))
+	}
+	builder.WriteString(" END")
+	return builder.String()
+}
+
+// orderedValue заменяет значение Enum его порядковым номером для сравнения с orderedColumn
+func orderedValue(field Field, value interface{}) interface{} {
+	if field.Type != Enum {
+		return value
+	}
+	for i, candidate := range field.Values {
+		if candidate == value {
+			return i + 1
+		}
+	}
+	return value
+}
+
+// orNull добавляет проверку на NULL для отрицательных условий по необязательным полям
+func (p *parser) orNull(field Field) string {
+	if field.Nullable {
+		return " OR " + field.Column + " IS NULL"
+	}
+	return ""
+}
+
+// escapeLike экранирует спецсимволы LIKE
+func escapeLike(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "%", "\\%")
+	return strings.ReplaceAll(value, "_", "\\_")
+}
+
+// parseDate разбирает дату: YYYY-MM-DD, RFC 3339 или now/today со смещением
+// в часах, днях или неделях (now+7d, today-1w, now+12h)
+func parseDate(text string, now time.Time) (time.Time, error) {
+	lower := strings.ToLower(text)
+	for _, base := range []string{"now", "today"} {
+		if !strings.HasPrefix(lower, base) {
+			continue
+		}
+		date := now
+		if base == "today" {
+			date = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		}
+
+		offset := lower[len(base):]
+		if offset == "" {
+			return date, nil
+		}
+		if len(offset) < 3 || (offset[0] != '+' && offset[0] != '-') {
+			return time.Time{}, fmt.Errorf("invalid offset %q", offset)
+		}
+		amount, err := strconv.Atoi(offset[1 : len(offset)-1])
+		if err != nil {
+			return time.Time{}, err
+		}
+		if offset[0] == '-' {
+			amount = -amount
+		}
+		switch offset[len(offset)-1] {
+		case 'h':
+			return date.Add(time.Duration(amount) * time.Hour), nil
+		case 'd':
+			return date.AddDate(0, 0, amount), nil
+		case 'w':
+			return date.AddDate(0, 0, 7*amount), nil
+		}
+		return time.Time{}, fmt.Errorf("invalid offset unit in %q", offset)
+	}
+
+	if date, err := time.Parse("2006-01-02", text); err == nil {
+		return date, nil
+	}
+	date, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return date.UTC(), nil
+}
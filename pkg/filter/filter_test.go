@@ -0,0 +1,159 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testSchema = Schema{
+	"title":      {Column: "title", Type: String},
+	"estimate":   {Column: "estimate", Type: Number, Nullable: true},
+	"end_date":   {Column: "end_date", Type: Date},
+	"project_id": {Column: "project_id", Type: Number, Nullable: true},
+	"priority":   {Column: "priority", Type: Enum, Values: []string{"low", "medium", "high"}},
+}
+
+var testNow = time.Date(2026, 10, 19, 15, 30, 0, 0, time.UTC)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		sql   string
+		args  []interface{}
+	}{
+		{
+			name:  "comparison",
+			input: `title = "Report"`,
+			sql:   "title = ?",
+			args:  []interface{}{"Report"},
+		},
+		{
+			name:  "precedence",
+			input: "estimate > 1 or estimate < 0 and not title ~ x",
+			sql:   "(estimate > ? OR (estimate < ? AND NOT (title LIKE ? ESCAPE '\\')))",
+			args:  []interface{}{1.0, 0.0, "%x%"},
+		},
+		{
+			name:  "not equal includes null",
+			input: "project_id != 3",
+			sql:   "(project_id <> ? OR project_id IS NULL)",
+			args:  []interface{}{3.0},
+		},
+		{
+			name:  "in list",
+			input: "priority in (low, high)",
+			sql:   "priority IN ?",
+			args:  []interface{}{[]interface{}{"low", "high"}},
+		},
+		{
+			name:  "enum ordering",
+			input: "priority >= medium",
+			sql:   "CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 END >= ?",
+			args:  []interface{}{2},
+		},
+		{
+			name:  "is null",
+			input: "project_id is not null",
+			sql:   "project_id IS NOT NULL",
+		},
+		{
+			name:  "like escapes wildcards",
+			input: `title ~ "50%_off"`,
+			sql:   "title LIKE ? ESCAPE '\\'",
+			args:  []interface{}{`%50\%\_off%`},
+		},
+		{
+			name:  "relative date",
+			input: "end_date < now+7d",
+			sql:   "end_date < ?",
+			args:  []interface{}{testNow.AddDate(0, 0, 7)},
+		},
+		{
+			name:  "today",
+			input: "end_date between today-1w and today",
+			sql:   "(end_date >= ? AND end_date <= ?)",
+			args: []interface{}{
+				time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := Compile(tt.input, testSchema, testNow)
+			if err != nil {
+				t.Fatalf("Compile(%q) error: %v", tt.input, err)
+			}
+			if sql != tt.sql {
+				t.Errorf("sql = %q, want %q", sql, tt.sql)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %#v, want %#v", args, tt.args)
+			}
+		})
+	}
+}
+
+// Даты со смещением передаются в запрос в UTC: время в базе хранится в UTC
+// и сравнивается как текст
+func TestCompileDateWithOffsetIsUTC(t *testing.T) {
+	_, args, err := Compile(`end_date < "2026-10-19T01:00:00+03:00"`, testSchema, testNow)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+	if len(args) != 1 {
+		t.Fatalf("args = %#v, want one date", args)
+	}
+	date, ok := args[0].(time.Time)
+	if !ok {
+		t.Fatalf("arg = %#v, want time.Time", args[0])
+	}
+	want := time.Date(2026, 10, 18, 22, 0, 0, 0, time.UTC)
+	if !date.Equal(want) || date.Location() != time.UTC {
+		t.Errorf("date = %v, want %v", date, want)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		position int
+	}{
+		{input: "unknown = 1", position: 1},
+		{input: "title < x", position: 7},
+		{input: "estimate = abc", position: 12},
+		{input: "title is null", position: 1},
+		{input: "(title = x", position: 11},
+		{input: `title = "open`, position: 9},
+		{input: "end_date > tomorrow", position: 12},
+		{input: "title = x title = y", position: 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, _, err := Compile(tt.input, testSchema, testNow)
+			var filterErr *Error
+			if !errors.As(err, &filterErr) {
+				t.Fatalf("Compile(%q) error = %v, want *Error", tt.input, err)
+			}
+			if filterErr.Position != tt.position {
+				t.Errorf("position = %d, want %d (%v)", filterErr.Position, tt.position, err)
+			}
+		})
+	}
+}
+
+func TestCompileDepthLimit(t *testing.T) {
+	input := ""
+	for i := 0; i <= maxDepth+1; i++ {
+		input += "not "
+	}
+	input += "title = x"
+	if _, _, err := Compile(input, testSchema, testNow); err == nil {
+		t.Fatal("expected an error for deeply nested expression")
+	}
+}
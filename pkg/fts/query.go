@@ -0,0 +1,75 @@
+package fts
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Query строит безопасное выражение MATCH для FTS5 из пользовательского запроса.
+// Слова объединяются условием И; текст в двойных кавычках ищется как фраза,
+// а "*" в конце слова или фразы включает поиск по префиксу.
+// Каждое слово экранируется, поэтому операторы FTS5 во вводе не интерпретируются.
+// Возвращает пустую строку, если в запросе нет ни одного слова.
+func Query(input string) string {
+	var terms []string
+
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		var text string
+		if runes[i] == '"' {
+			// Фраза продолжается до закрывающей кавычки или до конца запроса
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			text = string(runes[i+1 : end])
+			i = end + 1
+		} else {
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '"' {
+				end++
+			}
+			text = string(runes[i:end])
+			i = end
+		}
+
+		prefix := false
+		if strings.HasSuffix(text, "*") {
+			text = strings.TrimRight(text, "*")
+			prefix = true
+		}
+		if i < len(runes) && runes[i] == '*' {
+			for i < len(runes) && runes[i] == '*' {
+				i++
+			}
+			prefix = true
+		}
+
+		if !hasWord(text) {
+			continue
+		}
+
+		term := `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// hasWord проверяет, что текст содержит хотя бы одну букву или цифру
+func hasWord(text string) bool {
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
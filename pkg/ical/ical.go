@@ -0,0 +1,116 @@
+// Package ical формирует и разбирает календари в формате iCalendar (RFC 5545)
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineLength максимальная длина строки в октетах без учета CRLF
+const maxLineLength = 75
+
+// Property представляет свойство компонента: имя, параметры вида VALUE=DATE и значение
+type Property struct {
+	Name   string
+	Params []string
+	Value  string
+}
+
+// Component представляет компонент календаря (VCALENDAR, VEVENT, VTODO и т.д.)
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []Component
+}
+
+// Add добавляет свойство с уже отформатированным значением
+func (c *Component) Add(name, value string, params ...string) {
+	c.Properties = append(c.Properties, Property{Name: name, Params: params, Value: value})
+}
+
+// AddText добавляет текстовое свойство, экранируя значение
+func (c *Component) AddText(name, text string) {
+	c.Add(name, EscapeText(text))
+}
+
+// AddDateTime добавляет момент времени в UTC
+func (c *Component) AddDateTime(name string, t time.Time) {
+	c.Add(name, FormatDateTime(t))
+}
+
+// AddDate добавляет дату без времени
+func (c *Component) AddDate(name string, t time.Time) {
+	c.Add(name, FormatDate(t), "VALUE=DATE")
+}
+
+// Encode записывает компонент с вложенными компонентами, разделяя строки CRLF
+// и перенося строки длиннее 75 октетов
+func Encode(w io.Writer, c *Component) error {
+	writer := bufio.NewWriter(w)
+	encode(writer, c)
+	return writer.Flush()
+}
+
+// encode записывает компонент в буфер
+func encode(w *bufio.Writer, c *Component) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, property := range c.Properties {
+		line := property.Name
+		for _, param := range property.Params {
+			line += ";" + param
+		}
+		writeLine(w, line+":"+property.Value)
+	}
+	for i := range c.Components {
+		encode(w, &c.Components[i])
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+// writeLine записывает строку, перенося ее по границам символов UTF-8:
+// строка продолжения начинается с пробела
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Пробел в начале строки продолжения входит в ее длину
+		limit = maxLineLength - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+// textEscaper экранирует специальные символы текстовых значений
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// EscapeText экранирует текстовое значение: обратную косую черту, точку с запятой,
+// запятую и переводы строк
+func EscapeText(text string) string {
+	return textEscaper.Replace(text)
+}
+
+// FormatDateTime форматирует момент времени в UTC, например 20261020T093000Z
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// FormatDate форматирует дату, например 20261020
+func FormatDate(t time.Time) string {
+	return t.Format("20060102")
+}
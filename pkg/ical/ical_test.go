@@ -0,0 +1,75 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEncode(t *testing.T) {
+	todo := Component{Name: "VTODO"}
+	todo.AddText("SUMMARY", "Купить молоко, хлеб; сыр\nзавтра")
+	todo.AddDate("DTSTART", time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC))
+	todo.AddDateTime("DUE", time.Date(2026, 10, 20, 12, 30, 0, 0, time.FixedZone("", 3*60*60)))
+	calendar := &Component{Name: "VCALENDAR", Components: []Component{todo}}
+	calendar.Add("VERSION", "2.0")
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, calendar); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	want := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"SUMMARY:Купить молоко\\, хлеб\\; сыр\\nзавтра\r\n" +
+		"DTSTART;VALUE=DATE:20261020\r\n" +
+		"DUE:20261020T093000Z\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+	if buf.String() != want {
+		t.Fatalf("encoded = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncodeFoldsLongLines(t *testing.T) {
+	description := strings.Repeat("Длинное описание задачи ", 20)
+	todo := &Component{Name: "VTODO"}
+	todo.AddText("DESCRIPTION", description)
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, todo); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	for _, line := range lines {
+		if len(line) > maxLineLength {
+			t.Errorf("line is %d octets long: %q", len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line splits a character: %q", line)
+		}
+	}
+
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := UnescapeText(parsed.Get("DESCRIPTION").Value); got != description {
+		t.Errorf("description = %q, want %q", got, description)
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	text := "a\\b;c,d\r\ne\rf\ng"
+	escaped := EscapeText(text)
+	if want := `a\\b\;c\,d\ne\nf\ng`; escaped != want {
+		t.Fatalf("escaped = %q, want %q", escaped, want)
+	}
+	if got := UnescapeText(escaped); got != "a\\b;c,d\ne\nf\ng" {
+		t.Errorf("unescaped = %q", got)
+	}
+}
@@ -0,0 +1,214 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Parse разбирает объект iCalendar: склеивает перенесенные строки и собирает
+// вложенные компоненты. Ожидается ровно один компонент верхнего уровня.
+func Parse(r io.Reader) (*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *Component
+	var stack []*Component
+	for _, line := range lines {
+		property, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch property.Name {
+		case "BEGIN":
+			if root != nil && len(stack) == 0 {
+				return nil, errors.New("multiple top-level components")
+			}
+			stack = append(stack, &Component{Name: strings.ToUpper(property.Value)})
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(property.Value) {
+				return nil, fmt.Errorf("unexpected END:%s", property.Value)
+			}
+			component := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				root = component
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, *component)
+			}
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("property %s outside of a component", property.Name)
+			}
+			current := stack[len(stack)-1]
+			current.Properties = append(current.Properties, property)
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("component %s is not closed", stack[len(stack)-1].Name)
+	}
+	if root == nil {
+		return nil, errors.New("no components found")
+	}
+	return root, nil
+}
+
+// unfold читает строки и присоединяет строки продолжения, начинающиеся
+// с пробела или табуляции, к предыдущей строке
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine разбирает строку содержимого вида NAME;PARAM=VALUE:значение.
+// Значения параметров в кавычках могут содержать ; : и ,
+func parseLine(line string) (Property, error) {
+	var property Property
+
+	end := strings.IndexAny(line, ";:")
+	if end <= 0 {
+		return property, fmt.Errorf("invalid content line %q", line)
+	}
+	property.Name = strings.ToUpper(line[:end])
+
+	rest := line[end:]
+	for rest != "" && rest[0] == ';' {
+		rest = rest[1:]
+		i := paramEnd(rest)
+		if i == len(rest) {
+			return property, fmt.Errorf("invalid content line %q", line)
+		}
+		property.Params = append(property.Params, rest[:i])
+		rest = rest[i:]
+	}
+
+	if rest == "" || rest[0] != ':' {
+		return property, fmt.Errorf("invalid content line %q", line)
+	}
+	property.Value = rest[1:]
+	return property, nil
+}
+
+// paramEnd возвращает позицию конца параметра: первую ; или : вне кавычек
+func paramEnd(s string) int {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case ';', ':':
+			if !quoted {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+// Param возвращает значение параметра свойства без кавычек или пустую строку
+func (p *Property) Param(name string) string {
+	for _, param := range p.Params {
+		key, value, ok := strings.Cut(param, "=")
+		if ok && strings.EqualFold(key, name) {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
+
+// Get возвращает первое свойство компонента с именем name или nil
+func (c *Component) Get(name string) *Property {
+	for i := range c.Properties {
+		if c.Properties[i].Name == name {
+			return &c.Properties[i]
+		}
+	}
+	return nil
+}
+
+// GetAll возвращает все свойства компонента с именем name
+func (c *Component) GetAll(name string) []Property {
+	var properties []Property
+	for _, property := range c.Properties {
+		if property.Name == name {
+			properties = append(properties, property)
+		}
+	}
+	return properties
+}
+
+// textUnescaper снимает экранирование текстовых значений
+var textUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\n`, "\n",
+	`\N`, "\n",
+)
+
+// UnescapeText снимает экранирование текстового значения
+func UnescapeText(value string) string {
+	return textUnescaper.Replace(value)
+}
+
+// SplitText разбирает список текстовых значений, разделенных неэкранированными запятыми
+func SplitText(value string) []string {
+	var values []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, UnescapeText(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, UnescapeText(value[start:]))
+}
+
+// ParseTime разбирает значение даты или даты со временем. Дата без времени
+// возвращается полночью UTC с allDay = true. Время с суффиксом Z берется в UTC,
+// с параметром TZID — в указанном поясе, а «плавающее» время и неизвестный
+// пояс — в поясе location.
+func ParseTime(p *Property, location *time.Location) (t time.Time, allDay bool, err error) {
+	value := strings.TrimSpace(p.Value)
+	if strings.EqualFold(p.Param("VALUE"), "DATE") || len(value) == len("20060102") {
+		t, err = time.Parse("20060102", value)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	if tzid := p.Param("TZID"); tzid != "" {
+		if zone, err := time.LoadLocation(tzid); err == nil {
+			location = zone
+		}
+	}
+	t, err = time.ParseInLocation("20060102T150405", value, location)
+	return t, false, err
+}
@@ -0,0 +1,140 @@
+package ical
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	input := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:todo-1@example.com\r\n" +
+		"SUMMARY:Купить молоко\\, хлеб\r\n" +
+		"DESCRIPTION:Первая строка\\nвторая стр\r\n" +
+		" ока\r\n" +
+		"DUE;TZID=\"Europe/Moscow\";X-NOTE=\"a;b:c\":20261020T100000\r\n" +
+		"CATEGORIES:home,shop\\,food\r\n" +
+		"CATEGORIES:work\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	calendar, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if calendar.Name != "VCALENDAR" || len(calendar.Components) != 1 {
+		t.Fatalf("calendar = %+v, want VCALENDAR with one component", calendar)
+	}
+
+	todo := calendar.Components[0]
+	if todo.Name != "VTODO" {
+		t.Fatalf("component = %s, want VTODO", todo.Name)
+	}
+	if got := UnescapeText(todo.Get("SUMMARY").Value); got != "Купить молоко, хлеб" {
+		t.Errorf("summary = %q", got)
+	}
+	if got := UnescapeText(todo.Get("DESCRIPTION").Value); got != "Первая строка\nвторая строка" {
+		t.Errorf("description = %q", got)
+	}
+
+	due := todo.Get("DUE")
+	if due.Param("tzid") != "Europe/Moscow" || due.Param("X-NOTE") != "a;b:c" {
+		t.Errorf("due params = %v", due.Params)
+	}
+
+	var categories []string
+	for _, property := range todo.GetAll("CATEGORIES") {
+		categories = append(categories, SplitText(property.Value)...)
+	}
+	if want := []string{"home", "shop,food", "work"}; !reflect.DeepEqual(categories, want) {
+		t.Errorf("categories = %q, want %q", categories, want)
+	}
+	if todo.Get("DTSTART") != nil {
+		t.Error("DTSTART should be missing")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", "no components found"},
+		{"not closed", "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VTODO\r\n", "not closed"},
+		{"unexpected end", "BEGIN:VCALENDAR\r\nEND:VTODO\r\n", "unexpected END"},
+		{"multiple roots", "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\nBEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", "multiple top-level"},
+		{"property outside", "VERSION:2.0\r\n", "outside of a component"},
+		{"invalid line", "BEGIN:VCALENDAR\r\nSUMMARY\r\nEND:VCALENDAR\r\n", "invalid content line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skipf("time zone database is not available: %v", err)
+	}
+	floating := time.FixedZone("UTC+5", 5*60*60)
+
+	tests := []struct {
+		name       string
+		property   Property
+		want       time.Time
+		wantAllDay bool
+	}{
+		{
+			name:       "date",
+			property:   Property{Params: []string{"VALUE=DATE"}, Value: "20261020"},
+			want:       time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+			wantAllDay: true,
+		},
+		{
+			name:     "utc",
+			property: Property{Value: "20261020T093000Z"},
+			want:     time.Date(2026, 10, 20, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "tzid",
+			property: Property{Params: []string{"TZID=Europe/Moscow"}, Value: "20261020T093000"},
+			want:     time.Date(2026, 10, 20, 9, 30, 0, 0, moscow),
+		},
+		{
+			name:     "unknown tzid",
+			property: Property{Params: []string{"TZID=Mars/Olympus"}, Value: "20261020T093000"},
+			want:     time.Date(2026, 10, 20, 9, 30, 0, 0, floating),
+		},
+		{
+			name:     "floating",
+			property: Property{Value: "20261020T093000"},
+			want:     time.Date(2026, 10, 20, 9, 30, 0, 0, floating),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, allDay, err := ParseTime(&tt.property, floating)
+			if err != nil {
+				t.Fatalf("parse time: %v", err)
+			}
+			if !got.Equal(tt.want) || allDay != tt.wantAllDay {
+				t.Errorf("got %v (all day %v), want %v (all day %v)", got, allDay, tt.want, tt.wantAllDay)
+			}
+		})
+	}
+
+	if _, _, err := ParseTime(&Property{Value: "2026-10-20"}, time.UTC); err == nil {
+		t.Error("expected an error for an invalid date")
+	}
+}
@@ -0,0 +1,148 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// assertJSON сравнивает документы без учета порядка полей
+func assertJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("invalid result %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid expectation %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Fatalf("result = %s, want %s", got, want)
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			name:  "replace with null",
+			doc:   `{"project_id":3,"title":"x"}`,
+			patch: `[{"op":"replace","path":"/project_id","value":null}]`,
+			want:  `{"project_id":null,"title":"x"}`,
+		},
+		{
+			name:  "add null",
+			doc:   `{"title":"x"}`,
+			patch: `[{"op":"add","path":"/parent_id","value":null}]`,
+			want:  `{"parent_id":null,"title":"x"}`,
+		},
+		{
+			name:  "test null",
+			doc:   `{"project_id":null}`,
+			patch: `[{"op":"test","path":"/project_id","value":null},{"op":"replace","path":"/project_id","value":5}]`,
+			want:  `{"project_id":5}`,
+		},
+		{
+			name:  "remove",
+			doc:   `{"title":"x","description":"y"}`,
+			patch: `[{"op":"remove","path":"/description"}]`,
+			want:  `{"title":"x"}`,
+		},
+		{
+			name:  "array insert and append",
+			doc:   `{"tags":["a","c"]}`,
+			patch: `[{"op":"add","path":"/tags/1","value":"b"},{"op":"add","path":"/tags/-","value":"d"}]`,
+			want:  `{"tags":["a","b","c","d"]}`,
+		},
+		{
+			name:  "array remove",
+			doc:   `{"tags":["a","b","c"]}`,
+			patch: `[{"op":"remove","path":"/tags/0"}]`,
+			want:  `{"tags":["b","c"]}`,
+		},
+		{
+			name:  "move",
+			doc:   `{"title":"x","description":"y"}`,
+			patch: `[{"op":"move","from":"/description","path":"/title"}]`,
+			want:  `{"title":"y"}`,
+		},
+		{
+			name:  "copy is independent",
+			doc:   `{"a":{"b":1}}`,
+			patch: `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`,
+			want:  `{"a":{"b":1},"c":{"b":2}}`,
+		},
+		{
+			name:  "escaped pointer",
+			doc:   `{"a/b":1,"m~n":2}`,
+			patch: `[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/m~0n"}]`,
+			want:  `{"a/b":3}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{"missing value", `[{"op":"replace","path":"/title"}]`, "missing value"},
+		{"replace missing path", `[{"op":"replace","path":"/project_id","value":null}]`, "path not found"},
+		{"remove missing path", `[{"op":"remove","path":"/project_id"}]`, "path not found"},
+		{"array index out of range", `[{"op":"add","path":"/tags/5","value":"x"}]`, "out of range"},
+		{"leading zero index", `[{"op":"remove","path":"/tags/01"}]`, "invalid array index"},
+		{"move into child", `[{"op":"move","from":"/tags","path":"/tags/0"}]`, "own child"},
+		{"unknown operation", `[{"op":"merge","path":"/title","value":1}]`, "unknown operation"},
+		{"invalid pointer", `[{"op":"remove","path":"title"}]`, "invalid pointer"},
+	}
+
+	doc := []byte(`{"title":"x","tags":["a"]}`)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Apply(doc, []byte(tt.patch))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyTestFailedLeavesDocument(t *testing.T) {
+	doc := []byte(`{"title":"x"}`)
+	patch := []byte(`[{"op":"replace","path":"/title","value":"y"},{"op":"test","path":"/title","value":null}]`)
+
+	_, err := Apply(doc, patch)
+	if !errors.Is(err, ErrTestFailed) {
+		t.Fatalf("error = %v, want ErrTestFailed", err)
+	}
+	assertJSON(t, doc, `{"title":"x"}`)
+}
+
+func TestMergePatch(t *testing.T) {
+	got, err := MergePatch(
+		[]byte(`{"title":"x","description":"y","custom_fields":{"a":1,"b":2}}`),
+		[]byte(`{"description":null,"custom_fields":{"b":null,"c":3},"priority":"high"}`),
+	)
+	if err != nil {
+		t.Fatalf("merge patch: %v", err)
+	}
+	assertJSON(t, got, `{"title":"x","custom_fields":{"a":1,"c":3},"priority":"high"}`)
+}
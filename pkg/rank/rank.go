@@ -0,0 +1,134 @@
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+// digits алфавит ключей ранжирования (лексикографически упорядочен)
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// ErrInvalidRange возвращается, если левая граница не меньше правой
+var ErrInvalidRange = errors.New("rank: lower bound must be less than upper bound")
+
+// Between возвращает ключ, лежащий строго между a и b.
+// Пустая строка a означает начало списка, пустая строка b — его конец.
+// Ключи сравниваются как обычные строки, поэтому перемещение элемента
+// требует изменения только его собственного ключа.
+func Between(a, b string) (string, error) {
+	if !isValid(a) || !isValid(b) {
+		return "", errors.New("rank: invalid key")
+	}
+	if b != "" && a >= b {
+		return "", ErrInvalidRange
+	}
+	return midpoint(a, b), nil
+}
+
+// After возвращает ключ, следующий за a
+func After(a string) string {
+	key, _ := Between(a, "")
+	return key
+}
+
+// Sequence возвращает n возрастающих ключей, распределенных равномерно
+func Sequence(n int) []string {
+	keys := make([]string, 0, n)
+	if n <= 0 {
+		return keys
+	}
+
+	// Подбираем длину ключа так, чтобы хватило места для n значений
+	width := 1
+	capacity := len(digits) - 1
+	for capacity < n {
+		width++
+		capacity *= len(digits)
+	}
+
+	step := capacity / (n + 1)
+	if step == 0 {
+		step = 1
+	}
+	for i := 1; i <= n; i++ {
+		keys = append(keys, encode(i*step, width))
+	}
+	return keys
+}
+
+// midpoint вычисляет ключ между a и b (b == "" — бесконечность)
+func midpoint(a, b string) string {
+	if b != "" {
+		// Отбрасываем общий префикс, дополняя a нулями
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	lo := 0
+	if a != "" {
+		lo = strings.IndexByte(digits, a[0])
+	}
+	hi := len(digits)
+	if b != "" {
+		hi = strings.IndexByte(digits, b[0])
+	}
+
+	if hi-lo > 1 {
+		return string(digits[(lo+hi)/2])
+	}
+
+	// Соседние цифры: если у b есть продолжение, достаточно его первой цифры
+	if b != "" && len(b) > 1 {
+		return b[:1]
+	}
+
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[lo]) + midpoint(rest, "")
+}
+
+// digitAt возвращает символ a на позиции i, дополняя строку нулями
+func digitAt(a string, i int) byte {
+	if i < len(a) {
+		return a[i]
+	}
+	return digits[0]
+}
+
+// encode кодирует число в ключ фиксированной длины без завершающих нулей
+func encode(value, width int) string {
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buf[i] = digits[value%len(digits)]
+		value /= len(digits)
+	}
+	return strings.TrimRight(string(buf), digits[:1])
+}
+
+// isValid проверяет, что ключ состоит из допустимых символов
+// и не оканчивается нулем (иначе между ключами может не найтись места)
+func isValid(key string) bool {
+	if key == "" {
+		return true
+	}
+	if key[len(key)-1] == digits[0] {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
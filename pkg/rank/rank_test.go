@@ -0,0 +1,134 @@
+package rank
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// assertBetween проверяет, что key — допустимый ключ строго между a и b
+func assertBetween(t *testing.T, a, b, key string) {
+	t.Helper()
+
+	if !isValid(key) || key == "" {
+		t.Fatalf("Between(%q, %q) = %q is not a valid key", a, b, key)
+	}
+	if key <= a || (b != "" && key >= b) {
+		t.Fatalf("Between(%q, %q) = %q is out of range", a, b, key)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{"", ""},
+		{"", "1"},
+		{"", "01"},
+		{"1", ""},
+		{"z", ""},
+		{"zz", ""},
+		{"1", "2"},
+		{"1", "1001"},
+		{"a", "b"},
+		{"az", "b"},
+		{"a", "a1"},
+		{"a1", "a2"},
+	}
+
+	for _, tt := range tests {
+		key, err := Between(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("Between(%q, %q): %v", tt.a, tt.b, err)
+		}
+		assertBetween(t, tt.a, tt.b, key)
+	}
+}
+
+func TestBetweenErrors(t *testing.T) {
+	if _, err := Between("b", "a"); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("Between(b, a) error = %v, want ErrInvalidRange", err)
+	}
+	if _, err := Between("a", "a"); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("Between(a, a) error = %v, want ErrInvalidRange", err)
+	}
+	for _, key := range []string{"a0", "A", "a-b"} {
+		if _, err := Between(key, ""); err == nil {
+			t.Errorf("Between(%q, \"\") should reject the key", key)
+		}
+	}
+}
+
+func TestRepeatedInsertion(t *testing.T) {
+	// Вставка в начало, в конец и все время между одними и теми же соседями
+	first, last := After(""), After("")
+	low, high := "", After("")
+	for i := 0; i < 200; i++ {
+		key, err := Between("", first)
+		if err != nil {
+			t.Fatalf("insert before %q: %v", first, err)
+		}
+		assertBetween(t, "", first, key)
+		first = key
+
+		key = After(last)
+		assertBetween(t, last, "", key)
+		last = key
+
+		key, err = Between(low, high)
+		if err != nil {
+			t.Fatalf("insert between %q and %q: %v", low, high, err)
+		}
+		assertBetween(t, low, high, key)
+		if i%2 == 0 {
+			low = key
+		} else {
+			high = key
+		}
+	}
+}
+
+func TestRandomInsertionKeepsOrder(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	keys := Sequence(5)
+	for i := 0; i < 1000; i++ {
+		position := random.Intn(len(keys) + 1)
+		var a, b string
+		if position > 0 {
+			a = keys[position-1]
+		}
+		if position < len(keys) {
+			b = keys[position]
+		}
+
+		key, err := Between(a, b)
+		if err != nil {
+			t.Fatalf("Between(%q, %q): %v", a, b, err)
+		}
+		assertBetween(t, a, b, key)
+
+		keys = append(keys, "")
+		copy(keys[position+1:], keys[position:])
+		keys[position] = key
+	}
+
+	if !sort.StringsAreSorted(keys) {
+		t.Error("keys are not sorted after insertions")
+	}
+}
+
+func TestSequence(t *testing.T) {
+	for _, n := range []int{0, 1, 35, 36, 1000} {
+		keys := Sequence(n)
+		if len(keys) != n {
+			t.Fatalf("Sequence(%d) returned %d keys", n, len(keys))
+		}
+		for i, key := range keys {
+			if !isValid(key) || key == "" {
+				t.Fatalf("Sequence(%d)[%d] = %q is not a valid key", n, i, key)
+			}
+			if i > 0 && keys[i-1] >= key {
+				t.Fatalf("Sequence(%d) is not increasing at %d: %q >= %q", n, i, keys[i-1], key)
+			}
+		}
+	}
+}
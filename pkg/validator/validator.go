@@ -27,7 +27,7 @@ func IsValidUsername(username string) bool {
 	if len(username) < 3 || len(username) > 50 {
 		return false
 	}
-	
+
 	// Только буквы, цифры и подчеркивания
 	usernameRegex := regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
 	return usernameRegex.MatchString(username)
@@ -44,18 +44,20 @@ func IsValidDateRange(startDate, endDate time.Time) bool {
 	return !endDate.Before(startDate)
 }
 
-// IsValidTaskStatus проверяет валидность статуса задачи
-func IsValidTaskStatus(status string) bool {
-	validStatuses := []string{"pending", "in_progress", "completed"}
-	for _, validStatus := range validStatuses {
-		if status == validStatus {
-			return true
-		}
-	}
-	return false
+// IsValidStatusKey проверяет формат ключа статуса рабочего процесса.
+// Набор допустимых статусов задается самим процессом, здесь проверяется только запись ключа.
+func IsValidStatusKey(key string) bool {
+	statusKeyRegex := regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+	return statusKeyRegex.MatchString(key)
+}
+
+// IsValidFieldKey проверяет формат ключа пользовательского поля
+func IsValidFieldKey(key string) bool {
+	fieldKeyRegex := regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+	return fieldKeyRegex.MatchString(key)
 }
 
 // SanitizeString очищает строку от лишних пробелов
 func SanitizeString(str string) string {
 	return strings.TrimSpace(str)
-} 
\ No newline at end of file
+}
@@ -0,0 +1,143 @@
+// Package workdays считает рабочие дни по рабочей неделе и списку нерабочих дат
+package workdays
+
+import (
+	"time"
+)
+
+// dateFormat формат нерабочих дат
+const dateFormat = "2006-01-02"
+
+// Calendar описывает рабочие дни: дни недели и отдельные нерабочие даты.
+// Дата момента времени берется в его часовом поясе.
+type Calendar struct {
+	// Weekdays рабочие дни недели по индексу time.Weekday
+	Weekdays [7]bool
+	// Holidays нерабочие даты в формате YYYY-MM-DD
+	Holidays map[string]bool
+	// LoadHolidays, если задана, загружает нерабочие даты года при первом обращении к нему;
+	// так календарь читает только годы, которые нужны расчету
+	LoadHolidays func(year int) ([]string, error)
+
+	loaded map[int]bool
+	err    error
+}
+
+// Err возвращает первую ошибку загрузки нерабочих дат. После ошибки даты года
+// считаются рабочими, поэтому результат расчета нужно отбросить.
+func (c *Calendar) Err() error {
+	return c.err
+}
+
+// loadYear загружает нерабочие даты года, если они еще не загружены
+func (c *Calendar) loadYear(year int) {
+	if c.LoadHolidays == nil || c.err != nil || c.loaded[year] {
+		return
+	}
+	dates, err := c.LoadHolidays(year)
+	if err != nil {
+		c.err = err
+		return
+	}
+	if c.loaded == nil {
+		c.loaded = make(map[int]bool)
+	}
+	if c.Holidays == nil {
+		c.Holidays = make(map[string]bool, len(dates))
+	}
+	c.loaded[year] = true
+	for _, date := range dates {
+		c.Holidays[date] = true
+	}
+}
+
+// Standard возвращает календарь с рабочими днями с понедельника по пятницу
+func Standard() *Calendar {
+	return &Calendar{
+		Weekdays: [7]bool{time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true},
+	}
+}
+
+// IsWorkingDay проверяет, что день момента t рабочий
+func (c *Calendar) IsWorkingDay(t time.Time) bool {
+	if !c.Weekdays[t.Weekday()] {
+		return false
+	}
+	c.loadYear(t.Year())
+	return !c.Holidays[t.Format(dateFormat)]
+}
+
+// hasWorkingDays проверяет, что в неделе есть хотя бы один рабочий день:
+// иначе поиск рабочего дня не закончится
+func (c *Calendar) hasWorkingDays() bool {
+	for _, working := range c.Weekdays {
+		if working {
+			return true
+		}
+	}
+	return false
+}
+
+// NextWorkingDay возвращает t, если день рабочий, иначе тот же момент суток
+// ближайшего следующего рабочего дня
+func (c *Calendar) NextWorkingDay(t time.Time) time.Time {
+	if !c.hasWorkingDays() {
+		return t
+	}
+	for !c.IsWorkingDay(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// AddWorkingDays сдвигает t на n рабочих дней вперед (или назад при отрицательном n),
+// сохраняя момент суток. Нерабочий день t не считается.
+func (c *Calendar) AddWorkingDays(t time.Time, n int) time.Time {
+	if !c.hasWorkingDays() {
+		return t.AddDate(0, 0, n)
+	}
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if c.IsWorkingDay(t) {
+			n--
+		}
+	}
+	return t
+}
+
+// Count возвращает число рабочих дней с даты from по дату to включительно
+func (c *Calendar) Count(from, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, from.Location())
+
+	count := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if c.IsWorkingDay(day) {
+			count++
+		}
+	}
+	return count
+}
+
+// AddWorkingTime прибавляет к t длительность d, считая только время рабочих дней.
+// Дни берутся в часовом поясе t.
+func (c *Calendar) AddWorkingTime(t time.Time, d time.Duration) time.Time {
+	if !c.hasWorkingDays() || d <= 0 {
+		return t.Add(d)
+	}
+	for {
+		next := time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		if c.IsWorkingDay(t) {
+			left := next.Sub(t)
+			if d <= left {
+				return t.Add(d)
+			}
+			d -= left
+		}
+		t = next
+	}
+}
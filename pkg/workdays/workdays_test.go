@@ -0,0 +1,138 @@
+package workdays
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// testCalendar стандартная неделя с праздником во вторник 20 октября 2026 года
+func testCalendar() *Calendar {
+	calendar := Standard()
+	calendar.Holidays = map[string]bool{"2026-10-20": true}
+	return calendar
+}
+
+func TestIsWorkingDay(t *testing.T) {
+	calendar := testCalendar()
+	tests := []struct {
+		day  int
+		want bool
+	}{
+		{19, true},  // понедельник
+		{20, false}, // праздник
+		{23, true},  // пятница
+		{24, false}, // суббота
+		{25, false}, // воскресенье
+	}
+	for _, tt := range tests {
+		if got := calendar.IsWorkingDay(time.Date(2026, 10, tt.day, 12, 0, 0, 0, time.UTC)); got != tt.want {
+			t.Errorf("IsWorkingDay(%d October) = %v, want %v", tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestNextWorkingDay(t *testing.T) {
+	calendar := testCalendar()
+	saturday := time.Date(2026, 10, 24, 9, 30, 0, 0, time.UTC)
+	if got, want := calendar.NextWorkingDay(saturday), time.Date(2026, 10, 26, 9, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("NextWorkingDay(Saturday) = %v, want %v", got, want)
+	}
+	monday := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	if got := calendar.NextWorkingDay(monday); !got.Equal(monday) {
+		t.Errorf("NextWorkingDay(Monday) = %v, want the same moment", got)
+	}
+
+	// Без рабочих дней поиск не зацикливается
+	if got := (&Calendar{}).NextWorkingDay(saturday); !got.Equal(saturday) {
+		t.Errorf("NextWorkingDay without working days = %v, want %v", got, saturday)
+	}
+}
+
+func TestAddWorkingDays(t *testing.T) {
+	calendar := testCalendar()
+	monday := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		n    int
+		want time.Time
+	}{
+		{0, monday},
+		{1, time.Date(2026, 10, 21, 9, 0, 0, 0, time.UTC)},
+		{4, time.Date(2026, 10, 26, 9, 0, 0, 0, time.UTC)},
+		{-1, time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := calendar.AddWorkingDays(monday, tt.n); !got.Equal(tt.want) {
+			t.Errorf("AddWorkingDays(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	calendar := testCalendar()
+	from := time.Date(2026, 10, 19, 18, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 26, 1, 0, 0, 0, time.UTC)
+	// 19, 21, 22, 23 и 26 октября
+	if got := calendar.Count(from, to); got != 5 {
+		t.Errorf("Count = %d, want 5", got)
+	}
+	if got := calendar.Count(to, from); got != 0 {
+		t.Errorf("Count of a reversed range = %d, want 0", got)
+	}
+}
+
+func TestAddWorkingTime(t *testing.T) {
+	calendar := testCalendar()
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	// Пятница 20:00: 4 часа пятницы, затем выходные, затем 4 часа понедельника
+	friday := time.Date(2026, 10, 23, 20, 0, 0, 0, moscow)
+	if got, want := calendar.AddWorkingTime(friday, 8*time.Hour), time.Date(2026, 10, 26, 4, 0, 0, 0, moscow); !got.Equal(want) {
+		t.Errorf("AddWorkingTime(Friday) = %v, want %v", got, want)
+	}
+
+	// Понедельник 12:00 + 24 часа: праздник во вторник пропускается
+	monday := time.Date(2026, 10, 19, 12, 0, 0, 0, moscow)
+	if got, want := calendar.AddWorkingTime(monday, 24*time.Hour), time.Date(2026, 10, 21, 12, 0, 0, 0, moscow); !got.Equal(want) {
+		t.Errorf("AddWorkingTime(Monday) = %v, want %v", got, want)
+	}
+}
+
+func TestLoadHolidaysLoadsEachYearOnce(t *testing.T) {
+	var years []int
+	calendar := Standard()
+	calendar.LoadHolidays = func(year int) ([]string, error) {
+		years = append(years, year)
+		return []string{"2026-12-31", "2027-01-01"}, nil
+	}
+
+	// Среда 30 декабря 2026 + 2 рабочих дня: 31.12 и 1.01 — праздники, 2–3.01 — выходные
+	start := time.Date(2026, 12, 30, 9, 0, 0, 0, time.UTC)
+	if got, want := calendar.AddWorkingDays(start, 2), time.Date(2027, 1, 5, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("AddWorkingDays = %v, want %v", got, want)
+	}
+	calendar.IsWorkingDay(start)
+	if len(years) != 2 || years[0] != 2026 || years[1] != 2027 {
+		t.Errorf("loaded years = %v, want [2026 2027]", years)
+	}
+	if err := calendar.Err(); err != nil {
+		t.Errorf("Err = %v", err)
+	}
+}
+
+func TestLoadHolidaysError(t *testing.T) {
+	failure := errors.New("database is locked")
+	calendar := Standard()
+	calendar.LoadHolidays = func(int) ([]string, error) {
+		return nil, failure
+	}
+
+	// Пятница рабочая, потому что нерабочие даты не загрузились; ошибка сохраняется
+	friday := time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)
+	if !calendar.IsWorkingDay(friday) {
+		t.Error("a weekday should stay working without holidays")
+	}
+	if !errors.Is(calendar.Err(), failure) {
+		t.Errorf("Err = %v, want %v", calendar.Err(), failure)
+	}
+}